@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"github.com/frianlh/pokedex-api/configs"
 	"github.com/frianlh/pokedex-api/routers"
+	"github.com/frianlh/pokedex-api/schedulers"
 	"log"
 )
 
@@ -19,6 +21,11 @@ func main() {
 		return
 	}
 
+	// background task
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	schedulers.SetupScheduler(ctx, config)
+
 	// route
 	r := routers.SetupRoute(config)
 	err = r.Listen(fmt.Sprintf(":%d", config.PortApi))
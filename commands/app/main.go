@@ -0,0 +1,297 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"github.com/frianlh/pokedex-api/configs"
+	"github.com/frianlh/pokedex-api/libs/backup"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/importer"
+	"github.com/frianlh/pokedex-api/libs/pokeapi"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/frianlh/pokedex-api/usecase"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	ImageGC                  = "image_gc"
+	ImagePlaceholderBackfill = "image_placeholder_backfill"
+	TrashPurge               = "trash_purge"
+	MonsterImport            = "monster_import"
+	Backup                   = "backup"
+	Restore                  = "restore"
+	PokeAPIImport            = "pokeapi_import"
+)
+
+func main() {
+	log.SetFlags(log.Lshortfile | log.Lmicroseconds)
+
+	// configuration
+	newConfig := configs.NewConfig()
+	config, err := newConfig.Read()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+
+	// command argument
+	commandType := flag.String("type", "no-type", "type your command")
+	dryRun := flag.Bool("dry_run", true, "report only, without changing anything")
+	gracePeriod := flag.Duration("grace_period", config.ImageGCConfig.GracePeriod, "minimum age of unreferenced image to be deleted")
+	batchSize := flag.Int("batch_size", 100, "number of row processed per batch")
+	importFile := flag.String("file", "", "path of CSV or JSON import file")
+	importImages := flag.String("images", "", "path of zip image archive referenced by import file")
+	archivePath := flag.String("archive", "", "path of backup archive")
+	datasetDir := flag.String("dir", "", "path of PokeAPI dataset directory, CSV or JSON")
+	strategy := flag.String("strategy", constants.RestoreStrategyMerge, "restore strategy, replace or merge")
+	retentionDays := flag.Int("retention_days", config.TrashConfig.RetentionDays, "minimum days since soft deleted of monster to be purged")
+	flag.Parse()
+
+	if *commandType == ImageGC {
+		imageGC(config, *dryRun, *gracePeriod)
+	} else if *commandType == ImagePlaceholderBackfill {
+		imagePlaceholderBackfill(config, *batchSize)
+	} else if *commandType == TrashPurge {
+		trashPurge(config, *retentionDays)
+	} else if *commandType == MonsterImport {
+		monsterImport(config, *importFile, *importImages, *dryRun)
+	} else if *commandType == Backup {
+		backupArchive(config, *archivePath)
+	} else if *commandType == Restore {
+		restoreArchive(config, *archivePath, *strategy, *dryRun)
+	} else if *commandType == PokeAPIImport {
+		pokeAPIImport(config, *datasetDir, *dryRun)
+	} else {
+		log.Println("use arguments to run the command you need")
+	}
+}
+
+// imageGC is
+func imageGC(config *configs.Config, dryRun bool, gracePeriod time.Duration) {
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	uImageGC := usecase.NewImageGCUseCase(config.TimeoutCtx, rMonster, rMonsterImage, rMonsterRevision)
+
+	res, _, resMessage, err := uImageGC.RunImageGC(context.Background(), model.ImageGCReq{
+		GracePeriod: gracePeriod,
+		DryRun:      dryRun,
+	})
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+	printJSON(res)
+
+	log.Println(resMessage)
+}
+
+// imagePlaceholderBackfill is
+func imagePlaceholderBackfill(config *configs.Config, batchSize int) {
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	uMonsterImage := usecase.NewMonsterImageUseCase(config.TimeoutCtx, uploader.ImageURL{}, rMonster, rMonsterImage, rMonsterRevision)
+
+	res, _, resMessage, err := uMonsterImage.BackfillImagePlaceholder(context.Background(), batchSize)
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+	printJSON(res)
+
+	log.Println(resMessage)
+}
+
+// trashPurge is
+func trashPurge(config *configs.Config, retentionDays int) {
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	uMonsterTrash := usecase.NewMonsterTrashUseCase(config.TimeoutCtx, rMonster, rMonsterImage, rMonsterRevision)
+
+	res, _, resMessage, err := uMonsterTrash.PurgeExpiredMonster(context.Background(), retentionDays)
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+	printJSON(res)
+
+	log.Println(resMessage)
+}
+
+// monsterImport is
+func monsterImport(config *configs.Config, filePath, imagesPath string, dryRun bool) {
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	rMCategory := repository.NewMonsterCategory(config.PostgresConfig.DbConn)
+	rMType := repository.NewMTypeRepository(config.PostgresConfig.DbConn)
+	uMonster := usecase.NewMonsterUseCase(config.TimeoutCtx, uploader.ImageURL{}, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterImport := usecase.NewMonsterImportUseCase(config.TimeoutCtx, uMonster, rMonster, rMCategory, rMType)
+
+	// parse import file
+	format, err := importer.FormatOf(filePath)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	file, err := os.Open(filePath)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	defer file.Close()
+	rows, err := importer.Parse(format, file, constants.ImportTypeSeparator)
+	if err != nil {
+		log.Fatal("failed to parse import file: ", err)
+		return
+	}
+
+	// open image archive
+	var archive *importer.Archive
+	if imagesPath != "" {
+		zipFile, err := os.Open(imagesPath)
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		defer zipFile.Close()
+		zipInfo, err := zipFile.Stat()
+		if err != nil {
+			log.Fatal(err)
+			return
+		}
+		archive, err = importer.OpenArchive(zipFile, zipInfo.Size(), constants.ImportMaxImageSize)
+		if err != nil {
+			log.Fatal("failed to read image archive: ", err)
+			return
+		}
+	}
+
+	res, _, resMessage, err := uMonsterImport.ImportMonster(context.Background(), rows, archive, dryRun)
+	printJSON(res)
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+
+	log.Println(resMessage)
+}
+
+// backupArchive is
+func backupArchive(config *configs.Config, archivePath string) {
+	rBackup := repository.NewBackupRepository(config.PostgresConfig.DbConn)
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	uBackup := usecase.NewBackupUseCase(constants.BackupTimeout, rBackup, rMonster)
+
+	if archivePath == "" {
+		archivePath = fmt.Sprintf("pokedex_backup_%s.zip", time.Now().UTC().Format("20060102150405"))
+	}
+
+	// archive is written to temporary file first, so failed backup never leaves partial archive
+	file, err := os.CreateTemp(filepath.Dir(archivePath), ".backup_*")
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	defer os.Remove(file.Name())
+
+	res, _, resMessage, err := uBackup.Backup(context.Background(), file)
+	if err != nil {
+		file.Close()
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+	err = file.Close()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	err = os.Rename(file.Name(), archivePath)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	printJSON(res)
+
+	log.Println(resMessage+":", archivePath)
+}
+
+// restoreArchive is
+func restoreArchive(config *configs.Config, archivePath, strategy string, dryRun bool) {
+	rBackup := repository.NewBackupRepository(config.PostgresConfig.DbConn)
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	uBackup := usecase.NewBackupUseCase(constants.BackupTimeout, rBackup, rMonster)
+
+	// open backup archive
+	file, err := os.Open(archivePath)
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	defer file.Close()
+	fileInfo, err := file.Stat()
+	if err != nil {
+		log.Fatal(err)
+		return
+	}
+	reader, err := backup.OpenReader(file, fileInfo.Size())
+	if err != nil {
+		log.Fatal("backup archive is invalid: ", err)
+		return
+	}
+
+	res, _, resMessage, err := uBackup.Restore(context.Background(), reader, strategy, dryRun)
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+	printJSON(res)
+
+	log.Println(resMessage)
+}
+
+// pokeAPIImport is
+func pokeAPIImport(config *configs.Config, datasetDir string, dryRun bool) {
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	rMCategory := repository.NewMonsterCategory(config.PostgresConfig.DbConn)
+	rMType := repository.NewMTypeRepository(config.PostgresConfig.DbConn)
+	uMonster := usecase.NewMonsterUseCase(config.TimeoutCtx, uploader.ImageURL{}, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterPokeAPI := usecase.NewMonsterPokeAPIUseCase(constants.PokeAPIImportTimeout, uMonster, rMonster, rMCategory, rMType)
+
+	// load PokeAPI dataset
+	pokemons, err := pokeapi.Load(datasetDir)
+	if err != nil {
+		log.Fatal("failed to load pokeapi dataset: ", err)
+		return
+	}
+
+	res, _, resMessage, err := uMonsterPokeAPI.ImportPokeAPI(context.Background(), pokemons, dryRun)
+	printJSON(res)
+	if err != nil {
+		log.Fatal(resMessage, ": ", err)
+		return
+	}
+
+	log.Println(resMessage)
+}
+
+// printJSON is
+func printJSON(data interface{}) {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(data)
+	if err != nil {
+		log.Println(err)
+	}
+}
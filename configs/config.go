@@ -25,6 +25,10 @@ type Config struct {
 	PostgresConfig postgresConfig
 	JWTKey         string
 	TimeoutCtx     time.Duration
+	ImageGCConfig  imageGCConfig
+	ImageConfig    imageConfig
+	CacheConfig    cacheConfig
+	TrashConfig    trashConfig
 }
 
 type postgresConfig struct {
@@ -38,6 +42,30 @@ type postgresConfig struct {
 	MaxIdleConn int
 }
 
+type imageGCConfig struct {
+	Interval    time.Duration
+	GracePeriod time.Duration
+	DryRun      bool
+}
+
+type imageConfig struct {
+	SigningKey  string
+	URLTTL      time.Duration
+	CacheMaxAge time.Duration
+}
+
+type trashConfig struct {
+	PurgeInterval time.Duration
+	RetentionDays int
+}
+
+type cacheConfig struct {
+	MonsterList     string
+	MonsterDetail   string
+	MonsterType     string
+	MonsterCategory string
+}
+
 // NewConfig is
 func NewConfig() ConfigInterface {
 	timeoutCtx := time.Duration(30) * time.Second
@@ -138,5 +166,92 @@ func (c Config) Read() (config *Config, err error) {
 	}
 	c.JWTKey = jwtKeyStr
 
+	// image config, signing key fallback to JWT key when it is not set
+	c.ImageConfig.SigningKey = jwtKeyStr
+	imageSigningKeyStr := os.Getenv("IMAGE_SIGNING_KEY")
+	if imageSigningKeyStr != "" {
+		c.ImageConfig.SigningKey = imageSigningKeyStr
+	}
+	c.ImageConfig.URLTTL = 15 * time.Minute
+	imageURLTTLStr := os.Getenv("IMAGE_URL_TTL")
+	if imageURLTTLStr != "" {
+		imageURLTTL, err := time.ParseDuration(imageURLTTLStr)
+		if err != nil {
+			return nil, errors.New(constants.ImageInvalidEnv)
+		}
+		c.ImageConfig.URLTTL = imageURLTTL
+	}
+	c.ImageConfig.CacheMaxAge = 24 * time.Hour
+	imageCacheMaxAgeStr := os.Getenv("IMAGE_CACHE_MAX_AGE")
+	if imageCacheMaxAgeStr != "" {
+		imageCacheMaxAge, err := time.ParseDuration(imageCacheMaxAgeStr)
+		if err != nil {
+			return nil, errors.New(constants.ImageInvalidEnv)
+		}
+		c.ImageConfig.CacheMaxAge = imageCacheMaxAge
+	}
+
+	// cache config, Cache-Control header of read endpoint
+	c.CacheConfig.MonsterList = cacheControlEnv("CACHE_CONTROL_MONSTER_LIST", "no-cache")
+	c.CacheConfig.MonsterDetail = cacheControlEnv("CACHE_CONTROL_MONSTER_DETAIL", "no-cache")
+	c.CacheConfig.MonsterType = cacheControlEnv("CACHE_CONTROL_MONSTER_TYPE", "public, max-age=3600")
+	c.CacheConfig.MonsterCategory = cacheControlEnv("CACHE_CONTROL_MONSTER_CATEGORY", "public, max-age=3600")
+
+	// image garbage collector config, scheduler is disabled when interval is empty
+	imageGCIntervalStr := os.Getenv("IMAGE_GC_INTERVAL")
+	if imageGCIntervalStr != "" {
+		imageGCInterval, err := time.ParseDuration(imageGCIntervalStr)
+		if err != nil {
+			return nil, errors.New(constants.ImageGCInvalidEnv)
+		}
+		c.ImageGCConfig.Interval = imageGCInterval
+	}
+	c.ImageGCConfig.GracePeriod = 24 * time.Hour
+	imageGCGracePeriodStr := os.Getenv("IMAGE_GC_GRACE_PERIOD")
+	if imageGCGracePeriodStr != "" {
+		imageGCGracePeriod, err := time.ParseDuration(imageGCGracePeriodStr)
+		if err != nil {
+			return nil, errors.New(constants.ImageGCInvalidEnv)
+		}
+		c.ImageGCConfig.GracePeriod = imageGCGracePeriod
+	}
+	c.ImageGCConfig.DryRun = true
+	imageGCDryRunStr := os.Getenv("IMAGE_GC_DRY_RUN")
+	if imageGCDryRunStr != "" {
+		imageGCDryRun, err := strconv.ParseBool(imageGCDryRunStr)
+		if err != nil {
+			return nil, errors.New(constants.ImageGCInvalidEnv)
+		}
+		c.ImageGCConfig.DryRun = imageGCDryRun
+	}
+
+	// trash config, soft deleted monster older than retention days is purged, scheduler is disabled when interval is empty
+	trashPurgeIntervalStr := os.Getenv("TRASH_PURGE_INTERVAL")
+	if trashPurgeIntervalStr != "" {
+		trashPurgeInterval, err := time.ParseDuration(trashPurgeIntervalStr)
+		if err != nil {
+			return nil, errors.New(constants.TrashInvalidEnv)
+		}
+		c.TrashConfig.PurgeInterval = trashPurgeInterval
+	}
+	c.TrashConfig.RetentionDays = 30
+	trashRetentionDaysStr := os.Getenv("TRASH_RETENTION_DAYS")
+	if trashRetentionDaysStr != "" {
+		trashRetentionDays, err := strconv.Atoi(trashRetentionDaysStr)
+		if err != nil || trashRetentionDays < 0 {
+			return nil, errors.New(constants.TrashInvalidEnv)
+		}
+		c.TrashConfig.RetentionDays = trashRetentionDays
+	}
+
 	return &c, nil
 }
+
+// cacheControlEnv is
+func cacheControlEnv(key, defaultValue string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
@@ -7,7 +7,8 @@ func CorsConfig() cors.Config {
 	return cors.Config{
 		AllowOrigins:     "*",
 		AllowMethods:     "POST, GET, HEAD, PUT, DELETE, PATCH, OPTIONS",
-		AllowHeaders:     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Origin, Cache-Control, X-Requested-With",
+		AllowHeaders:     "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, Accept, Origin, Cache-Control, X-Requested-With, If-Match, If-None-Match, If-Modified-Since",
+		ExposeHeaders:    "ETag, Last-Modified",
 		AllowCredentials: true,
 	}
 }
@@ -1,7 +1,15 @@
 package delivery
 
 import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/exporter"
+	"github.com/frianlh/pokedex-api/libs/filter"
 	"github.com/frianlh/pokedex-api/libs/form"
 	"github.com/frianlh/pokedex-api/libs/response"
 	"github.com/frianlh/pokedex-api/libs/uploader"
@@ -10,10 +18,13 @@ import (
 	"github.com/frianlh/pokedex-api/usecase"
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
+	"math"
 	"mime/multipart"
 	"net/http"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type monsterHandler struct {
@@ -77,6 +88,13 @@ func (hMonster *monsterHandler) CreateMonster(ctx *fiber.Ctx) error {
 			return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to save image", err.Error())
 		}
 		req.ImageName = imageName
+
+		// compute image placeholder, image which cannot be decoded is rejected
+		req.BlurHash, req.DominantColor, err = uploader.ImagePlaceholder(imageName)
+		if err != nil {
+			_ = uploader.DeleteImage(imageName)
+			return response.ErrorRes(ctx, http.StatusBadRequest, "image cannot be decoded", err.Error())
+		}
 	}
 
 	// create monster
@@ -90,8 +108,7 @@ func (hMonster *monsterHandler) CreateMonster(ctx *fiber.Ctx) error {
 
 // GetMonsterById is handler to get monster by id
 func (hMonster *monsterHandler) GetMonsterById(ctx *fiber.Ctx) error {
-	id := form.SQLInjector(ctx.Params("id"))
-	_, err := uuid.Parse(id)
+	id, err := form.ParseUUID("id", ctx.Params("id"))
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
 	}
@@ -101,34 +118,124 @@ func (hMonster *monsterHandler) GetMonsterById(ctx *fiber.Ctx) error {
 	if err != nil {
 		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
 	}
+	// entity tag with fingerprint of monster category and monster type is set by conditional request, version is the fallback
+	if len(ctx.Response().Header.Peek(fiber.HeaderETag)) == 0 {
+		ctx.Set(fiber.HeaderETag, response.VersionETag(res.Version))
+	}
 
 	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
 }
 
 // GetListMonster is handler to get list monster
 func (hMonster *monsterHandler) GetListMonster(ctx *fiber.Ctx) error {
-	sortBy := form.SQLInjector(ctx.Query("sort_by", "created_at"))
-	orderBy := form.SQLInjector(strings.ToUpper(ctx.Query("order_by", "ASC")))
-	name := form.SQLInjector(ctx.Query("name", ""))
-	isCaught := form.SQLInjector(ctx.Query("is_caught", ""))
+	queryParams, resMessage, err := hMonster.getMonsterListQuery(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, resMessage, err.Error())
+	}
+	facets, err := hMonster.getFacets(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "facets not valid", err.Error())
+	}
 
-	// get monster type
-	monsterTypeId, err := hMonster.getMonsterTypeId(ctx)
+	// find list monster, only field and include asked for is kept
+	resMonster, resCode, resMessage, err := hMonster.monsterUseCase.GetListMonster(ctx.Context(), queryParams)
 	if err != nil {
-		return response.ErrorRes(ctx, http.StatusBadRequest, "monster type must be uuid", err.Error())
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+	res, err := response.SparseFields(resMonster, usecase.MonsterListKeys(queryParams))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to get list monster", err.Error())
+	}
+	if len(facets) == 0 {
+		return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+	}
+
+	// count list monster per facet value
+	resFacet, resCode, resFacetMessage, err := hMonster.monsterUseCase.GetMonsterFacet(ctx.Context(), queryParams, facets)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resFacetMessage, err.Error())
+	}
+
+	return response.FacetRes(ctx, http.StatusOK, resMessage, "", resFacet, res)
+}
+
+// ExportMonster is handler to export list monster as CSV, NDJSON, or JSON file, response body is streamed from database cursor
+// query is validated before streaming starts, since status code cannot be changed once the first row is sent
+func (hMonster *monsterHandler) ExportMonster(ctx *fiber.Ctx) error {
+	format := strings.ToLower(ctx.Query("format", exporter.FormatCSV))
+	contentType, err := exporter.ContentType(format)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "export format not valid", err.Error())
+	}
+	queryParams, resMessage, err := hMonster.getMonsterListQuery(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, resMessage, err.Error())
+	}
+
+	// find export of list monster
+	export, resCode, resMessage, err := hMonster.monsterUseCase.ExportMonster(queryParams, format)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	// stream export file, request context is not usable after handler returns so export has its own context which is
+	// canceled once client is gone, so database cursor is not kept open until export timeout
+	ctx.Set(fiber.HeaderContentType, contentType)
+	ctx.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="monster_export.%s"`, format))
+	ctx.Status(http.StatusOK)
+	ctx.Context().SetBodyStreamWriter(func(writer *bufio.Writer) {
+		exportCtx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// status is already sent, so failed export only ends the stream early
+		streamWriter := &cancelWriter{writer: writer, cancel: cancel}
+		err := export(exportCtx, streamWriter)
+		if err == nil {
+			_ = streamWriter.Flush()
+		}
+	})
+
+	return nil
+}
+
+// SimilarMonster is handler to get other monster ranked by similarity of stat, type, and category
+func (hMonster *monsterHandler) SimilarMonster(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit", strconv.Itoa(constants.SimilarDefaultLimit)))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "limit not valid", err.Error())
+	}
+	// exclude is filter expression of monster to leave out, e.g. exclude=caught OR type:fire
+	exclude, err := form.ParseText("exclude", ctx.Query("exclude", ""), filter.MaxLength)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "exclude expression not valid", err.Error())
 	}
 
-	// query params request
-	queryParams := model.MonsterQueryReq{
-		SortBy:        sortBy,
-		OrderBy:       orderBy,
-		Name:          name,
-		MonsterTypeId: monsterTypeId,
-		IsCaught:      isCaught,
+	// find list similar monster
+	res, resCode, resMessage, err := hMonster.monsterUseCase.SimilarMonster(ctx.Context(), id, exclude, limit)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
 	}
 
-	// find list monster
-	res, resCode, resMessage, err := hMonster.monsterUseCase.GetListMonster(ctx.Context(), queryParams)
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+}
+
+// SuggestMonster is handler to get monster name and id for autocomplete
+func (hMonster *monsterHandler) SuggestMonster(ctx *fiber.Ctx) error {
+	prefix, err := form.ParseText("prefix", strings.TrimSpace(ctx.Query("prefix", "")), constants.SuggestMaxPrefixLength)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "prefix not valid", err.Error())
+	}
+	limit, err := strconv.Atoi(ctx.Query("limit", strconv.Itoa(constants.SuggestDefaultLimit)))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "limit not valid", err.Error())
+	}
+
+	// find list monster suggestion
+	res, resCode, resMessage, err := hMonster.monsterUseCase.SuggestMonster(ctx.Context(), prefix, limit)
 	if err != nil {
 		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
 	}
@@ -136,28 +243,94 @@ func (hMonster *monsterHandler) GetListMonster(ctx *fiber.Ctx) error {
 	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
 }
 
+// GetMonsterByIdValidator is validator of get monster by id for conditional request, entity tag is the monster version with
+// fingerprint of last change of the monster, its monster category, and its monster type
+func (hMonster *monsterHandler) GetMonsterByIdValidator(ctx *fiber.Ctx) (validator response.Validator, err error) {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return validator, err
+	}
+
+	// find monster fingerprint
+	res, _, _, err := hMonster.monsterUseCase.GetMonsterFingerprint(ctx.Context(), id)
+	if err != nil {
+		return validator, err
+	}
+
+	// signed url of private image expires, so cached response cannot be reused
+	if res.HasPrivateImage {
+		return validator, response.ErrNotCacheable
+	}
+	var lastModified int64
+	if res.LastModified != nil {
+		lastModified = res.LastModified.UnixNano()
+		validator.LastModified = *res.LastModified
+	}
+	validator.ETag = response.VersionFingerprintETag(int(res.Version), lastModified)
+
+	return validator, nil
+}
+
+// GetListMonsterValidator is validator of get list monster for conditional request
+func (hMonster *monsterHandler) GetListMonsterValidator(ctx *fiber.Ctx) (validator response.Validator, err error) {
+	// find monster fingerprint
+	res, _, _, err := hMonster.monsterUseCase.GetMonsterFingerprint(ctx.Context(), "")
+	if err != nil {
+		return validator, err
+	}
+
+	// signed url of private image is regenerated every minute, so it is part of the entity tag
+	var lastModified int64
+	if res.LastModified != nil {
+		lastModified = res.LastModified.UnixNano()
+	}
+	var signingWindow int64
+	if res.HasPrivateImage {
+		signingWindow = time.Now().Truncate(time.Minute).Unix()
+	} else if res.LastModified != nil {
+		validator.LastModified = *res.LastModified
+	}
+	validator.ETag = response.FingerprintETag(string(ctx.Request().URI().QueryString()), res.Total, res.Version, lastModified, signingWindow)
+
+	return validator, nil
+}
+
 // UpdateMonster is handler to update monster
 func (hMonster *monsterHandler) UpdateMonster(ctx *fiber.Ctx) error {
 	var req model.UpdateMonsterReq
 
-	id := form.SQLInjector(ctx.Params("id"))
-	_, err := uuid.Parse(id)
+	id, err := form.ParseUUID("id", ctx.Params("id"))
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
 	}
 
+	// get expected monster version
+	version, resCode, err := hMonster.getIfMatchVersion(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, "monster version not valid", err.Error())
+	}
+
 	// binding request body to struct
 	err = ctx.BodyParser(&req)
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
 	}
 
-	// validate request body
+	// validate request body, PUT replace every field of monster
+	// struct validation
+	err = validator.ValidateStruct(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
 	// get monster type
 	monsterTypeId, err := hMonster.getMonsterTypeId(ctx)
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "monster type must be uuid", err.Error())
 	}
+	if len(monsterTypeId) == 0 {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", "monster type is required")
+	}
 	req.MonsterTypes = monsterTypeId
 
 	// get image file
@@ -175,10 +348,65 @@ func (hMonster *monsterHandler) UpdateMonster(ctx *fiber.Ctx) error {
 			return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to save image", err.Error())
 		}
 		req.ImageName = imageName
+
+		// compute image placeholder, image which cannot be decoded is rejected
+		req.BlurHash, req.DominantColor, err = uploader.ImagePlaceholder(imageName)
+		if err != nil {
+			_ = uploader.DeleteImage(imageName)
+			return response.ErrorRes(ctx, http.StatusBadRequest, "image cannot be decoded", err.Error())
+		}
 	}
 
 	// update monster
-	resCode, resMessage, err := hMonster.monsterUseCase.UpdateMonster(ctx.Context(), id, req)
+	resCode, resMessage, err := hMonster.monsterUseCase.UpdateMonster(ctx.Context(), id, version, req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// PatchMonster is handler to partially update monster with JSON Merge Patch (RFC 7396)
+func (hMonster *monsterHandler) PatchMonster(ctx *fiber.Ctx) error {
+	var req model.PatchMonsterReq
+
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// get expected monster version
+	version, resCode, err := hMonster.getIfMatchVersion(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, "monster version not valid", err.Error())
+	}
+
+	// request body must be merge patch document
+	contentType := strings.ToLower(strings.TrimSpace(strings.Split(string(ctx.Request().Header.ContentType()), ";")[0]))
+	if contentType != "application/merge-patch+json" && contentType != fiber.MIMEApplicationJSON {
+		return response.ErrorRes(ctx, http.StatusUnsupportedMediaType, "content type not supported", "content type must be application/merge-patch+json")
+	}
+	body := bytes.TrimSpace(ctx.Body())
+	if len(body) == 0 || body[0] != '{' {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", "merge patch document must be JSON object")
+	}
+
+	// binding request body to struct, unknown field is rejected
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
+	}
+
+	// validate request body
+	err = hMonster.patchValidation(req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// patch monster
+	resCode, resMessage, err := hMonster.monsterUseCase.PatchMonster(ctx.Context(), id, version, req)
 	if err != nil {
 		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
 	}
@@ -190,8 +418,7 @@ func (hMonster *monsterHandler) UpdateMonster(ctx *fiber.Ctx) error {
 func (hMonster *monsterHandler) UpdateMonsterCaptured(ctx *fiber.Ctx) error {
 	var req model.UpdateMonsterCapturedReq
 
-	id := form.SQLInjector(ctx.Params("id"))
-	_, err := uuid.Parse(id)
+	id, err := form.ParseUUID("id", ctx.Params("id"))
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
 	}
@@ -213,14 +440,72 @@ func (hMonster *monsterHandler) UpdateMonsterCaptured(ctx *fiber.Ctx) error {
 
 // DeleteMonster is handler to delete monster
 func (hMonster *monsterHandler) DeleteMonster(ctx *fiber.Ctx) error {
-	id := form.SQLInjector(ctx.Params("id"))
-	_, err := uuid.Parse(id)
+	id, err := form.ParseUUID("id", ctx.Params("id"))
 	if err != nil {
 		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
 	}
 
+	// get expected monster version
+	version, resCode, err := hMonster.getIfMatchVersion(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, "monster version not valid", err.Error())
+	}
+
 	// delete monster
-	resCode, resMessage, err := hMonster.monsterUseCase.DeleteMonster(ctx.Context(), id)
+	resCode, resMessage, err := hMonster.monsterUseCase.DeleteMonster(ctx.Context(), id, version)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// GetMonsterHistory is handler to get list revision of monster
+func (hMonster *monsterHandler) GetMonsterHistory(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// pagination request
+	page := ctx.QueryInt("page", 1)
+	perPage := ctx.QueryInt("per_page", 10)
+	if page < 1 || perPage < 1 || perPage > 100 {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "pagination not valid", "page must be positive and per_page must be between 1 and 100")
+	}
+
+	// find monster history
+	res, count, resCode, resMessage, err := hMonster.monsterUseCase.GetMonsterHistory(ctx.Context(), id, page, perPage)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.PaginationRes(ctx, http.StatusOK, resMessage, "", page, perPage, count, res)
+}
+
+// RevertMonster is handler to revert monster to given revision, If-Match header is optional
+func (hMonster *monsterHandler) RevertMonster(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+	revisionId, err := form.ParseUUID("revision_id", ctx.Params("revisionId"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster revision id not valid", err.Error())
+	}
+
+	// get expected monster version if given
+	var version int
+	if ctx.Get(fiber.HeaderIfMatch) != "" {
+		var resCode int
+		version, resCode, err = hMonster.getIfMatchVersion(ctx)
+		if err != nil {
+			return response.ErrorRes(ctx, resCode, "monster version not valid", err.Error())
+		}
+	}
+
+	// revert monster
+	resCode, resMessage, err := hMonster.monsterUseCase.RevertMonster(ctx.Context(), id, revisionId, version)
 	if err != nil {
 		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
 	}
@@ -228,13 +513,263 @@ func (hMonster *monsterHandler) DeleteMonster(ctx *fiber.Ctx) error {
 	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
 }
 
+// BulkMonster is handler to create, update and delete monster in bulk, every operation is validated before any of it is applied
+func (hMonster *monsterHandler) BulkMonster(ctx *fiber.Ctx) error {
+	var req []model.BulkMonsterOperation
+
+	continueOnError := ctx.QueryBool("continue_on_error", false)
+
+	// request body must be JSON array of operation
+	body := bytes.TrimSpace(ctx.Body())
+	if len(body) == 0 || body[0] != '[' {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", "bulk request must be JSON array")
+	}
+
+	// binding request body to struct, unknown field is rejected
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.DisallowUnknownFields()
+	err := decoder.Decode(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
+	}
+	if len(req) == 0 || len(req) > constants.BulkMaxOperation {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", fmt.Sprintf("bulk request must have 1 to %d operation", constants.BulkMaxOperation))
+	}
+
+	// validate every operation
+	var invalidRes []model.BulkMonsterResultRes
+	for i := 0; i < len(req); i++ {
+		req[i].Index = i
+		err = hMonster.bulkValidation(&req[i])
+		if err != nil {
+			req[i].InvalidReason = err.Error()
+			invalidRes = append(invalidRes, model.BulkMonsterResultRes{
+				Index:   i,
+				Op:      req[i].Op,
+				ID:      req[i].ID,
+				Code:    http.StatusBadRequest,
+				Message: "data input is invalid",
+				Error:   err.Error(),
+			})
+		}
+	}
+	if len(invalidRes) > 0 && !continueOnError {
+		return response.ErrorDataRes(ctx, http.StatusBadRequest, "bulk monster is invalid, no operation is applied", fmt.Sprintf("%d of %d operation is invalid", len(invalidRes), len(req)), invalidRes)
+	}
+
+	// bulk monster, failed bulk still answers result per operation
+	res, resCode, resMessage, err := hMonster.monsterUseCase.BulkMonster(ctx.Context(), req, continueOnError)
+	if err != nil {
+		return response.ErrorDataRes(ctx, resCode, resMessage, err.Error(), res)
+	}
+
+	return response.SuccessRes(ctx, resCode, resMessage, "", res)
+}
+
+// getIfMatchVersion is function to get expected monster version from If-Match header, zero version means any version
+func (hMonster *monsterHandler) getIfMatchVersion(ctx *fiber.Ctx) (version int, resCode int, err error) {
+	ifMatch := strings.TrimSpace(ctx.Get(fiber.HeaderIfMatch))
+	if ifMatch == "" {
+		return 0, http.StatusPreconditionRequired, errors.New("If-Match header is required")
+	}
+	if ifMatch == "*" {
+		return 0, http.StatusOK, nil
+	}
+
+	// weak or malformed entity tag never match current version
+	version, isValid := response.ParseVersionETag(ifMatch)
+	if !isValid {
+		return 0, http.StatusPreconditionFailed, errors.New("If-Match header does not match current version")
+	}
+
+	return version, http.StatusOK, nil
+}
+
+// getMonsterListQuery is function to parse query params of list monster with its monster type, sort, and filter, list monster
+// and its export share the same query
+func (hMonster *monsterHandler) getMonsterListQuery(ctx *fiber.Ctx) (queryReq model.MonsterQueryReq, resMessage string, err error) {
+	queryReq, err = hMonster.getMonsterQuery(ctx)
+	if err != nil {
+		return queryReq, "query params not valid", err
+	}
+
+	// get monster type, sort, and filter
+	queryReq.MonsterTypeId, err = hMonster.getMonsterTypeId(ctx)
+	if err != nil {
+		return queryReq, "monster type must be uuid", err
+	}
+	queryReq.Sort, err = hMonster.getSort(ctx, queryReq.Q)
+	if err != nil {
+		return queryReq, "sort not valid", err
+	}
+	err = hMonster.getMonsterFilter(ctx, &queryReq)
+	if err != nil {
+		return queryReq, "filter not valid", err
+	}
+
+	return queryReq, "", nil
+}
+
+// getMonsterQuery is function to parse search, name, caught mark, filter expression, sparse field, and include of list monster, text is kept
+// as is since it is only bound as query argument, so name such as Farfetch'd is searched unchanged
+func (hMonster *monsterHandler) getMonsterQuery(ctx *fiber.Ctx) (queryReq model.MonsterQueryReq, err error) {
+	queryReq.Q, err = form.ParseText("q", strings.TrimSpace(ctx.Query("q", "")), constants.SearchMaxQueryLength)
+	if err != nil {
+		return model.MonsterQueryReq{}, err
+	}
+	queryReq.Name, err = form.ParseText("name", ctx.Query("name", ""), constants.MonsterNameMaxLength)
+	if err != nil {
+		return model.MonsterQueryReq{}, err
+	}
+	queryReq.IsCaught, err = form.ParseBool("is_caught", ctx.Query("is_caught", ""))
+	if err != nil {
+		return model.MonsterQueryReq{}, err
+	}
+	queryReq.Filter, err = form.ParseText("filter", ctx.Query("filter", ""), filter.MaxLength)
+	if err != nil {
+		return model.MonsterQueryReq{}, err
+	}
+	// sparse field and include is checked against whitelist by use case
+	queryReq.Fields = form.ParseList(ctx.Query("fields", ""))
+	queryReq.Include = form.ParseList(ctx.Query("include", ""))
+
+	return queryReq, nil
+}
+
+// getFacets is function to get facet of list monster, facets is given as comma separated list, e.g. facets=type,caught
+func (hMonster *monsterHandler) getFacets(ctx *fiber.Ctx) (res []string, err error) {
+	facets := ctx.Query("facets", "")
+	if facets == "" {
+		return nil, nil
+	}
+	isExist := map[string]bool{}
+	for _, facet := range strings.Split(facets, ",") {
+		facet = strings.ToLower(strings.TrimSpace(facet))
+		if facet != constants.MonsterFacetType && facet != constants.MonsterFacetCategory && facet != constants.MonsterFacetCaught {
+			return nil, fmt.Errorf("facet %q is not supported, facet must be %s, %s, or %s", facet, constants.MonsterFacetType, constants.MonsterFacetCategory, constants.MonsterFacetCaught)
+		}
+		if !isExist[facet] {
+			isExist[facet] = true
+			res = append(res, facet)
+		}
+	}
+
+	return res, nil
+}
+
+// getMonsterFilter is function to get and validate category, monster type matching, and range filter of list monster
+// range is given as <field>_min and <field>_max, both are inclusive
+func (hMonster *monsterHandler) getMonsterFilter(ctx *fiber.Ctx, queryReq *model.MonsterQueryReq) (err error) {
+	var errString []string
+	queryReq.MonsterCategoryId = ctx.Query("monster_category_id", "")
+	if queryReq.MonsterCategoryId != "" {
+		queryReq.MonsterCategoryId, err = form.ParseUUID("monster_category_id", queryReq.MonsterCategoryId)
+		if err != nil {
+			errString = append(errString, err.Error())
+		}
+	}
+	queryReq.MonsterTypeMatch = strings.ToLower(ctx.Query("monster_type_match", constants.MonsterTypeMatchAny))
+	if queryReq.MonsterTypeMatch != constants.MonsterTypeMatchAny && queryReq.MonsterTypeMatch != constants.MonsterTypeMatchAll {
+		errString = append(errString, fmt.Sprintf("monster_type_match must be %s or %s", constants.MonsterTypeMatchAny, constants.MonsterTypeMatchAll))
+	}
+
+	// length is the only decimal field, every other field is unsigned 16 bit integer
+	ranges := []struct {
+		name      string
+		value     *model.RangeReq
+		isDecimal bool
+	}{
+		{name: "monster_code", value: &queryReq.MonsterCode},
+		{name: "hp", value: &queryReq.HP},
+		{name: "attack", value: &queryReq.Attack},
+		{name: "defends", value: &queryReq.Defends},
+		{name: "speed", value: &queryReq.Speed},
+		{name: "weight", value: &queryReq.Weight},
+		{name: "length", value: &queryReq.Length, isDecimal: true},
+	}
+	for i := 0; i < len(ranges); i++ {
+		bounds := []struct {
+			name  string
+			value **float64
+		}{
+			{name: ranges[i].name + "_min", value: &ranges[i].value.Min},
+			{name: ranges[i].name + "_max", value: &ranges[i].value.Max},
+		}
+		for j := 0; j < len(bounds); j++ {
+			query := ctx.Query(bounds[j].name, "")
+			if query == "" {
+				continue
+			}
+			if ranges[i].isDecimal {
+				value, err := strconv.ParseFloat(query, 64)
+				if err != nil || math.IsNaN(value) || math.IsInf(value, 0) || value < 0 {
+					errString = append(errString, fmt.Sprintf("%s must be non negative number", bounds[j].name))
+					continue
+				}
+				*bounds[j].value = &value
+			} else {
+				value, err := strconv.ParseUint(query, 10, 16)
+				if err != nil {
+					errString = append(errString, fmt.Sprintf("%s must be integer between 0 and %d", bounds[j].name, math.MaxUint16))
+					continue
+				}
+				floatValue := float64(value)
+				*bounds[j].value = &floatValue
+			}
+		}
+		if ranges[i].value.Min != nil && ranges[i].value.Max != nil && *ranges[i].value.Min > *ranges[i].value.Max {
+			errString = append(errString, fmt.Sprintf("%s_min must not be greater than %s_max", ranges[i].name, ranges[i].name))
+		}
+	}
+	if len(errString) > 0 {
+		return errors.New(strings.Join(errString, ", "))
+	}
+
+	return nil
+}
+
+// getSort is function to get multi-key sort such as sort=-hp,name, every field must be whitelisted sort field of monster
+// sort_by and order_by are still accepted as single key sort when sort is not given, relevance requires q
+func (hMonster *monsterHandler) getSort(ctx *fiber.Ctx, q string) (res []form.Sort, err error) {
+	sort := ctx.Query("sort", "")
+	if sort == "" && (ctx.Query("sort_by", "") != "" || ctx.Query("order_by", "") != "") {
+		defaultSortBy := "created_at"
+		if q != "" {
+			defaultSortBy = constants.SortByRelevance
+		}
+		sort = ctx.Query("sort_by", defaultSortBy)
+		switch strings.ToUpper(ctx.Query("order_by", "ASC")) {
+		case "ASC":
+		case "DESC":
+			sort = "-" + sort
+		default:
+			return nil, errors.New("order_by must be ASC or DESC")
+		}
+	}
+	if sort == "" {
+		return nil, nil
+	}
+
+	res, err = form.ParseSort(sort, usecase.MonsterSortFields, constants.SortMaxKeys)
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < len(res); i++ {
+		if res[i].Field == constants.SortByRelevance && q == "" {
+			return nil, errors.New("sort by relevance requires q")
+		}
+	}
+
+	return res, nil
+}
+
 // getMonsterTypeId is
 func (hMonster *monsterHandler) getMonsterTypeId(ctx *fiber.Ctx) (monsterType []string, err error) {
 	loopCheck := true
 	for i := 0; i < 7; i++ {
 		typeId := ctx.FormValue(fmt.Sprintf("monster_type_id[%d]", i), "")
 		if typeId != "" {
-			_, err = uuid.Parse(typeId)
+			typeId, err = form.ParseUUID(fmt.Sprintf("monster_type_id[%d]", i), typeId)
 			if err != nil {
 				return nil, err
 			}
@@ -250,6 +785,150 @@ func (hMonster *monsterHandler) getMonsterTypeId(ctx *fiber.Ctx) (monsterType []
 	return monsterType, nil
 }
 
+// patchValidation is
+func (hMonster *monsterHandler) patchValidation(req model.PatchMonsterReq) (err error) {
+	// only description can be cleared with null
+	nullFields := []struct {
+		name   string
+		isNull bool
+	}{
+		{name: "name", isNull: req.Name.Null},
+		{name: "monster_category_id", isNull: req.MonsterCategoryId.Null},
+		{name: "monster_types", isNull: req.MonsterTypes.Null},
+		{name: "length", isNull: req.Length.Null},
+		{name: "weight", isNull: req.Weight.Null},
+		{name: "hp", isNull: req.HP.Null},
+		{name: "attack", isNull: req.Attack.Null},
+		{name: "defends", isNull: req.Defends.Null},
+		{name: "speed", isNull: req.Speed.Null},
+		{name: "is_caught", isNull: req.IsCaught.Null},
+	}
+	var errString []string
+	for i := 0; i < len(nullFields); i++ {
+		if nullFields[i].isNull {
+			errString = append(errString, fmt.Sprintf("%s cannot be null", nullFields[i].name))
+		}
+	}
+
+	// value validation
+	if req.Name.IsValue() && strings.TrimSpace(req.Name.Value) == "" {
+		errString = append(errString, "name cannot be empty")
+	}
+	if req.MonsterCategoryId.IsValue() {
+		_, err = uuid.Parse(req.MonsterCategoryId.Value)
+		if err != nil {
+			errString = append(errString, "monster_category_id must be uuid")
+		}
+	}
+	if req.MonsterTypes.IsValue() {
+		if len(req.MonsterTypes.Value) == 0 {
+			errString = append(errString, "monster_types cannot be empty")
+		}
+		for i := 0; i < len(req.MonsterTypes.Value); i++ {
+			_, err = uuid.Parse(req.MonsterTypes.Value[i])
+			if err != nil {
+				errString = append(errString, "monster_types must be uuid")
+				break
+			}
+		}
+	}
+	if req.Length.IsValue() && req.Length.Value < 0 {
+		errString = append(errString, "length cannot be negative")
+	}
+
+	if errString != nil {
+		return errors.New(strings.Join(errString, ", "))
+	}
+
+	return nil
+}
+
+// bulkValidation is function to validate bulk operation and bind its data into request of the operation
+func (hMonster *monsterHandler) bulkValidation(op *model.BulkMonsterOperation) (err error) {
+	if op.Op != constants.BulkOperationCreate {
+		_, err = uuid.Parse(op.ID)
+		if err != nil {
+			return errors.New("monster id must be uuid")
+		}
+
+		// version is required the same way as If-Match header, so concurrent change is never overwritten
+		if op.Version < 1 {
+			return errors.New("version is required on update and delete")
+		}
+	}
+
+	switch op.Op {
+	case constants.BulkOperationCreate:
+		if op.ID != "" || op.Version != 0 {
+			return errors.New("id and version are not allowed on create")
+		}
+		err = hMonster.bulkData(op.Data, &op.CreateReq)
+		if err != nil {
+			return err
+		}
+		err = validator.ValidateStruct(&op.CreateReq)
+		if err != nil {
+			return err
+		}
+		if op.CreateReq.ImageName != "" {
+			return errors.New("image is not supported in bulk request")
+		}
+		return hMonster.bulkReferenceValidation(op.CreateReq.MonsterCategoryId, op.CreateReq.MonsterTypes)
+	case constants.BulkOperationUpdate:
+		err = hMonster.bulkData(op.Data, &op.UpdateReq)
+		if err != nil {
+			return err
+		}
+		err = validator.ValidateStruct(&op.UpdateReq)
+		if err != nil {
+			return err
+		}
+		if op.UpdateReq.ImageName != "" {
+			return errors.New("image is not supported in bulk request")
+		}
+		return hMonster.bulkReferenceValidation(op.UpdateReq.MonsterCategoryId, op.UpdateReq.MonsterTypes)
+	case constants.BulkOperationDelete:
+		data := bytes.TrimSpace(op.Data)
+		if len(data) > 0 && string(data) != "null" {
+			return errors.New("data is not allowed on delete")
+		}
+		return nil
+	default:
+		return fmt.Errorf("op must be one of %s, %s or %s", constants.BulkOperationCreate, constants.BulkOperationUpdate, constants.BulkOperationDelete)
+	}
+}
+
+// bulkData is function to bind data of bulk operation, unknown field is rejected
+func (hMonster *monsterHandler) bulkData(data json.RawMessage, req interface{}) (err error) {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 || data[0] != '{' {
+		return errors.New("data must be JSON object")
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	return decoder.Decode(req)
+}
+
+// bulkReferenceValidation is function to validate monster category and monster type of bulk operation
+func (hMonster *monsterHandler) bulkReferenceValidation(monsterCategoryId string, monsterTypes []string) (err error) {
+	_, err = uuid.Parse(monsterCategoryId)
+	if err != nil {
+		return errors.New("monster category must be uuid")
+	}
+	if len(monsterTypes) == 0 {
+		return errors.New("monster type is required")
+	}
+	for i := 0; i < len(monsterTypes); i++ {
+		_, err = uuid.Parse(monsterTypes[i])
+		if err != nil {
+			return errors.New("monster type must be uuid")
+		}
+	}
+
+	return nil
+}
+
 // fileUploadedValidation is
 func (hMonster *monsterHandler) fileUploadedValidation(fileHeader *multipart.FileHeader) (isValid bool, resMessage string) {
 	maxPartSize := int64(10 * 1024 * 1024)
@@ -265,3 +944,29 @@ func (hMonster *monsterHandler) fileUploadedValidation(fileHeader *multipart.Fil
 
 	return true, ""
 }
+
+// cancelWriter is writer of streamed response which cancels its context once writing to client fails
+type cancelWriter struct {
+	writer *bufio.Writer
+	cancel context.CancelFunc
+}
+
+// Write is function to write into streamed response, context is canceled when client is gone
+func (w *cancelWriter) Write(p []byte) (n int, err error) {
+	n, err = w.writer.Write(p)
+	if err != nil {
+		w.cancel()
+	}
+
+	return n, err
+}
+
+// Flush is function to flush streamed response, context is canceled when client is gone
+func (w *cancelWriter) Flush() (err error) {
+	err = w.writer.Flush()
+	if err != nil {
+		w.cancel()
+	}
+
+	return err
+}
@@ -27,3 +27,21 @@ func (hMCategory *mCategoryHandler) GetAllMonsterCategory(ctx *fiber.Ctx) error
 
 	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
 }
+
+// GetAllMonsterCategoryValidator is validator of get all monster category for conditional request
+func (hMCategory *mCategoryHandler) GetAllMonsterCategoryValidator(ctx *fiber.Ctx) (validator response.Validator, err error) {
+	// find monster category fingerprint
+	res, _, _, err := hMCategory.mCategoryUseCase.GetMonsterCategoryFingerprint(ctx.Context())
+	if err != nil {
+		return validator, err
+	}
+
+	var lastModified int64
+	if res.LastModified != nil {
+		lastModified = res.LastModified.UnixNano()
+		validator.LastModified = *res.LastModified
+	}
+	validator.ETag = response.FingerprintETag(res.Total, lastModified)
+
+	return validator, nil
+}
@@ -0,0 +1,289 @@
+package delivery
+
+import (
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/libs/validator"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type monsterImageHandler struct {
+	formatFile          map[string]bool
+	imageNamePattern    *regexp.Regexp
+	cacheMaxAge         time.Duration
+	monsterImageUseCase usecase.MonsterImageUseCaseInterface
+}
+
+func NewMonsterImageHandler(cacheMaxAge time.Duration, monsterImageUseCase usecase.MonsterImageUseCaseInterface) *monsterImageHandler {
+	return &monsterImageHandler{
+		formatFile: map[string]bool{
+			".png":  true,
+			".jpeg": true,
+			".jpg":  true,
+		},
+		imageNamePattern:    regexp.MustCompile(`^[A-Za-z0-9_-]+\.[A-Za-z0-9]+$`),
+		cacheMaxAge:         cacheMaxAge,
+		monsterImageUseCase: monsterImageUseCase,
+	}
+}
+
+// CreateMonsterImage is handler to upload image into monster gallery
+func (hMonsterImage *monsterImageHandler) CreateMonsterImage(ctx *fiber.Ctx) error {
+	var req model.CreateMonsterImageReq
+
+	monsterId, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// binding request body to struct
+	err = ctx.BodyParser(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
+	}
+
+	// validate request body
+	// get variant tags
+	req.VariantTags = hMonsterImage.getVariantTags(ctx)
+	for i := 0; i < len(req.VariantTags); i++ {
+		if len(req.VariantTags[i]) > 50 {
+			return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", "variant tag cannot exceed 50 characters")
+		}
+	}
+	// struct validation
+	err = validator.ValidateStruct(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// get image file
+	imageFile, err := ctx.FormFile("image")
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", "image is required")
+	}
+	isValid, resMessage := hMonsterImage.fileUploadedValidation(imageFile)
+	if !isValid {
+		return response.ErrorRes(ctx, http.StatusBadRequest, resMessage, resMessage)
+	}
+
+	// save image
+	imageName, err := uploader.SaveImage(ctx, imageFile)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to save image", err.Error())
+	}
+	req.ImageName = imageName
+
+	// compute image placeholder, image which cannot be decoded is rejected
+	req.BlurHash, req.DominantColor, err = uploader.ImagePlaceholder(imageName)
+	if err != nil {
+		_ = uploader.DeleteImage(imageName)
+		return response.ErrorRes(ctx, http.StatusBadRequest, "image cannot be decoded", err.Error())
+	}
+
+	// create monster image
+	res, resCode, resMessage, err := hMonsterImage.monsterImageUseCase.CreateMonsterImage(ctx.Context(), monsterId, req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusCreated, resMessage, "", res)
+}
+
+// ReorderMonsterImage is handler to reorder monster gallery
+func (hMonsterImage *monsterImageHandler) ReorderMonsterImage(ctx *fiber.Ctx) error {
+	var req model.ReorderMonsterImageReq
+
+	monsterId, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// binding request body to struct
+	err = ctx.BodyParser(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
+	}
+
+	// validate request body
+	// struct validation
+	err = validator.ValidateStruct(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// reorder monster image
+	resCode, resMessage, err := hMonsterImage.monsterImageUseCase.ReorderMonsterImage(ctx.Context(), monsterId, req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// SetPrimaryMonsterImage is handler to set primary image of monster gallery
+func (hMonsterImage *monsterImageHandler) SetPrimaryMonsterImage(ctx *fiber.Ctx) error {
+	monsterId, imageId, err := hMonsterImage.getId(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id or image id not valid", err.Error())
+	}
+
+	// set primary monster image
+	resCode, resMessage, err := hMonsterImage.monsterImageUseCase.SetPrimaryMonsterImage(ctx.Context(), monsterId, imageId)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// UpdateMonsterImageVisibility is handler to publish or hold back image of monster gallery
+func (hMonsterImage *monsterImageHandler) UpdateMonsterImageVisibility(ctx *fiber.Ctx) error {
+	var req model.UpdateMonsterImageVisibilityReq
+
+	monsterId, imageId, err := hMonsterImage.getId(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id or image id not valid", err.Error())
+	}
+
+	// binding request body to struct
+	err = ctx.BodyParser(&req)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to binds the request body", err.Error())
+	}
+
+	// update monster image visibility
+	resCode, resMessage, err := hMonsterImage.monsterImageUseCase.UpdateMonsterImageVisibility(ctx.Context(), monsterId, imageId, req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// DeleteMonsterImage is handler to delete image from monster gallery
+func (hMonsterImage *monsterImageHandler) DeleteMonsterImage(ctx *fiber.Ctx) error {
+	monsterId, imageId, err := hMonsterImage.getId(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id or image id not valid", err.Error())
+	}
+
+	// delete monster image
+	resCode, resMessage, err := hMonsterImage.monsterImageUseCase.DeleteMonsterImage(ctx.Context(), monsterId, imageId)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// ServeImage is handler to serve monster image, private image require signed url
+func (hMonsterImage *monsterImageHandler) ServeImage(ctx *fiber.Ctx) error {
+	imageName := ctx.Params("imageName")
+	if !hMonsterImage.imageNamePattern.MatchString(imageName) {
+		return response.ErrorRes(ctx, http.StatusNotFound, "image not found", "image name not valid")
+	}
+	expires := ctx.Query("expires")
+
+	// find monster image
+	res, resCode, resMessage, err := hMonsterImage.monsterImageUseCase.GetMonsterImageFile(ctx.Context(), imageName, expires, ctx.Query("signature"))
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	// open image file
+	file, err := os.Open(filepath.Join(constants.ImageDirectory, res.ImageName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return response.ErrorRes(ctx, http.StatusNotFound, "image not found", err.Error())
+		}
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to open image", err.Error())
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to open image", err.Error())
+	}
+
+	// cache header, private image is only cached by client until its url expired
+	cacheControl := fmt.Sprintf("public, max-age=%d", int64(hMonsterImage.cacheMaxAge.Seconds()))
+	if res.IsPrivate {
+		expiresUnix, _ := strconv.ParseInt(expires, 10, 64)
+		cacheControl = fmt.Sprintf("private, max-age=%d", expiresUnix-time.Now().Unix())
+	}
+	eTag := fmt.Sprintf(`"%x-%x"`, info.ModTime().UnixNano(), info.Size())
+	contentType := mime.TypeByExtension(strings.ToLower(filepath.Ext(res.ImageName)))
+
+	// serve image, conditional and range request are handled by http.ServeContent
+	return adaptor.HTTPHandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Header().Set("ETag", eTag)
+		if contentType != "" {
+			w.Header().Set("Content-Type", contentType)
+		}
+		http.ServeContent(w, r, res.ImageName, info.ModTime(), file)
+	})(ctx)
+}
+
+// getId is
+func (hMonsterImage *monsterImageHandler) getId(ctx *fiber.Ctx) (monsterId, imageId string, err error) {
+	monsterId, err = form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return "", "", err
+	}
+	imageId, err = form.ParseUUID("image_id", ctx.Params("imageId"))
+	if err != nil {
+		return "", "", err
+	}
+
+	return monsterId, imageId, nil
+}
+
+// getVariantTags is
+func (hMonsterImage *monsterImageHandler) getVariantTags(ctx *fiber.Ctx) (variantTags []string) {
+	existTag := map[string]bool{}
+	for i := 0; ; i++ {
+		tag := strings.ToLower(strings.TrimSpace(ctx.FormValue(fmt.Sprintf("variant_tags[%d]", i), "")))
+		if tag == "" {
+			break
+		}
+		if existTag[tag] {
+			continue
+		}
+		existTag[tag] = true
+		variantTags = append(variantTags, tag)
+	}
+
+	return variantTags
+}
+
+// fileUploadedValidation is
+func (hMonsterImage *monsterImageHandler) fileUploadedValidation(fileHeader *multipart.FileHeader) (isValid bool, resMessage string) {
+	maxPartSize := int64(10 * 1024 * 1024)
+	size := fileHeader.Size
+	extension := strings.ToLower(filepath.Ext(fileHeader.Filename))
+
+	if size > maxPartSize {
+		return false, "file cannot exceed 10 MB"
+	}
+	if !hMonsterImage.formatFile[extension] {
+		return false, "file format must be .png, .jpg, or .jpeg"
+	}
+
+	return true, ""
+}
@@ -0,0 +1,81 @@
+package delivery
+
+import (
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/importer"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/frianlh/pokedex-api/usecase"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+type monsterImportHandler struct {
+	monsterImportUseCase usecase.MonsterImportUseCaseInterface
+}
+
+func NewMonsterImportHandler(monsterImportUseCase usecase.MonsterImportUseCaseInterface) *monsterImportHandler {
+	return &monsterImportHandler{
+		monsterImportUseCase: monsterImportUseCase,
+	}
+}
+
+// ImportMonster is handler to import monster from CSV or JSON file, image is referenced from optional zip archive
+func (hMonsterImport *monsterImportHandler) ImportMonster(ctx *fiber.Ctx) error {
+	dryRun := ctx.QueryBool("dry_run", false)
+
+	// get import file
+	importFile, err := ctx.FormFile("file")
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", "import file is required")
+	}
+	if importFile.Size > constants.ImportMaxFileSize {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", fmt.Sprintf("import file cannot exceed %d MB", constants.ImportMaxFileSize/1024/1024))
+	}
+	format, err := importer.FormatOf(importFile.Filename)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// parse import file
+	file, err := importFile.Open()
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to read import file", err.Error())
+	}
+	defer file.Close()
+	rows, err := importer.Parse(format, file, constants.ImportTypeSeparator)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "failed to parse import file", err.Error())
+	}
+
+	// get image archive
+	var archive *importer.Archive
+	archiveFile, err := ctx.FormFile("images")
+	if err == nil {
+		if strings.ToLower(filepath.Ext(archiveFile.Filename)) != ".zip" {
+			return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", "image archive must be .zip")
+		}
+		if archiveFile.Size > constants.ImportMaxArchiveSize {
+			return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", fmt.Sprintf("image archive cannot exceed %d MB", constants.ImportMaxArchiveSize/1024/1024))
+		}
+		zipFile, err := archiveFile.Open()
+		if err != nil {
+			return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to read image archive", err.Error())
+		}
+		defer zipFile.Close()
+		archive, err = importer.OpenArchive(zipFile, archiveFile.Size, constants.ImportMaxImageSize)
+		if err != nil {
+			return response.ErrorRes(ctx, http.StatusBadRequest, "failed to read image archive", err.Error())
+		}
+	}
+
+	// import monster
+	res, resCode, resMessage, err := hMonsterImport.monsterImportUseCase.ImportMonster(ctx.Context(), rows, archive, dryRun)
+	if err != nil {
+		return response.SuccessRes(ctx, resCode, resMessage, err.Error(), res)
+	}
+
+	return response.SuccessRes(ctx, resCode, resMessage, "", res)
+}
@@ -0,0 +1,98 @@
+package delivery
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/usecase"
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// bulkMonsterUseCase is monster use case of bulk handler test, the second operation of atomic bulk always fails
+type bulkMonsterUseCase struct {
+	usecase.MonsterUseCaseInterface
+}
+
+func (uMonster *bulkMonsterUseCase) BulkMonster(ctx context.Context, req []model.BulkMonsterOperation, continueOnError bool) (res []model.BulkMonsterResultRes, resCode int, resMessage string, err error) {
+	for i := 0; i < len(req); i++ {
+		result := model.BulkMonsterResultRes{Index: req[i].Index, Op: req[i].Op, ID: req[i].ID, Code: http.StatusFailedDependency, Message: "operation is not applied", Error: "previous operation failed"}
+		switch {
+		case i == 0:
+			result.Message, result.Error = "operation is rolled back", ""
+		case i == 1:
+			result.Code, result.Message, result.Error = http.StatusBadRequest, "monster not found", "record not found"
+		}
+		res = append(res, result)
+	}
+
+	return res, http.StatusBadRequest, "bulk monster failed, no operation is applied", errors.New("operation 1: record not found")
+}
+
+func TestBulkMonster(t *testing.T) {
+	// argument
+	type args struct {
+		body string
+	}
+
+	// test case
+	tests := []struct {
+		name      string
+		args      args
+		wantCode  int
+		wantCodes []int
+	}{
+		// failed scenario: test with invalid operation, only invalid operation is answered
+		{
+			name:      "Failed_With_Invalid_Operation",
+			args:      args{body: fmt.Sprintf(`[{"op":"delete","id":%q,"version":1},{"op":"delete","id":"not-uuid","version":1}]`, uuid.NewString())},
+			wantCode:  http.StatusBadRequest,
+			wantCodes: []int{http.StatusBadRequest},
+		},
+		// failed scenario: test with failed atomic bulk, every operation is answered
+		{
+			name: "Failed_With_Failed_Atomic_Bulk",
+			args: args{body: fmt.Sprintf(`[{"op":"delete","id":%q,"version":1},{"op":"delete","id":%q,"version":1},{"op":"delete","id":%q,"version":1}]`,
+				uuid.NewString(), uuid.NewString(), uuid.NewString())},
+			wantCode:  http.StatusBadRequest,
+			wantCodes: []int{http.StatusFailedDependency, http.StatusBadRequest, http.StatusFailedDependency},
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hMonster := NewMonsterHandler(&bulkMonsterUseCase{})
+			f := fiber.New()
+			f.Post("/", hMonster.BulkMonster)
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.args.body))
+			req.Header.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			res, err := f.Test(req)
+			assert.NoError(t, err)
+			assert.Equal(t, res.StatusCode, tt.wantCode)
+
+			// body
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			var resBody struct {
+				Data []model.BulkMonsterResultRes `json:"data"`
+			}
+			err = json.Unmarshal(body, &resBody)
+			assert.NoError(t, err)
+			assert.Len(t, resBody.Data, len(tt.wantCodes))
+			for i := 0; i < len(resBody.Data); i++ {
+				assert.Equal(t, resBody.Data[i].Op, constants.BulkOperationDelete)
+				assert.Equal(t, resBody.Data[i].Code, tt.wantCodes[i])
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package delivery
+
+import (
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/frianlh/pokedex-api/usecase"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+)
+
+type monsterTrashHandler struct {
+	monsterTrashUseCase usecase.MonsterTrashUseCaseInterface
+}
+
+func NewMonsterTrashHandler(monsterTrashUseCase usecase.MonsterTrashUseCaseInterface) *monsterTrashHandler {
+	return &monsterTrashHandler{
+		monsterTrashUseCase: monsterTrashUseCase,
+	}
+}
+
+// GetListDeletedMonster is handler to get list soft deleted monster
+func (hMonsterTrash *monsterTrashHandler) GetListDeletedMonster(ctx *fiber.Ctx) error {
+	// pagination request
+	page := ctx.QueryInt("page", 1)
+	perPage := ctx.QueryInt("per_page", 10)
+	if page < 1 || perPage < 1 || perPage > 100 {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "pagination not valid", "page must be positive and per_page must be between 1 and 100")
+	}
+
+	// find list deleted monster
+	res, count, resCode, resMessage, err := hMonsterTrash.monsterTrashUseCase.GetListDeletedMonster(ctx.Context(), page, perPage)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.PaginationRes(ctx, http.StatusOK, resMessage, "", page, perPage, count, res)
+}
+
+// RestoreMonster is handler to restore soft deleted monster
+func (hMonsterTrash *monsterTrashHandler) RestoreMonster(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// restore monster
+	resCode, resMessage, err := hMonsterTrash.monsterTrashUseCase.RestoreMonster(ctx.Context(), id)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// PurgeMonster is handler to permanently delete soft deleted monster
+func (hMonsterTrash *monsterTrashHandler) PurgeMonster(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "monster id not valid", err.Error())
+	}
+
+	// purge monster
+	resCode, resMessage, err := hMonsterTrash.monsterTrashUseCase.PurgeMonster(ctx.Context(), id)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
@@ -27,3 +27,21 @@ func (hMType *mTypeHandler) GetAllMonsterType(ctx *fiber.Ctx) error {
 
 	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
 }
+
+// GetAllMonsterTypeValidator is validator of get all monster type for conditional request
+func (hMType *mTypeHandler) GetAllMonsterTypeValidator(ctx *fiber.Ctx) (validator response.Validator, err error) {
+	// find monster type fingerprint
+	res, _, _, err := hMType.mTypeUseCase.GetMonsterTypeFingerprint(ctx.Context())
+	if err != nil {
+		return validator, err
+	}
+
+	var lastModified int64
+	if res.LastModified != nil {
+		lastModified = res.LastModified.UnixNano()
+		validator.LastModified = *res.LastModified
+	}
+	validator.ETag = response.FingerprintETag(res.Total, lastModified)
+
+	return validator, nil
+}
@@ -0,0 +1,139 @@
+package delivery
+
+import (
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/frianlh/pokedex-api/libs/validator"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/usecase"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"strings"
+)
+
+type savedSearchHandler struct {
+	savedSearchUseCase usecase.SavedSearchUseCaseInterface
+}
+
+func NewSavedSearchHandler(savedSearchUseCase usecase.SavedSearchUseCaseInterface) *savedSearchHandler {
+	return &savedSearchHandler{
+		savedSearchUseCase: savedSearchUseCase,
+	}
+}
+
+// CreateSavedSearch is handler to save monster query of authenticated user
+func (hSavedSearch *savedSearchHandler) CreateSavedSearch(ctx *fiber.Ctx) error {
+	req, err := hSavedSearch.getSavedSearchReq(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// create saved search
+	res, resCode, resMessage, err := hSavedSearch.savedSearchUseCase.CreateSavedSearch(ctx.Context(), req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusCreated, resMessage, "", res)
+}
+
+// GetListSavedSearch is handler to get list saved search of authenticated user
+func (hSavedSearch *savedSearchHandler) GetListSavedSearch(ctx *fiber.Ctx) error {
+	// find list saved search
+	res, resCode, resMessage, err := hSavedSearch.savedSearchUseCase.GetListSavedSearch(ctx.Context())
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+}
+
+// GetSavedSearchById is handler to get saved search of authenticated user by id
+func (hSavedSearch *savedSearchHandler) GetSavedSearchById(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "saved search id not valid", err.Error())
+	}
+
+	// find saved search by id
+	res, resCode, resMessage, err := hSavedSearch.savedSearchUseCase.GetSavedSearchById(ctx.Context(), id)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+}
+
+// UpdateSavedSearch is handler to replace name and monster query of saved search
+func (hSavedSearch *savedSearchHandler) UpdateSavedSearch(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "saved search id not valid", err.Error())
+	}
+	req, err := hSavedSearch.getSavedSearchReq(ctx)
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "data input is invalid", err.Error())
+	}
+
+	// update saved search
+	res, resCode, resMessage, err := hSavedSearch.savedSearchUseCase.UpdateSavedSearch(ctx.Context(), id, req)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+}
+
+// DeleteSavedSearch is handler to delete saved search
+func (hSavedSearch *savedSearchHandler) DeleteSavedSearch(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "saved search id not valid", err.Error())
+	}
+
+	// delete saved search
+	resCode, resMessage, err := hSavedSearch.savedSearchUseCase.DeleteSavedSearch(ctx.Context(), id)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", nil)
+}
+
+// GetSavedSearchResult is handler to run saved search as list monster
+func (hSavedSearch *savedSearchHandler) GetSavedSearchResult(ctx *fiber.Ctx) error {
+	id, err := form.ParseUUID("id", ctx.Params("id"))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusBadRequest, "saved search id not valid", err.Error())
+	}
+
+	// find saved search result, only field and include saved with the search is kept
+	resSavedSearch, resMonster, resCode, resMessage, err := hSavedSearch.savedSearchUseCase.GetSavedSearchResult(ctx.Context(), id)
+	if err != nil {
+		return response.ErrorRes(ctx, resCode, resMessage, err.Error())
+	}
+	res, err := response.SparseFields(resMonster, usecase.MonsterListKeys(resSavedSearch.Query))
+	if err != nil {
+		return response.ErrorRes(ctx, http.StatusInternalServerError, "failed to get saved search result", err.Error())
+	}
+
+	return response.SuccessRes(ctx, http.StatusOK, resMessage, "", res)
+}
+
+// getSavedSearchReq is function to bind and validate JSON body of saved search
+func (hSavedSearch *savedSearchHandler) getSavedSearchReq(ctx *fiber.Ctx) (req model.SavedSearchReq, err error) {
+	// binding request body to struct
+	err = ctx.BodyParser(&req)
+	if err != nil {
+		return req, err
+	}
+
+	// struct validation
+	req.Name = strings.TrimSpace(req.Name)
+	err = validator.ValidateStruct(&req)
+	if err != nil {
+		return req, err
+	}
+
+	return req, nil
+}
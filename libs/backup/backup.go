@@ -0,0 +1,325 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// FormatVersion is version of archive layout, archive of other version cannot be restored
+	FormatVersion = 1
+	// ManifestName is name of manifest entry, it is the only entry not listed in manifest
+	ManifestName = "manifest.json"
+)
+
+// Manifest is content list of archive, every entry is listed with its size and checksum
+type Manifest struct {
+	FormatVersion int       `json:"format_version"`
+	SchemaVersion uint      `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Files         []File    `json:"files"`
+}
+
+// File is entry of archive
+type File struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Writer is writer of archive, manifest is written when it is closed
+type Writer struct {
+	zipWriter *zip.Writer
+	manifest  Manifest
+	names     map[string]bool
+}
+
+// NewWriter is function to create archive writer of given database schema version
+func NewWriter(writer io.Writer, schemaVersion uint, createdAt time.Time) *Writer {
+	return &Writer{
+		zipWriter: zip.NewWriter(writer),
+		manifest: Manifest{
+			FormatVersion: FormatVersion,
+			SchemaVersion: schemaVersion,
+			CreatedAt:     createdAt.UTC(),
+			Files:         []File{},
+		},
+		names: map[string]bool{},
+	}
+}
+
+// WriteJSON is function to write data as JSON entry
+func (w *Writer) WriteJSON(name string, data interface{}) (err error) {
+	content, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	return w.Write(name, bytes.NewReader(content))
+}
+
+// Write is function to write entry, its size and checksum are recorded in manifest
+func (w *Writer) Write(name string, reader io.Reader) (err error) {
+	err = ValidateName(name)
+	if err != nil {
+		return err
+	}
+	if name == ManifestName || w.names[name] {
+		return fmt.Errorf("duplicate entry %q", name)
+	}
+
+	entry, err := w.zipWriter.Create(name)
+	if err != nil {
+		return err
+	}
+	checksum := sha256.New()
+	size, err := io.Copy(io.MultiWriter(entry, checksum), reader)
+	if err != nil {
+		return err
+	}
+	w.names[name] = true
+	w.manifest.Files = append(w.manifest.Files, File{
+		Name:   name,
+		Size:   size,
+		SHA256: hex.EncodeToString(checksum.Sum(nil)),
+	})
+
+	return nil
+}
+
+// Close is function to write manifest and close archive
+func (w *Writer) Close() (manifest Manifest, err error) {
+	entry, err := w.zipWriter.Create(ManifestName)
+	if err != nil {
+		return manifest, err
+	}
+	encoder := json.NewEncoder(entry)
+	encoder.SetIndent("", "  ")
+	err = encoder.Encode(w.manifest)
+	if err != nil {
+		return manifest, err
+	}
+	err = w.zipWriter.Close()
+	if err != nil {
+		return manifest, err
+	}
+
+	return w.manifest, nil
+}
+
+// Reader is reader of archive, entry is only readable if it is listed in manifest
+type Reader struct {
+	Manifest Manifest
+	files    map[string]*zip.File
+	sizes    map[string]File
+}
+
+// OpenReader is function to open archive and read its manifest
+// archive is rejected if its format version is not supported, or if its entries do not match its manifest
+func OpenReader(reader io.ReaderAt, size int64) (res *Reader, err error) {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	res = &Reader{
+		files: map[string]*zip.File{},
+		sizes: map[string]File{},
+	}
+	var manifestFile *zip.File
+	for i := 0; i < len(zipReader.File); i++ {
+		if zipReader.File[i].FileInfo().IsDir() {
+			continue
+		}
+		name := zipReader.File[i].Name
+		if name == ManifestName {
+			manifestFile = zipReader.File[i]
+			continue
+		}
+		if _, ok := res.files[name]; ok {
+			return nil, fmt.Errorf("duplicate entry %q", name)
+		}
+		res.files[name] = zipReader.File[i]
+	}
+	if manifestFile == nil {
+		return nil, errors.New("manifest is not found in archive")
+	}
+
+	// read manifest
+	manifestReader, err := manifestFile.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer manifestReader.Close()
+	decoder := json.NewDecoder(manifestReader)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(&res.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("manifest is invalid: %w", err)
+	}
+	if res.Manifest.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("archive format version %d is not supported, supported format version is %d", res.Manifest.FormatVersion, FormatVersion)
+	}
+
+	// entries must match manifest
+	for i := 0; i < len(res.Manifest.Files); i++ {
+		file := res.Manifest.Files[i]
+		err = ValidateName(file.Name)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := res.sizes[file.Name]; ok {
+			return nil, fmt.Errorf("duplicate entry %q in manifest", file.Name)
+		}
+		if _, ok := res.files[file.Name]; !ok {
+			return nil, fmt.Errorf("entry %q of manifest is not found in archive", file.Name)
+		}
+		res.sizes[file.Name] = file
+	}
+	for name := range res.files {
+		if _, ok := res.sizes[name]; !ok {
+			return nil, fmt.Errorf("entry %q is not listed in manifest", name)
+		}
+	}
+
+	return res, nil
+}
+
+// Verify is function to verify size and checksum of every entry against manifest
+func (r *Reader) Verify() (err error) {
+	for i := 0; i < len(r.Manifest.Files); i++ {
+		reader, err := r.Open(r.Manifest.Files[i].Name)
+		if err != nil {
+			return err
+		}
+		_, err = io.Copy(io.Discard, reader)
+		reader.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Has is function to check entry exist in archive
+func (r *Reader) Has(name string) bool {
+	_, ok := r.sizes[name]
+
+	return ok
+}
+
+// File is function to get manifest entry of given name
+func (r *Reader) File(name string) (file File, ok bool) {
+	file, ok = r.sizes[name]
+
+	return file, ok
+}
+
+// Names is function to get sorted name of entry with given directory prefix
+func (r *Reader) Names(prefix string) (res []string) {
+	for name := range r.sizes {
+		if strings.HasPrefix(name, prefix) {
+			res = append(res, name)
+		}
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// Open is function to open entry, reading fails if entry does not match its size and checksum of manifest
+func (r *Reader) Open(name string) (reader io.ReadCloser, err error) {
+	file, ok := r.sizes[name]
+	if !ok {
+		return nil, fmt.Errorf("entry %q is not found in archive", name)
+	}
+	zipReader, err := r.files[name].Open()
+	if err != nil {
+		return nil, err
+	}
+
+	return &verifyReader{
+		reader:  zipReader,
+		limited: io.LimitReader(zipReader, file.Size+1),
+		hash:    sha256.New(),
+		file:    file,
+	}, nil
+}
+
+// ReadJSON is function to decode JSON entry into data
+func (r *Reader) ReadJSON(name string, data interface{}) (err error) {
+	reader, err := r.Open(name)
+	if err != nil {
+		return err
+	}
+	defer reader.Close()
+
+	decoder := json.NewDecoder(reader)
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(data)
+	if err != nil {
+		return fmt.Errorf("entry %q is invalid: %w", name, err)
+	}
+
+	// read until end of entry, so its checksum is verified
+	_, err = io.Copy(io.Discard, reader)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ValidateName is function to validate entry name is relative slash separated path without parent reference
+func ValidateName(name string) (err error) {
+	if name == "" || strings.Contains(name, `\`) || path.IsAbs(name) || path.Clean(name) != name || name == "." ||
+		name == ".." || strings.HasPrefix(name, "../") {
+		return fmt.Errorf("entry name %q is not valid", name)
+	}
+
+	return nil
+}
+
+// verifyReader is reader of entry which verifies size and checksum once entry is fully read
+type verifyReader struct {
+	reader   io.ReadCloser
+	limited  io.Reader
+	hash     hash.Hash
+	file     File
+	readSize int64
+}
+
+func (v *verifyReader) Read(p []byte) (n int, err error) {
+	n, err = v.limited.Read(p)
+	v.readSize += int64(n)
+	if v.readSize > v.file.Size {
+		return n, fmt.Errorf("entry %q is larger than its manifest size", v.file.Name)
+	}
+	v.hash.Write(p[:n])
+	if errors.Is(err, io.EOF) {
+		if v.readSize != v.file.Size {
+			return n, fmt.Errorf("entry %q size does not match manifest", v.file.Name)
+		}
+		if hex.EncodeToString(v.hash.Sum(nil)) != v.file.SHA256 {
+			return n, fmt.Errorf("entry %q checksum does not match manifest", v.file.Name)
+		}
+	}
+
+	return n, err
+}
+
+func (v *verifyReader) Close() error {
+	return v.reader.Close()
+}
@@ -0,0 +1,256 @@
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+type monster struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// writeArchive is function to write archive of monster data and image
+func writeArchive(t *testing.T) []byte {
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer, 20231216080000, time.Date(2023, 12, 17, 8, 0, 0, 0, time.UTC))
+	assert.Nil(t, writer.WriteJSON("data/monsters.json", []monster{{ID: "1", Name: "Bulbasaur"}}))
+	assert.Nil(t, writer.Write("images/bulbasaur.png", strings.NewReader("image")))
+	_, err := writer.Close()
+	assert.Nil(t, err)
+
+	return buffer.Bytes()
+}
+
+// rewriteArchive is function to rewrite archive entries, entry is dropped if edit returns nil
+func rewriteArchive(t *testing.T, data []byte, edit func(name string, content []byte) []byte) []byte {
+	zipReader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	assert.Nil(t, err)
+
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for _, file := range zipReader.File {
+		reader, err := file.Open()
+		assert.Nil(t, err)
+		content, err := io.ReadAll(reader)
+		assert.Nil(t, err)
+		reader.Close()
+		content = edit(file.Name, content)
+		if content == nil {
+			continue
+		}
+		entry, err := zipWriter.Create(file.Name)
+		assert.Nil(t, err)
+		_, err = entry.Write(content)
+		assert.Nil(t, err)
+	}
+	assert.Nil(t, zipWriter.Close())
+
+	return buffer.Bytes()
+}
+
+// editManifest is function to rewrite manifest of archive
+func editManifest(t *testing.T, data []byte, edit func(manifest *Manifest)) []byte {
+	return rewriteArchive(t, data, func(name string, content []byte) []byte {
+		if name != ManifestName {
+			return content
+		}
+		var manifest Manifest
+		assert.Nil(t, json.Unmarshal(content, &manifest))
+		edit(&manifest)
+		content, err := json.Marshal(manifest)
+		assert.Nil(t, err)
+
+		return content
+	})
+}
+
+func TestOpenReader(t *testing.T) {
+	archive := writeArchive(t)
+
+	// argument
+	type args struct {
+		data []byte
+	}
+
+	// test case
+	tests := []struct {
+		name          string
+		args          args
+		wantOpenErr   bool
+		wantVerifyErr bool
+	}{
+		// success scenario: test with archive of writer
+		{
+			name:          "Success_With_Archive_Of_Writer",
+			args:          args{data: archive},
+			wantOpenErr:   false,
+			wantVerifyErr: false,
+		},
+		// failed scenario: test with archive without manifest
+		{
+			name: "Failed_With_Archive_Without_Manifest",
+			args: args{data: rewriteArchive(t, archive, func(name string, content []byte) []byte {
+				if name == ManifestName {
+					return nil
+				}
+				return content
+			})},
+			wantOpenErr: true,
+		},
+		// failed scenario: test with unsupported format version
+		{
+			name: "Failed_With_Unsupported_Format_Version",
+			args: args{data: editManifest(t, archive, func(manifest *Manifest) {
+				manifest.FormatVersion = FormatVersion + 1
+			})},
+			wantOpenErr: true,
+		},
+		// failed scenario: test with entry of manifest not found in archive
+		{
+			name: "Failed_With_Missing_Entry",
+			args: args{data: rewriteArchive(t, archive, func(name string, content []byte) []byte {
+				if name == "images/bulbasaur.png" {
+					return nil
+				}
+				return content
+			})},
+			wantOpenErr: true,
+		},
+		// failed scenario: test with entry not listed in manifest
+		{
+			name: "Failed_With_Unlisted_Entry",
+			args: args{data: editManifest(t, archive, func(manifest *Manifest) {
+				manifest.Files = manifest.Files[:1]
+			})},
+			wantOpenErr: true,
+		},
+		// failed scenario: test with entry name outside archive
+		{
+			name: "Failed_With_Unsafe_Entry_Name",
+			args: args{data: editManifest(t, archive, func(manifest *Manifest) {
+				manifest.Files = append(manifest.Files, File{Name: "../etc/passwd"})
+			})},
+			wantOpenErr: true,
+		},
+		// failed scenario: test with tampered entry
+		{
+			name: "Failed_With_Tampered_Entry",
+			args: args{data: rewriteArchive(t, archive, func(name string, content []byte) []byte {
+				if name == "images/bulbasaur.png" {
+					return []byte("imagx")
+				}
+				return content
+			})},
+			wantOpenErr:   false,
+			wantVerifyErr: true,
+		},
+		// failed scenario: test with truncated entry
+		{
+			name: "Failed_With_Truncated_Entry",
+			args: args{data: rewriteArchive(t, archive, func(name string, content []byte) []byte {
+				if name == "images/bulbasaur.png" {
+					return []byte("img")
+				}
+				return content
+			})},
+			wantOpenErr:   false,
+			wantVerifyErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := OpenReader(bytes.NewReader(tt.args.data), int64(len(tt.args.data)))
+			if tt.wantOpenErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			err = reader.Verify()
+			if tt.wantVerifyErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, reader.Manifest.SchemaVersion, uint(20231216080000))
+			assert.Equal(t, reader.Names("images/"), []string{"images/bulbasaur.png"})
+
+			var gotMonsters []monster
+			assert.Nil(t, reader.ReadJSON("data/monsters.json", &gotMonsters))
+			assert.Equal(t, gotMonsters, []monster{{ID: "1", Name: "Bulbasaur"}})
+		})
+	}
+}
+
+func TestValidateName(t *testing.T) {
+	// argument
+	type args struct {
+		name string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// success scenario: test with nested name
+		{
+			name:    "Success_With_Nested_Name",
+			args:    args{name: "images/bulbasaur.png"},
+			wantErr: false,
+		},
+		// failed scenario: test with empty name
+		{
+			name:    "Failed_With_Empty_Name",
+			args:    args{name: ""},
+			wantErr: true,
+		},
+		// failed scenario: test with absolute name
+		{
+			name:    "Failed_With_Absolute_Name",
+			args:    args{name: "/images/bulbasaur.png"},
+			wantErr: true,
+		},
+		// failed scenario: test with parent reference
+		{
+			name:    "Failed_With_Parent_Reference",
+			args:    args{name: "images/../../bulbasaur.png"},
+			wantErr: true,
+		},
+		// failed scenario: test with backslash
+		{
+			name:    "Failed_With_Backslash",
+			args:    args{name: `images\bulbasaur.png`},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateName(tt.args.name)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
+
+func TestWriterDuplicateEntry(t *testing.T) {
+	var buffer bytes.Buffer
+	writer := NewWriter(&buffer, 1, time.Now())
+	assert.Nil(t, writer.Write("images/bulbasaur.png", strings.NewReader("image")))
+	assert.NotNil(t, writer.Write("images/bulbasaur.png", strings.NewReader("image")))
+	assert.NotNil(t, writer.Write(ManifestName, strings.NewReader("{}")))
+}
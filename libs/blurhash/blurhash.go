@@ -0,0 +1,202 @@
+package blurhash
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"strings"
+)
+
+const characters = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz#$%*+,-.:;=?@[]^_{|}~"
+
+// maxSample is maximum sampled pixel per axis, bigger image is sampled to keep encoding fast
+const maxSample = 100
+
+// Encode is function to encode image into BlurHash string with given x and y components
+func Encode(img image.Image, xComponents, yComponents int) (hash string, err error) {
+	if xComponents < 1 || xComponents > 9 || yComponents < 1 || yComponents > 9 {
+		return "", errors.New("blurhash components must be between 1 and 9")
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return "", errors.New("image is empty")
+	}
+
+	// sample pixel in linear color space
+	pixels, width, height := samplePixels(img)
+
+	// compute factors of each component
+	factors := make([][3]float64, 0, xComponents*yComponents)
+	for j := 0; j < yComponents; j++ {
+		for i := 0; i < xComponents; i++ {
+			normalisation := 2.0
+			if i == 0 && j == 0 {
+				normalisation = 1.0
+			}
+			var r, g, b float64
+			for y := 0; y < height; y++ {
+				basisY := math.Cos(math.Pi * float64(j) * float64(y) / float64(height))
+				for x := 0; x < width; x++ {
+					basis := math.Cos(math.Pi*float64(i)*float64(x)/float64(width)) * basisY
+					pixel := pixels[y*width+x]
+					r += basis * pixel[0]
+					g += basis * pixel[1]
+					b += basis * pixel[2]
+				}
+			}
+			scale := normalisation / float64(width*height)
+			factors = append(factors, [3]float64{r * scale, g * scale, b * scale})
+		}
+	}
+
+	var builder strings.Builder
+
+	// size flag
+	builder.WriteString(encode83((xComponents-1)+(yComponents-1)*9, 1))
+
+	// maximum AC component value
+	maximumValue := 1.0
+	if len(factors) > 1 {
+		actualMaximumValue := 0.0
+		for i := 1; i < len(factors); i++ {
+			for c := 0; c < 3; c++ {
+				actualMaximumValue = math.Max(actualMaximumValue, math.Abs(factors[i][c]))
+			}
+		}
+		quantisedMaximumValue := int(math.Max(0, math.Min(82, math.Floor(actualMaximumValue*166-0.5))))
+		maximumValue = float64(quantisedMaximumValue+1) / 166
+		builder.WriteString(encode83(quantisedMaximumValue, 1))
+	} else {
+		builder.WriteString(encode83(0, 1))
+	}
+
+	// DC component
+	builder.WriteString(encode83(encodeDC(factors[0]), 4))
+
+	// AC component
+	for i := 1; i < len(factors); i++ {
+		builder.WriteString(encode83(encodeAC(factors[i], maximumValue), 2))
+	}
+
+	return builder.String(), nil
+}
+
+// DominantColor is function to get most frequent color of image as hex color
+func DominantColor(img image.Image) (hexColor string) {
+	bounds := img.Bounds()
+	stepX := stepOf(bounds.Dx())
+	stepY := stepOf(bounds.Dy())
+
+	// group color into 4 bit per channel bucket
+	type bucket struct {
+		count   int
+		r, g, b int
+	}
+	buckets := map[int]*bucket{}
+	bestKey := -1
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a == 0 {
+				continue
+			}
+			r8, g8, b8 := int(r>>8), int(g>>8), int(b>>8)
+			key := (r8>>4)<<8 | (g8>>4)<<4 | b8>>4
+			current, ok := buckets[key]
+			if !ok {
+				current = &bucket{}
+				buckets[key] = current
+			}
+			current.count++
+			current.r += r8
+			current.g += g8
+			current.b += b8
+			if bestKey == -1 || current.count > buckets[bestKey].count {
+				bestKey = key
+			}
+		}
+	}
+	if bestKey == -1 {
+		return ""
+	}
+
+	best := buckets[bestKey]
+	return fmt.Sprintf("#%02x%02x%02x", best.r/best.count, best.g/best.count, best.b/best.count)
+}
+
+// samplePixels is
+func samplePixels(img image.Image) (pixels [][3]float64, width, height int) {
+	bounds := img.Bounds()
+	stepX := stepOf(bounds.Dx())
+	stepY := stepOf(bounds.Dy())
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += stepY {
+		width = 0
+		for x := bounds.Min.X; x < bounds.Max.X; x += stepX {
+			r, g, b, _ := img.At(x, y).RGBA()
+			pixels = append(pixels, [3]float64{
+				sRGBToLinear(int(r >> 8)),
+				sRGBToLinear(int(g >> 8)),
+				sRGBToLinear(int(b >> 8)),
+			})
+			width++
+		}
+		height++
+	}
+	return pixels, width, height
+}
+
+// stepOf is
+func stepOf(size int) int {
+	step := size / maxSample
+	if step < 1 {
+		return 1
+	}
+	return step
+}
+
+// encodeDC is
+func encodeDC(value [3]float64) int {
+	return linearToSRGB(value[0])<<16 + linearToSRGB(value[1])<<8 + linearToSRGB(value[2])
+}
+
+// encodeAC is
+func encodeAC(value [3]float64, maximumValue float64) int {
+	quant := func(v float64) int {
+		return int(math.Max(0, math.Min(18, math.Floor(signPow(v/maximumValue, 0.5)*9+9.5))))
+	}
+	return quant(value[0])*19*19 + quant(value[1])*19 + quant(value[2])
+}
+
+// encode83 is
+func encode83(value, length int) string {
+	result := make([]byte, length)
+	for i := 1; i <= length; i++ {
+		digit := (value / int(math.Pow(83, float64(length-i)))) % 83
+		result[i-1] = characters[digit]
+	}
+	return string(result)
+}
+
+// sRGBToLinear is
+func sRGBToLinear(value int) float64 {
+	v := float64(value) / 255
+	if v <= 0.04045 {
+		return v / 12.92
+	}
+	return math.Pow((v+0.055)/1.055, 2.4)
+}
+
+// linearToSRGB is
+func linearToSRGB(value float64) int {
+	v := math.Max(0, math.Min(1, value))
+	if v <= 0.0031308 {
+		return int(v*12.92*255 + 0.5)
+	}
+	return int((1.055*math.Pow(v, 1/2.4)-0.055)*255 + 0.5)
+}
+
+// signPow is
+func signPow(value, exp float64) float64 {
+	return math.Copysign(math.Pow(math.Abs(value), exp), value)
+}
@@ -0,0 +1,164 @@
+package blurhash
+
+import (
+	"github.com/stretchr/testify/assert"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	// argument
+	type args struct {
+		img         image.Image
+		xComponents int
+		yComponents int
+	}
+	red := solidImage(32, 32, color.RGBA{R: 255, A: 255})
+	halfImage := solidImage(64, 32, color.RGBA{R: 255, A: 255})
+	for y := 0; y < 32; y++ {
+		for x := 32; x < 64; x++ {
+			halfImage.Set(x, y, color.RGBA{B: 255, A: 255})
+		}
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantHash string
+		wantErr  bool
+	}{
+		// success scenario: test with solid color image
+		{
+			name: "Success_With_Solid_Color_Image",
+			args: args{
+				img:         red,
+				xComponents: 4,
+				yComponents: 3,
+			},
+			wantHash: "L9TI:j|cfQ|c|co1fQo1fQfQfQfQ",
+			wantErr:  false,
+		},
+		// success scenario: test with DC component only
+		{
+			name: "Success_With_DC_Component_Only",
+			args: args{
+				img:         red,
+				xComponents: 1,
+				yComponents: 1,
+			},
+			wantHash: "00TI:j",
+			wantErr:  false,
+		},
+		// success scenario: test with two color image
+		{
+			name: "Success_With_Two_Color_Image",
+			args: args{
+				img:         halfImage,
+				xComponents: 4,
+				yComponents: 3,
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with invalid components
+		{
+			name: "Failed_With_Invalid_Components",
+			args: args{
+				img:         red,
+				xComponents: 10,
+				yComponents: 3,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with empty image
+		{
+			name: "Failed_With_Empty_Image",
+			args: args{
+				img:         image.NewRGBA(image.Rect(0, 0, 0, 0)),
+				xComponents: 4,
+				yComponents: 3,
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHash, err := Encode(tt.args.img, tt.args.xComponents, tt.args.yComponents)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Empty(t, gotHash)
+			} else {
+				assert.Nil(t, err)
+				assert.Len(t, gotHash, 4+2*tt.args.xComponents*tt.args.yComponents)
+				if tt.wantHash != "" {
+					assert.Equal(t, gotHash, tt.wantHash)
+				}
+			}
+		})
+	}
+}
+
+func TestDominantColor(t *testing.T) {
+	// argument
+	type args struct {
+		img image.Image
+	}
+	mostlyGreen := solidImage(10, 10, color.RGBA{G: 128, A: 255})
+	for x := 0; x < 10; x++ {
+		mostlyGreen.Set(x, 0, color.RGBA{R: 255, A: 255})
+	}
+
+	// test case
+	tests := []struct {
+		name         string
+		args         args
+		wantHexColor string
+	}{
+		// success scenario: test with solid color image
+		{
+			name: "Success_With_Solid_Color_Image",
+			args: args{
+				img: solidImage(10, 10, color.RGBA{R: 18, G: 52, B: 86, A: 255}),
+			},
+			wantHexColor: "#123456",
+		},
+		// success scenario: test with mostly one color image
+		{
+			name: "Success_With_Mostly_One_Color_Image",
+			args: args{
+				img: mostlyGreen,
+			},
+			wantHexColor: "#008000",
+		},
+		// success scenario: test with transparent image
+		{
+			name: "Success_With_Transparent_Image",
+			args: args{
+				img: image.NewRGBA(image.Rect(0, 0, 10, 10)),
+			},
+			wantHexColor: "",
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotHexColor := DominantColor(tt.args.img)
+			assert.Equal(t, gotHexColor, tt.wantHexColor)
+		})
+	}
+}
+
+// solidImage is
+func solidImage(width, height int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
@@ -0,0 +1,22 @@
+package constants
+
+import "time"
+
+const (
+	// BackupTimeout is maximum duration of single backup or restore
+	BackupTimeout = 30 * time.Minute
+	// RestoreStrategyReplace is restore strategy which replaces every monster data with data of archive
+	RestoreStrategyReplace = "replace"
+	// RestoreStrategyMerge is restore strategy which upserts data of archive and keeps other monster
+	RestoreStrategyMerge = "merge"
+)
+
+const (
+	BackupMonsterCategoryFile       = "data/monster_categories.json"
+	BackupMonsterTypeFile           = "data/monster_types.json"
+	BackupMonsterFile               = "data/monsters.json"
+	BackupMappingMonsterAndTypeFile = "data/mapping_monster_and_types.json"
+	BackupMonsterImageFile          = "data/monster_images.json"
+	BackupMonsterRevisionFile       = "data/monster_revisions.json"
+	BackupImageDirectory            = "images/"
+)
@@ -0,0 +1,11 @@
+package constants
+
+const (
+	// BulkMaxOperation is maximum number of operation in single bulk request
+	BulkMaxOperation = 500
+
+	// operation of bulk monster
+	BulkOperationCreate = "create"
+	BulkOperationUpdate = "update"
+	BulkOperationDelete = "delete"
+)
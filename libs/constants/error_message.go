@@ -6,4 +6,7 @@ const (
 	PostgresInvalidEnv  = "invalid postgres database environment"
 	MigrationInvalidEnv = "invalid migration environment"
 	JWTKeyEnv           = "invalid JWT key"
+	ImageGCInvalidEnv   = "invalid image garbage collector environment"
+	ImageInvalidEnv     = "invalid image environment"
+	TrashInvalidEnv     = "invalid trash environment"
 )
@@ -0,0 +1,10 @@
+package constants
+
+import "time"
+
+const (
+	// ExportTimeout is maximum duration of single export, export of large table outlives request context timeout
+	ExportTimeout = 30 * time.Minute
+	// ExportTypeSeparator is separator of monster type name in CSV export file, same as import so export can be read back
+	ExportTypeSeparator = ImportTypeSeparator
+)
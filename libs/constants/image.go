@@ -0,0 +1,9 @@
+package constants
+
+const (
+	ImageDirectory = "./images"
+
+	// BlurHash component of image placeholder
+	BlurHashXComponents = 4
+	BlurHashYComponents = 3
+)
@@ -0,0 +1,12 @@
+package constants
+
+const (
+	// ImportMaxFileSize is maximum size of import file
+	ImportMaxFileSize = 10 * 1024 * 1024
+	// ImportMaxArchiveSize is maximum size of image archive of import
+	ImportMaxArchiveSize = 100 * 1024 * 1024
+	// ImportMaxImageSize is maximum uncompressed size of single image in image archive
+	ImportMaxImageSize = 10 * 1024 * 1024
+	// ImportTypeSeparator is separator of monster type name in CSV import file
+	ImportTypeSeparator = "|"
+)
@@ -0,0 +1,8 @@
+package constants
+
+import "time"
+
+const (
+	// PokeAPIImportTimeout is maximum duration of single PokeAPI dataset import
+	PokeAPIImportTimeout = 30 * time.Minute
+)
@@ -0,0 +1,17 @@
+package constants
+
+const (
+	// ActorIdKey is key of authenticated user id in request context
+	ActorIdKey = "actor_id"
+
+	// action of monster revision, image is change of primary image of gallery and image check is missing image flag
+	// of image garbage collector
+	RevisionActionCreate     = "create"
+	RevisionActionUpdate     = "update"
+	RevisionActionCapture    = "capture"
+	RevisionActionDelete     = "delete"
+	RevisionActionRevert     = "revert"
+	RevisionActionRestore    = "restore"
+	RevisionActionImage      = "image"
+	RevisionActionImageCheck = "image_check"
+)
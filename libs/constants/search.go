@@ -0,0 +1,40 @@
+package constants
+
+const (
+	// SearchConfig is text search configuration of monster search vector, query must use the same configuration
+	SearchConfig = "english"
+	// SortByRelevance is sort of list monster by full-text search rank, it is only valid with search query
+	SortByRelevance = "relevance"
+	// SortMaxKeys is maximum number of key of multi-key sort
+	SortMaxKeys = 5
+	// SearchSimilarityThreshold is minimum trigram similarity of monster name to match name filter with typo, 0.3 is also
+	// the default threshold of pg_trgm similarity operator which lets the query use trigram index
+	SearchSimilarityThreshold = 0.3
+	// SuggestDefaultLimit and SuggestMaxLimit are number of monster name returned by autocomplete
+	SuggestDefaultLimit = 10
+	SuggestMaxLimit     = 50
+	// SearchMaxQueryLength is maximum length of full-text search query
+	SearchMaxQueryLength = 255
+	// MonsterNameMaxLength is maximum length of monster name, it follows the column size
+	MonsterNameMaxLength = 255
+	// SimilarDefaultLimit and SimilarMaxLimit are number of similar monster returned
+	SimilarDefaultLimit = 10
+	SimilarMaxLimit     = 50
+	// SuggestMaxPrefixLength is maximum length of autocomplete prefix
+	SuggestMaxPrefixLength = 100
+)
+
+const (
+	// MonsterTypeMatchAny and MonsterTypeMatchAll are matching of monster type filter, any matches monster with at least
+	// one of the type and all matches monster with every type
+	MonsterTypeMatchAny = "any"
+	MonsterTypeMatchAll = "all"
+)
+
+const (
+	// MonsterFacetType, MonsterFacetCategory, and MonsterFacetCaught are facet of list monster counted by monster type,
+	// monster category, and caught mark
+	MonsterFacetType     = "type"
+	MonsterFacetCategory = "category"
+	MonsterFacetCaught   = "caught"
+)
@@ -8,4 +8,12 @@ const (
 	MonsterTypeTable       = "monster_types"
 	MonsterTable           = "monsters"
 	MappingMonsterAndTypes = "mapping_monster_and_types"
+	MonsterImageTable      = "monster_images"
+	MonsterRevisionTable   = "monster_revisions"
+	SavedSearchTable       = "saved_searches"
+
+	MonsterCodeSequence = "monsters_monster_code_seq"
+
+	MonsterCodeConstraint       = "monsters_monster_code_key"
+	MonsterExternalIdConstraint = "monsters_external_id_key"
 )
@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+)
+
+// Change is change of a field, from is null when field did not exist before
+type Change struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
+
+// Compare is function to get field level change between two value, field is compared by its JSON representation
+// nil before or after means the whole value did not exist
+func Compare(before, after interface{}) (changes map[string]Change, err error) {
+	beforeMap, err := toMap(before)
+	if err != nil {
+		return nil, err
+	}
+	afterMap, err := toMap(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes = map[string]Change{}
+	for key, beforeValue := range beforeMap {
+		afterValue, ok := afterMap[key]
+		if !ok {
+			changes[key] = Change{From: beforeValue, To: nil}
+			continue
+		}
+		if !reflect.DeepEqual(beforeValue, afterValue) {
+			changes[key] = Change{From: beforeValue, To: afterValue}
+		}
+	}
+	for key, afterValue := range afterMap {
+		if _, ok := beforeMap[key]; !ok {
+			changes[key] = Change{From: nil, To: afterValue}
+		}
+	}
+
+	return changes, nil
+}
+
+// Fields is function to get sorted changed field name
+func Fields(changes map[string]Change) (fields []string) {
+	for key := range changes {
+		fields = append(fields, key)
+	}
+	sort.Strings(fields)
+
+	return fields
+}
+
+// toMap is
+func toMap(value interface{}) (res map[string]interface{}, err error) {
+	res = map[string]interface{}{}
+	if value == nil || (reflect.ValueOf(value).Kind() == reflect.Ptr && reflect.ValueOf(value).IsNil()) {
+		return res, nil
+	}
+
+	dataJSON, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(dataJSON, &res)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
@@ -0,0 +1,109 @@
+package diff
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+type monster struct {
+	Name        string   `json:"name"`
+	HP          uint16   `json:"hp"`
+	Description string   `json:"description"`
+	Types       []string `json:"types"`
+}
+
+func TestCompare(t *testing.T) {
+	// argument
+	type args struct {
+		before interface{}
+		after  interface{}
+	}
+
+	// test case
+	tests := []struct {
+		name        string
+		args        args
+		wantChanges map[string]Change
+		wantErr     bool
+	}{
+		// success scenario: test with changed field
+		{
+			name: "Success_With_Changed_Field",
+			args: args{
+				before: monster{Name: "Bulbasaur", HP: 45, Description: "Seed", Types: []string{"grass"}},
+				after:  monster{Name: "Bulbasaur", HP: 0, Description: "", Types: []string{"grass", "poison"}},
+			},
+			wantChanges: map[string]Change{
+				"hp":          {From: float64(45), To: float64(0)},
+				"description": {From: "Seed", To: ""},
+				"types":       {From: []interface{}{"grass"}, To: []interface{}{"grass", "poison"}},
+			},
+			wantErr: false,
+		},
+		// success scenario: test without changed field
+		{
+			name: "Success_Without_Changed_Field",
+			args: args{
+				before: monster{Name: "Bulbasaur", HP: 45},
+				after:  &monster{Name: "Bulbasaur", HP: 45},
+			},
+			wantChanges: map[string]Change{},
+			wantErr:     false,
+		},
+		// success scenario: test with nil before
+		{
+			name: "Success_With_Nil_Before",
+			args: args{
+				before: (*monster)(nil),
+				after:  monster{Name: "Bulbasaur", HP: 45},
+			},
+			wantChanges: map[string]Change{
+				"name":        {From: nil, To: "Bulbasaur"},
+				"hp":          {From: nil, To: float64(45)},
+				"description": {From: nil, To: ""},
+				"types":       {From: nil, To: nil},
+			},
+			wantErr: false,
+		},
+		// success scenario: test with nil after
+		{
+			name: "Success_With_Nil_After",
+			args: args{
+				before: map[string]interface{}{"name": "Bulbasaur"},
+				after:  nil,
+			},
+			wantChanges: map[string]Change{
+				"name": {From: "Bulbasaur", To: nil},
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with value which cannot be marshalled
+		{
+			name: "Failed_With_Unsupported_Value",
+			args: args{
+				before: map[string]interface{}{"channel": make(chan int)},
+				after:  nil,
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotChanges, err := Compare(tt.args.before, tt.args.after)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotChanges, tt.wantChanges)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	// test
+	gotFields := Fields(map[string]Change{"hp": {}, "attack": {}, "name": {}})
+	assert.Equal(t, gotFields, []string{"attack", "hp", "name"})
+}
@@ -0,0 +1,36 @@
+package encrypt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignURL is function to create HMAC-SHA256 signature of url path and its expiry time
+func SignURL(path string, expires int64, signingKey string) (signature string) {
+	mac := hmac.New(sha256.New, []byte(signingKey))
+	mac.Write([]byte(fmt.Sprintf("%s:%d", path, expires)))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyURL is function to verify signature and expiry time of signed url path
+func VerifyURL(path, expires, signature, signingKey string) (err error) {
+	if expires == "" || signature == "" {
+		return errors.New("url is not signed")
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return errors.New("invalid url expiry time")
+	}
+	if !hmac.Equal([]byte(signature), []byte(SignURL(path, expiresUnix, signingKey))) {
+		return errors.New("invalid url signature")
+	}
+	if expiresUnix <= time.Now().Unix() {
+		return errors.New("url expired")
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package encrypt
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSignURL(t *testing.T) {
+	// argument
+	type args struct {
+		path       string
+		expires    int64
+		signingKey string
+	}
+
+	// test case
+	tests := []struct {
+		name          string
+		args          args
+		wantSignature string
+	}{
+		// success scenario: test with image path
+		{
+			name: "Success_With_Image_Path",
+			args: args{
+				path:       "/api/v1/monster/images/monster_1.png",
+				expires:    1701434210,
+				signingKey: "Unit Testing",
+			},
+			wantSignature: "ZlurVFh2TR7PoBRYgQygdFdEuSFy1E3FJ3U-dcMg0Sk",
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSignature := SignURL(tt.args.path, tt.args.expires, tt.args.signingKey)
+			assert.Equal(t, gotSignature, tt.wantSignature)
+			assert.Len(t, gotSignature, 43)
+			assert.NotEqual(t, gotSignature, SignURL(tt.args.path, tt.args.expires+1, tt.args.signingKey))
+			assert.NotEqual(t, gotSignature, SignURL(tt.args.path+"x", tt.args.expires, tt.args.signingKey))
+			assert.NotEqual(t, gotSignature, SignURL(tt.args.path, tt.args.expires, tt.args.signingKey+"x"))
+		})
+	}
+}
+
+func TestVerifyURL(t *testing.T) {
+	// argument
+	type args struct {
+		path       string
+		expires    string
+		signature  string
+		signingKey string
+	}
+	path := "/api/v1/monster/images/monster_1.png"
+	validExpires := time.Now().Add(time.Hour).Unix()
+	expiredExpires := time.Now().Add(-time.Hour).Unix()
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		// success scenario: test with valid signature
+		{
+			name: "Success_With_Valid_Signature",
+			args: args{
+				path:       path,
+				expires:    strconv.FormatInt(validExpires, 10),
+				signature:  SignURL(path, validExpires, "UnitTesting"),
+				signingKey: "UnitTesting",
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with expired url
+		{
+			name: "Failed_With_Expired_URL",
+			args: args{
+				path:       path,
+				expires:    strconv.FormatInt(expiredExpires, 10),
+				signature:  SignURL(path, expiredExpires, "UnitTesting"),
+				signingKey: "UnitTesting",
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with extended expiry time
+		{
+			name: "Failed_With_Tampered_Expiry",
+			args: args{
+				path:       path,
+				expires:    strconv.FormatInt(validExpires+3600, 10),
+				signature:  SignURL(path, validExpires, "UnitTesting"),
+				signingKey: "UnitTesting",
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with signature of another image
+		{
+			name: "Failed_With_Another_Path_Signature",
+			args: args{
+				path:       path,
+				expires:    strconv.FormatInt(validExpires, 10),
+				signature:  SignURL("/api/v1/monster/images/monster_2.png", validExpires, "UnitTesting"),
+				signingKey: "UnitTesting",
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with unsigned url
+		{
+			name: "Failed_With_Unsigned_URL",
+			args: args{
+				path:       path,
+				signingKey: "UnitTesting",
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with invalid expiry time
+		{
+			name: "Failed_With_Invalid_Expiry",
+			args: args{
+				path:       path,
+				expires:    "tomorrow",
+				signature:  SignURL(path, validExpires, "UnitTesting"),
+				signingKey: "UnitTesting",
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyURL(tt.args.path, tt.args.expires, tt.args.signature, tt.args.signingKey)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package exporter
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+	FormatJSON   = "json"
+)
+
+// Row is monster of export file, category and type are exported by name
+type Row struct {
+	ID          string    `json:"id"`
+	MonsterCode uint16    `json:"monster_code"`
+	Name        string    `json:"name"`
+	Category    string    `json:"category"`
+	Types       []string  `json:"types"`
+	Description string    `json:"description"`
+	Length      float32   `json:"length"`
+	Weight      uint16    `json:"weight"`
+	HP          uint16    `json:"hp"`
+	Attack      uint16    `json:"attack"`
+	Defends     uint16    `json:"defends"`
+	Speed       uint16    `json:"speed"`
+	IsCaught    bool      `json:"is_caught"`
+	Image       string    `json:"image"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// Writer is writer of export file, row is written as soon as it is given so memory usage does not grow with row count
+// Close must be called after the last row to complete the file
+type Writer interface {
+	Write(row Row) error
+	Close() error
+}
+
+var csvHeader = []string{"id", "monster_code", "name", "category", "types", "description", "length", "weight", "hp", "attack", "defends", "speed", "is_caught", "image", "created_at", "updated_at"}
+
+var contentType = map[string]string{
+	FormatCSV:    "text/csv; charset=utf-8",
+	FormatNDJSON: "application/x-ndjson",
+	FormatJSON:   "application/json",
+}
+
+// ContentType is function to get content type of export format, it fails if format is not supported
+func ContentType(format string) (res string, err error) {
+	res, ok := contentType[format]
+	if !ok {
+		return "", fmt.Errorf("export format %q is not supported, format must be csv, ndjson, or json", format)
+	}
+
+	return res, nil
+}
+
+// NewWriter is function to create writer of given export format
+func NewWriter(format string, writer io.Writer, typeSeparator string) (res Writer, err error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(writer, typeSeparator)
+	case FormatNDJSON:
+		return &jsonWriter{writer: bufio.NewWriter(writer)}, nil
+	case FormatJSON:
+		return &jsonWriter{writer: bufio.NewWriter(writer), isArray: true}, nil
+	}
+
+	return nil, fmt.Errorf("export format %q is not supported, format must be csv, ndjson, or json", format)
+}
+
+type csvWriter struct {
+	writer        *csv.Writer
+	typeSeparator string
+}
+
+func newCSVWriter(writer io.Writer, typeSeparator string) (res *csvWriter, err error) {
+	res = &csvWriter{
+		writer:        csv.NewWriter(writer),
+		typeSeparator: typeSeparator,
+	}
+
+	// header is written first, so empty export still has its columns
+	err = res.writer.Write(csvHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Write is function to write row as CSV record
+func (w *csvWriter) Write(row Row) (err error) {
+	return w.writer.Write([]string{
+		row.ID,
+		strconv.FormatUint(uint64(row.MonsterCode), 10),
+		row.Name,
+		row.Category,
+		strings.Join(row.Types, w.typeSeparator),
+		row.Description,
+		strconv.FormatFloat(float64(row.Length), 'f', -1, 32),
+		strconv.FormatUint(uint64(row.Weight), 10),
+		strconv.FormatUint(uint64(row.HP), 10),
+		strconv.FormatUint(uint64(row.Attack), 10),
+		strconv.FormatUint(uint64(row.Defends), 10),
+		strconv.FormatUint(uint64(row.Speed), 10),
+		strconv.FormatBool(row.IsCaught),
+		row.Image,
+		row.CreatedAt.UTC().Format(time.RFC3339),
+		row.UpdatedAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// Close is function to flush buffered CSV record
+func (w *csvWriter) Close() (err error) {
+	w.writer.Flush()
+
+	return w.writer.Error()
+}
+
+// jsonWriter is writer of NDJSON, one object per line, or of JSON array when isArray is true
+type jsonWriter struct {
+	writer  *bufio.Writer
+	isArray bool
+	count   int
+}
+
+// Write is function to write row as JSON object
+func (w *jsonWriter) Write(row Row) (err error) {
+	if row.Types == nil {
+		row.Types = []string{}
+	}
+	data, err := json.Marshal(row)
+	if err != nil {
+		return err
+	}
+
+	separator := ""
+	if w.isArray {
+		separator = ",\n"
+		if w.count == 0 {
+			separator = "[\n"
+		}
+	}
+	_, err = w.writer.WriteString(separator)
+	if err != nil {
+		return err
+	}
+	_, err = w.writer.Write(data)
+	if err != nil {
+		return err
+	}
+	if !w.isArray {
+		err = w.writer.WriteByte('\n')
+		if err != nil {
+			return err
+		}
+	}
+	w.count++
+
+	return nil
+}
+
+// Close is function to close JSON array and flush buffered object
+func (w *jsonWriter) Close() (err error) {
+	if w.isArray {
+		closing := "\n]\n"
+		if w.count == 0 {
+			closing = "[]\n"
+		}
+		_, err = w.writer.WriteString(closing)
+		if err != nil {
+			return err
+		}
+	}
+
+	return w.writer.Flush()
+}
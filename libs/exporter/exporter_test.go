@@ -0,0 +1,202 @@
+package exporter
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"time"
+)
+
+var (
+	bulbasaur = Row{
+		ID:          "8f2c3d3e-5a4b-4d1c-9c9e-1f1f1f1f1f1f",
+		MonsterCode: 1,
+		Name:        "Bulbasaur",
+		Category:    "Seed",
+		Types:       []string{"Grass", "Poison"},
+		Description: "A strange seed, \"planted\" on its back",
+		Length:      0.7,
+		Weight:      69,
+		HP:          45,
+		Attack:      49,
+		Defends:     49,
+		Speed:       45,
+		IsCaught:    true,
+		Image:       "bulbasaur.png",
+		CreatedAt:   time.Date(2023, 12, 1, 8, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2023, 12, 2, 8, 0, 0, 0, time.UTC),
+	}
+	mew = Row{
+		ID:          "0b6e9c2a-7d3f-4e8a-8b1c-2e2e2e2e2e2e",
+		MonsterCode: 151,
+		Name:        "Mew",
+		Category:    "New Species",
+		Description: "Mew",
+		Length:      0.4,
+		Weight:      40,
+		HP:          100,
+		Attack:      100,
+		Defends:     100,
+		Speed:       100,
+		CreatedAt:   time.Date(2023, 12, 3, 8, 0, 0, 0, time.UTC),
+		UpdatedAt:   time.Date(2023, 12, 3, 8, 0, 0, 0, time.UTC),
+	}
+)
+
+func TestContentType(t *testing.T) {
+	// argument
+	type args struct {
+		format string
+	}
+
+	// test case
+	tests := []struct {
+		name            string
+		args            args
+		wantContentType string
+		wantErr         bool
+	}{
+		// success scenario: test with csv format
+		{
+			name:            "Success_With_CSV_Format",
+			args:            args{format: FormatCSV},
+			wantContentType: "text/csv; charset=utf-8",
+			wantErr:         false,
+		},
+		// success scenario: test with ndjson format
+		{
+			name:            "Success_With_NDJSON_Format",
+			args:            args{format: FormatNDJSON},
+			wantContentType: "application/x-ndjson",
+			wantErr:         false,
+		},
+		// success scenario: test with json format
+		{
+			name:            "Success_With_JSON_Format",
+			args:            args{format: FormatJSON},
+			wantContentType: "application/json",
+			wantErr:         false,
+		},
+		// failed scenario: test with unsupported format
+		{
+			name:    "Failed_With_Unsupported_Format",
+			args:    args{format: "xlsx"},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotContentType, err := ContentType(tt.args.format)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotContentType, tt.wantContentType)
+			}
+		})
+	}
+}
+
+func TestNewWriter(t *testing.T) {
+	// argument
+	type args struct {
+		format string
+		rows   []Row
+	}
+
+	// test case
+	tests := []struct {
+		name       string
+		args       args
+		wantOutput string
+		wantErr    bool
+	}{
+		// success scenario: test with csv format
+		{
+			name: "Success_With_CSV_Format",
+			args: args{format: FormatCSV, rows: []Row{bulbasaur, mew}},
+			wantOutput: "id,monster_code,name,category,types,description,length,weight,hp,attack,defends,speed,is_caught,image,created_at,updated_at\n" +
+				"8f2c3d3e-5a4b-4d1c-9c9e-1f1f1f1f1f1f,1,Bulbasaur,Seed,Grass|Poison,\"A strange seed, \"\"planted\"\" on its back\",0.7,69,45,49,49,45,true,bulbasaur.png,2023-12-01T08:00:00Z,2023-12-02T08:00:00Z\n" +
+				"0b6e9c2a-7d3f-4e8a-8b1c-2e2e2e2e2e2e,151,Mew,New Species,,Mew,0.4,40,100,100,100,100,false,,2023-12-03T08:00:00Z,2023-12-03T08:00:00Z\n",
+			wantErr: false,
+		},
+		// success scenario: test with csv format without row
+		{
+			name:       "Success_With_CSV_Format_Without_Row",
+			args:       args{format: FormatCSV},
+			wantOutput: "id,monster_code,name,category,types,description,length,weight,hp,attack,defends,speed,is_caught,image,created_at,updated_at\n",
+			wantErr:    false,
+		},
+		// success scenario: test with ndjson format
+		{
+			name:       "Success_With_NDJSON_Format",
+			args:       args{format: FormatNDJSON, rows: []Row{bulbasaur, mew}},
+			wantOutput: jsonOf(t, bulbasaur) + "\n" + jsonOf(t, mew) + "\n",
+			wantErr:    false,
+		},
+		// success scenario: test with ndjson format without row
+		{
+			name:       "Success_With_NDJSON_Format_Without_Row",
+			args:       args{format: FormatNDJSON},
+			wantOutput: "",
+			wantErr:    false,
+		},
+		// success scenario: test with json format
+		{
+			name:       "Success_With_JSON_Format",
+			args:       args{format: FormatJSON, rows: []Row{bulbasaur, mew}},
+			wantOutput: "[\n" + jsonOf(t, bulbasaur) + ",\n" + jsonOf(t, mew) + "\n]\n",
+			wantErr:    false,
+		},
+		// success scenario: test with json format without row
+		{
+			name:       "Success_With_JSON_Format_Without_Row",
+			args:       args{format: FormatJSON},
+			wantOutput: "[]\n",
+			wantErr:    false,
+		},
+		// failed scenario: test with unsupported format
+		{
+			name:    "Failed_With_Unsupported_Format",
+			args:    args{format: "xlsx"},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var output strings.Builder
+			writer, err := NewWriter(tt.args.format, &output, "|")
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			for _, row := range tt.args.rows {
+				assert.Nil(t, writer.Write(row))
+			}
+			assert.Nil(t, writer.Close())
+			assert.Equal(t, output.String(), tt.wantOutput)
+			if tt.args.format == FormatJSON {
+				var gotRows []Row
+				assert.Nil(t, json.Unmarshal([]byte(output.String()), &gotRows))
+				assert.Equal(t, len(gotRows), len(tt.args.rows))
+			}
+		})
+	}
+}
+
+// jsonOf is function to marshal row the same way as json writer, monster type is never null
+func jsonOf(t *testing.T, row Row) string {
+	if row.Types == nil {
+		row.Types = []string{}
+	}
+	data, err := json.Marshal(row)
+	assert.Nil(t, err)
+
+	return string(data)
+}
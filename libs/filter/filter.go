@@ -0,0 +1,393 @@
+package filter
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// MaxLength is maximum number of character of filter expression
+	MaxLength = 1000
+	// MaxDepth is maximum nesting of parenthesis and NOT of filter expression
+	MaxDepth = 20
+)
+
+// Kind is value type of filter field, it decides which operator and value the field accepts
+type Kind int
+
+const (
+	// KindNumber accepts number value with :, =, !=, >, >=, <, and <=
+	KindNumber Kind = iota
+	// KindText accepts word, number, or quoted text value with :, =, and !=, text is compared case insensitively
+	KindText
+	// KindBool accepts true or false with :, =, and !=, field without operator means true
+	KindBool
+)
+
+// Field is whitelisted field of filter expression, SQL of field is written by caller and never by the expression
+type Field struct {
+	Kind Kind
+	// Column is SQL expression compared with value
+	Column string
+	// Placeholder is SQL of bound value, default is ?
+	Placeholder string
+	// Match is SQL condition of text field with one ? bound by value, it is used instead of Column for field that is not
+	// a plain column such as subquery, != negates the whole condition
+	Match string
+}
+
+// SyntaxError is error of filter expression at 1-based character position
+type SyntaxError struct {
+	Position int
+	Message  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error at position %d: %s", e.Position, e.Message)
+}
+
+// Compile is function to compile filter expression into parameterized SQL condition, every value is returned as argument
+// and every field is resolved through fields, so the condition only consists of SQL written by caller
+//
+//	expr       = or
+//	or         = and { "OR" and }
+//	and        = not { "AND" not }
+//	not        = "NOT" not | primary
+//	primary    = "(" expr ")" | field [ operator value ]
+//	operator   = ":" | "=" | "!=" | ">" | ">=" | "<" | "<="
+//	value      = word | number | "quoted text"
+func Compile(input string, fields map[string]Field) (sql string, args []interface{}, err error) {
+	if utf8.RuneCountInString(input) > MaxLength {
+		return "", nil, &SyntaxError{Position: MaxLength + 1, Message: fmt.Sprintf("filter is longer than %d characters", MaxLength)}
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return "", nil, err
+	}
+
+	p := &parser{tokens: tokens, fields: fields}
+	sql, err = p.parseOr()
+	if err != nil {
+		return "", nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return "", nil, p.errorf(p.peek(), "unexpected %s", p.peek().describe())
+	}
+
+	return sql, p.args, nil
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenNumber
+	tokenText
+	tokenOperator
+	tokenOpen
+	tokenClose
+)
+
+type token struct {
+	kind     tokenKind
+	value    string
+	position int
+}
+
+// describe is function to describe token in syntax error
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of filter"
+	case tokenText:
+		return fmt.Sprintf("text %q", t.value)
+	default:
+		return fmt.Sprintf("%q", t.value)
+	}
+}
+
+// tokenize is function to split filter expression into token, position is counted by character
+func tokenize(input string) (tokens []token, err error) {
+	runes := []rune(input)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		position := i + 1
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenOpen, value: "(", position: position})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenClose, value: ")", position: position})
+			i++
+		case r == ':' || r == '=':
+			tokens = append(tokens, token{kind: tokenOperator, value: string(r), position: position})
+			i++
+		case r == '!' || r == '>' || r == '<':
+			operator := string(r)
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				operator += "="
+			}
+			if operator == "!" {
+				return nil, &SyntaxError{Position: position, Message: `expected "!="`}
+			}
+			tokens = append(tokens, token{kind: tokenOperator, value: operator, position: position})
+			i += len(operator)
+		case r == '"':
+			var builder strings.Builder
+			i++
+			for {
+				if i >= len(runes) {
+					return nil, &SyntaxError{Position: position, Message: "unterminated quoted text"}
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && (runes[i+1] == '"' || runes[i+1] == '\\') {
+					builder.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if runes[i] == '"' {
+					i++
+					break
+				}
+				builder.WriteRune(runes[i])
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenText, value: builder.String(), position: position})
+		case r == '-' || r == '.' || isDigit(r):
+			start := i
+			if r == '-' {
+				i++
+			}
+			isDot := false
+			for i < len(runes) && (isDigit(runes[i]) || (runes[i] == '.' && !isDot)) {
+				isDot = isDot || runes[i] == '.'
+				i++
+			}
+			value := string(runes[start:i])
+			number, err := strconv.ParseFloat(value, 64)
+			if err != nil || math.IsInf(number, 0) {
+				return nil, &SyntaxError{Position: position, Message: fmt.Sprintf("invalid number %q", value)}
+			}
+			if i < len(runes) && isWord(runes[i]) {
+				return nil, &SyntaxError{Position: i + 1, Message: fmt.Sprintf("unexpected %q after number", runes[i])}
+			}
+			tokens = append(tokens, token{kind: tokenNumber, value: value, position: position})
+		case isWordStart(r):
+			start := i
+			for i < len(runes) && isWord(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokenWord, value: string(runes[start:i]), position: position})
+		default:
+			return nil, &SyntaxError{Position: position, Message: fmt.Sprintf("unexpected character %q", r)}
+		}
+	}
+	tokens = append(tokens, token{kind: tokenEOF, position: len(runes) + 1})
+
+	return tokens, nil
+}
+
+func isDigit(r rune) bool {
+	return r >= '0' && r <= '9'
+}
+
+func isWordStart(r rune) bool {
+	return r == '_' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+func isWord(r rune) bool {
+	return isWordStart(r) || isDigit(r) || r == '-'
+}
+
+type parser struct {
+	tokens []token
+	index  int
+	depth  int
+	fields map[string]Field
+	args   []interface{}
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.index]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.index]
+	if t.kind != tokenEOF {
+		p.index++
+	}
+	return t
+}
+
+// isKeyword is function to check next token is keyword, keyword is case insensitive
+func (p *parser) isKeyword(keyword string) bool {
+	return p.peek().kind == tokenWord && strings.EqualFold(p.peek().value, keyword)
+}
+
+func (p *parser) errorf(t token, format string, args ...interface{}) error {
+	return &SyntaxError{Position: t.position, Message: fmt.Sprintf(format, args...)}
+}
+
+func (p *parser) parseOr() (sql string, err error) {
+	return p.parseList("OR", p.parseAnd)
+}
+
+func (p *parser) parseAnd() (sql string, err error) {
+	return p.parseList("AND", p.parseNot)
+}
+
+// parseList is function to parse operand joined by keyword, single operand is returned as is
+func (p *parser) parseList(keyword string, parseOperand func() (string, error)) (sql string, err error) {
+	operand, err := parseOperand()
+	if err != nil {
+		return "", err
+	}
+	operands := []string{operand}
+	for p.isKeyword(keyword) {
+		p.next()
+		operand, err = parseOperand()
+		if err != nil {
+			return "", err
+		}
+		operands = append(operands, operand)
+	}
+	if len(operands) == 1 {
+		return operand, nil
+	}
+
+	return "(" + strings.Join(operands, " "+keyword+" ") + ")", nil
+}
+
+func (p *parser) parseNot() (sql string, err error) {
+	if !p.isKeyword("NOT") {
+		return p.parsePrimary()
+	}
+	t := p.next()
+	err = p.enter(t)
+	if err != nil {
+		return "", err
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return "", err
+	}
+	p.depth--
+
+	return "NOT (" + operand + ")", nil
+}
+
+// enter is function to limit nesting, so deeply nested expression cannot exhaust the stack
+func (p *parser) enter(t token) error {
+	p.depth++
+	if p.depth > MaxDepth {
+		return p.errorf(t, "filter is nested deeper than %d", MaxDepth)
+	}
+	return nil
+}
+
+func (p *parser) parsePrimary() (sql string, err error) {
+	t := p.next()
+	switch {
+	case t.kind == tokenOpen:
+		err = p.enter(t)
+		if err != nil {
+			return "", err
+		}
+		sql, err = p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if p.peek().kind != tokenClose {
+			return "", p.errorf(p.peek(), `expected ")" to close "(" at position %d, found %s`, t.position, p.peek().describe())
+		}
+		p.next()
+		p.depth--
+		return sql, nil
+	case t.kind == tokenWord && !isReserved(t.value):
+		return p.parseComparison(t)
+	default:
+		return "", p.errorf(t, "expected field, NOT or \"(\", found %s", t.describe())
+	}
+}
+
+func isReserved(word string) bool {
+	return strings.EqualFold(word, "AND") || strings.EqualFold(word, "OR") || strings.EqualFold(word, "NOT")
+}
+
+func (p *parser) parseComparison(fieldToken token) (sql string, err error) {
+	field, ok := p.fields[strings.ToLower(fieldToken.value)]
+	if !ok {
+		return "", p.errorf(fieldToken, "unknown field %q", fieldToken.value)
+	}
+
+	// bool field without operator means true
+	if p.peek().kind != tokenOperator {
+		if field.Kind == KindBool {
+			return p.compare(field, "=", true), nil
+		}
+		return "", p.errorf(p.peek(), "expected operator after field %q, found %s", fieldToken.value, p.peek().describe())
+	}
+	operatorToken := p.next()
+	operator := operatorToken.value
+	if operator == ":" {
+		operator = "="
+	}
+	valueToken := p.next()
+
+	switch field.Kind {
+	case KindNumber:
+		if valueToken.kind != tokenNumber {
+			return "", p.errorf(valueToken, "field %q expects number, found %s", fieldToken.value, valueToken.describe())
+		}
+		value, _ := strconv.ParseFloat(valueToken.value, 64)
+		return p.compare(field, operator, value), nil
+	case KindText:
+		if operator != "=" && operator != "!=" {
+			return "", p.errorf(operatorToken, "field %q does not support operator %q", fieldToken.value, operatorToken.value)
+		}
+		if valueToken.kind != tokenWord && valueToken.kind != tokenNumber && valueToken.kind != tokenText {
+			return "", p.errorf(valueToken, "field %q expects text, found %s", fieldToken.value, valueToken.describe())
+		}
+		return p.compare(field, operator, valueToken.value), nil
+	default:
+		if operator != "=" && operator != "!=" {
+			return "", p.errorf(operatorToken, "field %q does not support operator %q", fieldToken.value, operatorToken.value)
+		}
+		value, err := strconv.ParseBool(strings.ToLower(valueToken.value))
+		if valueToken.kind != tokenWord || err != nil {
+			return "", p.errorf(valueToken, "field %q expects true or false, found %s", fieldToken.value, valueToken.describe())
+		}
+		return p.compare(field, operator, value), nil
+	}
+}
+
+// compare is function to write condition of field with value bound as argument
+func (p *parser) compare(field Field, operator string, value interface{}) (sql string) {
+	p.args = append(p.args, value)
+	placeholder := field.Placeholder
+	if placeholder == "" {
+		placeholder = "?"
+	}
+
+	if field.Kind == KindText {
+		sql = "lower(" + field.Column + ") = lower(" + placeholder + ")"
+		if field.Match != "" {
+			sql = field.Match
+		}
+		if operator == "!=" {
+			return "NOT (" + sql + ")"
+		}
+		return "(" + sql + ")"
+	}
+	if operator == "!=" {
+		operator = "<>"
+	}
+
+	return "(" + field.Column + " " + operator + " " + placeholder + ")"
+}
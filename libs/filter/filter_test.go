@@ -0,0 +1,224 @@
+package filter
+
+import (
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+var testFields = map[string]Field{
+	"hp":     {Kind: KindNumber, Column: "monsters.hp"},
+	"speed":  {Kind: KindNumber, Column: "monsters.speed"},
+	"length": {Kind: KindNumber, Column: "CAST(monsters.length AS real)", Placeholder: "CAST(? AS real)"},
+	"name":   {Kind: KindText, Column: "monsters.name"},
+	"type":   {Kind: KindText, Match: "EXISTS (SELECT 1 FROM monster_types mt WHERE lower(mt.name) = lower(?))"},
+	"caught": {Kind: KindBool, Column: "monsters.is_caught"},
+}
+
+func TestCompile(t *testing.T) {
+	// argument
+	type args struct {
+		input string
+	}
+
+	// test case
+	tests := []struct {
+		name         string
+		args         args
+		wantSQL      string
+		wantArgs     []interface{}
+		wantPosition int
+	}{
+		// success scenario: test with single comparison
+		{
+			name: "Success_With_Single_Comparison",
+			args: args{
+				input: "hp>=80",
+			},
+			wantSQL:  "(monsters.hp >= ?)",
+			wantArgs: []interface{}{float64(80)},
+		},
+		// success scenario: test with precedence of AND over OR and NOT
+		{
+			name: "Success_With_Precedence",
+			args: args{
+				input: "type:FIRE AND (hp>=80 OR speed>100) AND NOT caught",
+			},
+			wantSQL:  "((EXISTS (SELECT 1 FROM monster_types mt WHERE lower(mt.name) = lower(?))) AND ((monsters.hp >= ?) OR (monsters.speed > ?)) AND NOT ((monsters.is_caught = ?)))",
+			wantArgs: []interface{}{"FIRE", float64(80), float64(100), true},
+		},
+		// success scenario: test with OR without parenthesis
+		{
+			name: "Success_With_OR_Without_Parenthesis",
+			args: args{
+				input: "hp<10 or hp>100 and caught:false",
+			},
+			wantSQL:  "((monsters.hp < ?) OR ((monsters.hp > ?) AND (monsters.is_caught = ?)))",
+			wantArgs: []interface{}{float64(10), float64(100), false},
+		},
+		// success scenario: test with quoted text and not equal
+		{
+			name: "Success_With_Quoted_Text",
+			args: args{
+				input: `name!="Mr. \"Mime\""`,
+			},
+			wantSQL:  "NOT (lower(monsters.name) = lower(?))",
+			wantArgs: []interface{}{`Mr. "Mime"`},
+		},
+		// success scenario: test with placeholder and decimal number
+		{
+			name: "Success_With_Placeholder",
+			args: args{
+				input: "length<=0.7",
+			},
+			wantSQL:  "(CAST(monsters.length AS real) <= CAST(? AS real))",
+			wantArgs: []interface{}{0.7},
+		},
+		// success scenario: test with value which looks like SQL
+		{
+			name: "Success_With_SQL_Like_Value",
+			args: args{
+				input: `name:"x') OR 1=1 --"`,
+			},
+			wantSQL:  "(lower(monsters.name) = lower(?))",
+			wantArgs: []interface{}{"x') OR 1=1 --"},
+		},
+		// failed scenario: test with unknown field
+		{
+			name: "Failed_With_Unknown_Field",
+			args: args{
+				input: "hp>1 AND password:x",
+			},
+			wantPosition: 10,
+		},
+		// failed scenario: test with text value of number field
+		{
+			name: "Failed_With_Text_Value_Of_Number_Field",
+			args: args{
+				input: "hp>=high",
+			},
+			wantPosition: 5,
+		},
+		// failed scenario: test with unsupported operator of text field
+		{
+			name: "Failed_With_Unsupported_Operator",
+			args: args{
+				input: "type>FIRE",
+			},
+			wantPosition: 5,
+		},
+		// failed scenario: test with unclosed parenthesis
+		{
+			name: "Failed_With_Unclosed_Parenthesis",
+			args: args{
+				input: "(hp>1 OR speed>1",
+			},
+			wantPosition: 17,
+		},
+		// failed scenario: test with trailing token
+		{
+			name: "Failed_With_Trailing_Token",
+			args: args{
+				input: "hp>1 speed>1",
+			},
+			wantPosition: 6,
+		},
+		// failed scenario: test with unterminated quoted text
+		{
+			name: "Failed_With_Unterminated_Quoted_Text",
+			args: args{
+				input: `name:"abc`,
+			},
+			wantPosition: 6,
+		},
+		// failed scenario: test with unexpected character
+		{
+			name: "Failed_With_Unexpected_Character",
+			args: args{
+				input: "hp>1; DROP TABLE monsters",
+			},
+			wantPosition: 5,
+		},
+		// failed scenario: test with too deep nesting
+		{
+			name: "Failed_With_Too_Deep_Nesting",
+			args: args{
+				input: strings.Repeat("(", MaxDepth+1) + "caught" + strings.Repeat(")", MaxDepth+1),
+			},
+			wantPosition: MaxDepth + 1,
+		},
+		// failed scenario: test with empty filter
+		{
+			name: "Failed_With_Empty_Filter",
+			args: args{
+				input: "  ",
+			},
+			wantPosition: 3,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs, err := Compile(tt.args.input, testFields)
+			if tt.wantPosition > 0 {
+				var syntaxErr *SyntaxError
+				assert.True(t, errors.As(err, &syntaxErr))
+				if syntaxErr != nil {
+					assert.Equal(t, syntaxErr.Position, tt.wantPosition)
+				}
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, gotSQL, tt.wantSQL)
+			assert.Equal(t, gotArgs, tt.wantArgs)
+		})
+	}
+}
+
+// FuzzCompile checks that any input either compiles into condition which only has SQL of field and every value bound
+// as argument, or fails with syntax error positioned inside the input
+func FuzzCompile(f *testing.F) {
+	seeds := []string{
+		"type:FIRE AND (hp>=80 OR speed>100) AND NOT caught",
+		`name!="Mr. \"Mime\"" or length<=0.7`,
+		"NOT NOT (caught:false)",
+		`name:"x') OR 1=1 --"`,
+		"((((hp>1))))",
+		"hp>-1.5 and speed<.5",
+		"",
+		"(",
+		`"`,
+		"hp>=1e9",
+	}
+	for _, seed := range seeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, input string) {
+		sql, args, err := Compile(input, testFields)
+		if err != nil {
+			var syntaxErr *SyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("error is not syntax error: %v", err)
+			}
+			if syntaxErr.Position < 1 || syntaxErr.Position > utf8.RuneCountInString(input)+1 {
+				t.Fatalf("position %d is outside of input %q", syntaxErr.Position, input)
+			}
+			return
+		}
+		if strings.Count(sql, "?") != len(args) {
+			t.Fatalf("sql %q has %d placeholder for %d argument", sql, strings.Count(sql, "?"), len(args))
+		}
+		for _, unsafe := range []string{"'", `"`, ";", "--", "/*"} {
+			if strings.Contains(sql, unsafe) {
+				t.Fatalf("sql %q contains %q", sql, unsafe)
+			}
+		}
+		if strings.Count(sql, "(") != strings.Count(sql, ")") {
+			t.Fatalf("sql %q has unbalanced parenthesis", sql)
+		}
+	})
+}
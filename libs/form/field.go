@@ -0,0 +1,74 @@
+package form
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// FieldError is error of request field which cannot be parsed into its type
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// ParseUUID is function to parse uuid field, it returns canonical form so every accepted form of uuid reaches query the same way
+func ParseUUID(field, value string) (res string, err error) {
+	id, err := uuid.Parse(value)
+	if err != nil {
+		return "", &FieldError{Field: field, Message: "must be uuid"}
+	}
+
+	return id.String(), nil
+}
+
+// ParseBool is function to parse optional bool field, empty value is nil
+func ParseBool(field, value string) (res *bool, err error) {
+	if value == "" {
+		return nil, nil
+	}
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		return nil, &FieldError{Field: field, Message: "must be true or false"}
+	}
+
+	return &boolValue, nil
+}
+
+// ParseText is function to validate free text field, text is returned unchanged so punctuation such as ' ; * % is kept
+// since text is only used as query argument, control character and invalid UTF-8 are rejected
+func ParseText(field, value string, maxLength int) (res string, err error) {
+	if !utf8.ValidString(value) {
+		return "", &FieldError{Field: field, Message: "must be valid UTF-8"}
+	}
+	if utf8.RuneCountInString(value) > maxLength {
+		return "", &FieldError{Field: field, Message: fmt.Sprintf("must be at most %d characters", maxLength)}
+	}
+	if strings.IndexFunc(value, unicode.IsControl) >= 0 {
+		return "", &FieldError{Field: field, Message: "must not contain control character"}
+	}
+
+	return value, nil
+}
+
+// ParseList is function to parse comma separated list such as fields=id,name, item is trimmed and lowercased, empty and
+// duplicate item is removed
+func ParseList(value string) (res []string) {
+	isExist := map[string]bool{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.ToLower(strings.TrimSpace(item))
+		if item != "" && !isExist[item] {
+			isExist[item] = true
+			res = append(res, item)
+		}
+	}
+
+	return res
+}
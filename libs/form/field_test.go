@@ -0,0 +1,325 @@
+package form
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"unicode"
+	"unicode/utf8"
+)
+
+// punctuatedNames is monster name with punctuation which must reach query unchanged
+var punctuatedNames = []string{
+	"Farfetch'd",
+	"Sirfetch'd",
+	"Mr. Mime",
+	"Type: Null",
+	"Nidoran♀",
+	"Flabébé",
+	"Porygon-Z",
+	"Ho-Oh",
+	`"Quoted" Name`,
+	"Name; DROP TABLE monsters; --",
+	"100% *Star* _Wild_ \\ $Money$ ^Up^",
+	"http://example.com/x",
+}
+
+func TestParseUUID(t *testing.T) {
+	// argument
+	type args struct {
+		value string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes string
+		wantErr bool
+	}{
+		// success scenario: test with canonical uuid
+		{
+			name: "Success_With_Canonical_UUID",
+			args: args{
+				value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+			},
+			wantRes: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		},
+		// success scenario: test with uppercase uuid
+		{
+			name: "Success_With_Uppercase_UUID",
+			args: args{
+				value: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8",
+			},
+			wantRes: "6ba7b810-9dad-11d1-80b4-00c04fd430c8",
+		},
+		// failed scenario: test with injection
+		{
+			name: "Failed_With_Injection",
+			args: args{
+				value: "6ba7b810-9dad-11d1-80b4-00c04fd430c8' OR '1'='1",
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with empty value
+		{
+			name: "Failed_With_Empty_Value",
+			args: args{
+				value: "",
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := ParseUUID("id", tt.args.value)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, gotRes, tt.wantRes)
+			if err != nil {
+				assert.Equal(t, err.Error(), "id must be uuid")
+			}
+		})
+	}
+}
+
+func TestParseBool(t *testing.T) {
+	// argument
+	type args struct {
+		value string
+	}
+	isTrue, isFalse := true, false
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes *bool
+		wantErr bool
+	}{
+		// success scenario: test with empty value
+		{
+			name: "Success_With_Empty_Value",
+			args: args{
+				value: "",
+			},
+			wantRes: nil,
+		},
+		// success scenario: test with true
+		{
+			name: "Success_With_True",
+			args: args{
+				value: "true",
+			},
+			wantRes: &isTrue,
+		},
+		// success scenario: test with false
+		{
+			name: "Success_With_False",
+			args: args{
+				value: "0",
+			},
+			wantRes: &isFalse,
+		},
+		// failed scenario: test with invalid value
+		{
+			name: "Failed_With_Invalid_Value",
+			args: args{
+				value: "yes'",
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := ParseBool("is_caught", tt.args.value)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
+
+func TestParseText(t *testing.T) {
+	// argument
+	type args struct {
+		value     string
+		maxLength int
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes string
+		wantErr bool
+	}{
+		// success scenario: test with name with apostrophe
+		{
+			name: "Success_With_Apostrophe",
+			args: args{
+				value:     "Farfetch'd",
+				maxLength: 255,
+			},
+			wantRes: "Farfetch'd",
+		},
+		// success scenario: test with multibyte character at max length
+		{
+			name: "Success_With_Multibyte_At_Max_Length",
+			args: args{
+				value:     "Nidoran♀",
+				maxLength: 8,
+			},
+			wantRes: "Nidoran♀",
+		},
+		// failed scenario: test with too long value
+		{
+			name: "Failed_With_Too_Long_Value",
+			args: args{
+				value:     "Charmander",
+				maxLength: 5,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with control character
+		{
+			name: "Failed_With_Control_Character",
+			args: args{
+				value:     "Pika\x00chu",
+				maxLength: 255,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with invalid UTF-8
+		{
+			name: "Failed_With_Invalid_UTF8",
+			args: args{
+				value:     "Pika\xffchu",
+				maxLength: 255,
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := ParseText("name", tt.args.value, tt.args.maxLength)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
+
+// TestParseText_QueryRoundTrip checks that name with punctuation sent as query param reaches handler unchanged
+func TestParseList(t *testing.T) {
+	// argument
+	type args struct {
+		value string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes []string
+	}{
+		// success scenario: test with spaced, uppercase, and duplicate item
+		{
+			name: "Success_With_Spaced_Uppercase_And_Duplicate_Item",
+			args: args{
+				value: " id, Name,,hp ,name",
+			},
+			wantRes: []string{"id", "name", "hp"},
+		},
+		// success scenario: test with empty value
+		{
+			name: "Success_With_Empty_Value",
+			args: args{
+				value: " , ",
+			},
+			wantRes: nil,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := ParseList(tt.args.value)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
+
+func TestParseText_QueryRoundTrip(t *testing.T) {
+	app := fiber.New()
+	app.Get("/monster", func(ctx *fiber.Ctx) error {
+		name, err := ParseText("name", ctx.Query("name"), 255)
+		if err != nil {
+			return ctx.Status(http.StatusBadRequest).SendString(err.Error())
+		}
+		return ctx.SendString(name)
+	})
+
+	for _, name := range punctuatedNames {
+		t.Run(name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/monster?"+url.Values{"name": {name}}.Encode(), nil)
+			res, err := app.Test(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, err := io.ReadAll(res.Body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, res.StatusCode, http.StatusOK)
+			assert.Equal(t, string(body), name)
+		})
+	}
+}
+
+// FuzzParseText checks that every printable text within max length is returned unchanged, and every rejected text has
+// a reason, so text is never silently altered
+func FuzzParseText(f *testing.F) {
+	for _, name := range punctuatedNames {
+		f.Add(name)
+	}
+	f.Add("Pika\x00chu")
+	f.Add("Pika\xffchu")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		res, err := ParseText("name", value, 255)
+		isValid := utf8.ValidString(value) && utf8.RuneCountInString(value) <= 255 && strings.IndexFunc(value, unicode.IsControl) < 0
+		if isValid && (err != nil || res != value) {
+			t.Fatalf("valid text %q is changed into %q, %v", value, res, err)
+		}
+		if !isValid && err == nil {
+			t.Fatalf("invalid text %q is accepted", value)
+		}
+	})
+}
+
+// FuzzParseUUID checks that accepted uuid is always canonical, so id in query never has character outside of hex and hyphen
+func FuzzParseUUID(f *testing.F) {
+	f.Add("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	f.Add("{6ba7b810-9dad-11d1-80b4-00c04fd430c8}")
+	f.Add("urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	f.Add("6ba7b810-9dad-11d1-80b4-00c04fd430c8'--")
+
+	f.Fuzz(func(t *testing.T, value string) {
+		res, err := ParseUUID("id", value)
+		if err != nil {
+			return
+		}
+		if len(res) != 36 || strings.Trim(res, "0123456789abcdef-") != "" {
+			t.Fatalf("uuid %q is parsed into non canonical %q", value, res)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package form
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// Optional is JSON field wrapper to differentiate absent, null, and explicit value, used for JSON Merge Patch (RFC 7396)
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+// UnmarshalJSON is only called when field is present in JSON document
+func (o *Optional[T]) UnmarshalJSON(data []byte) (err error) {
+	o.Set = true
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		o.Null = true
+		return nil
+	}
+
+	return json.Unmarshal(data, &o.Value)
+}
+
+// IsValue is function to check field is present with non null value
+func (o Optional[T]) IsValue() bool {
+	return o.Set && !o.Null
+}
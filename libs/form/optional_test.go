@@ -0,0 +1,139 @@
+package form
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestOptional_UnmarshalJSON(t *testing.T) {
+	// argument
+	type args struct {
+		data string
+	}
+	type document struct {
+		HP          Optional[uint16]   `json:"hp"`
+		Description Optional[string]   `json:"description"`
+		Types       Optional[[]string] `json:"types"`
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantDoc document
+		wantErr bool
+	}{
+		// success scenario: test with absent field
+		{
+			name: "Success_With_Absent_Field",
+			args: args{
+				data: `{}`,
+			},
+			wantDoc: document{},
+			wantErr: false,
+		},
+		// success scenario: test with null field
+		{
+			name: "Success_With_Null_Field",
+			args: args{
+				data: `{"hp": null, "description": null}`,
+			},
+			wantDoc: document{
+				HP:          Optional[uint16]{Set: true, Null: true},
+				Description: Optional[string]{Set: true, Null: true},
+			},
+			wantErr: false,
+		},
+		// success scenario: test with zero value field
+		{
+			name: "Success_With_Zero_Value_Field",
+			args: args{
+				data: `{"hp": 0, "description": "", "types": []}`,
+			},
+			wantDoc: document{
+				HP:          Optional[uint16]{Set: true},
+				Description: Optional[string]{Set: true},
+				Types:       Optional[[]string]{Set: true, Value: []string{}},
+			},
+			wantErr: false,
+		},
+		// success scenario: test with value field
+		{
+			name: "Success_With_Value_Field",
+			args: args{
+				data: `{"hp": 45, "types": ["grass"]}`,
+			},
+			wantDoc: document{
+				HP:    Optional[uint16]{Set: true, Value: 45},
+				Types: Optional[[]string]{Set: true, Value: []string{"grass"}},
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with invalid value type
+		{
+			name: "Failed_With_Invalid_Value_Type",
+			args: args{
+				data: `{"hp": "45"}`,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with out of range value
+		{
+			name: "Failed_With_Out_Of_Range_Value",
+			args: args{
+				data: `{"hp": 70000}`,
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotDoc document
+			err := json.Unmarshal([]byte(tt.args.data), &gotDoc)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotDoc, tt.wantDoc)
+			}
+		})
+	}
+}
+
+func TestOptional_IsValue(t *testing.T) {
+	// test case
+	tests := []struct {
+		name        string
+		optional    Optional[string]
+		wantIsValue bool
+	}{
+		// success scenario: test with absent field
+		{
+			name:        "Success_With_Absent_Field",
+			optional:    Optional[string]{},
+			wantIsValue: false,
+		},
+		// success scenario: test with null field
+		{
+			name:        "Success_With_Null_Field",
+			optional:    Optional[string]{Set: true, Null: true},
+			wantIsValue: false,
+		},
+		// success scenario: test with zero value field
+		{
+			name:        "Success_With_Zero_Value_Field",
+			optional:    Optional[string]{Set: true},
+			wantIsValue: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.optional.IsValue(), tt.wantIsValue)
+		})
+	}
+}
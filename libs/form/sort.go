@@ -0,0 +1,52 @@
+package form
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Sort is one key of multi-key sort
+type Sort struct {
+	Field string `json:"field"`
+	Desc  bool   `json:"desc"`
+}
+
+// ParseSort is function to parse comma separated sort keys such as -hp,name, key prefixed with - is descending and key
+// without prefix or prefixed with + is ascending, every field must be in fields and can only be used once
+func ParseSort[T any](input string, fields map[string]T, maxKeys int) (res []Sort, err error) {
+	keys := strings.Split(input, ",")
+	if len(keys) > maxKeys {
+		return nil, fmt.Errorf("sort accepts at most %d keys", maxKeys)
+	}
+
+	isExist := map[string]bool{}
+	for i := 0; i < len(keys); i++ {
+		key := strings.TrimSpace(keys[i])
+		desc := strings.HasPrefix(key, "-")
+		key = strings.TrimPrefix(strings.TrimPrefix(key, "-"), "+")
+		if key == "" {
+			return nil, fmt.Errorf("sort key %d is empty", i+1)
+		}
+		if _, ok := fields[key]; !ok {
+			return nil, fmt.Errorf("sort field %q is not valid, valid field: %s", key, strings.Join(sortFieldNames(fields), ", "))
+		}
+		if isExist[key] {
+			return nil, fmt.Errorf("sort field %q is used more than once", key)
+		}
+		isExist[key] = true
+		res = append(res, Sort{Field: key, Desc: desc})
+	}
+
+	return res, nil
+}
+
+// sortFieldNames is function to get ordered name of sortable field for error message
+func sortFieldNames[T any](fields map[string]T) (res []string) {
+	for field := range fields {
+		res = append(res, field)
+	}
+	sort.Strings(res)
+
+	return res
+}
@@ -0,0 +1,94 @@
+package form
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	// argument
+	type args struct {
+		input   string
+		fields  map[string]bool
+		maxKeys int
+	}
+	fields := map[string]bool{"hp": true, "name": true, "stat_total": true}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes []Sort
+		wantErr bool
+	}{
+		// success scenario: test with single ascending key
+		{
+			name: "Success_With_Single_Ascending_Key",
+			args: args{
+				input:   "name",
+				fields:  fields,
+				maxKeys: 3,
+			},
+			wantRes: []Sort{{Field: "name"}},
+		},
+		// success scenario: test with multiple key
+		{
+			name: "Success_With_Multiple_Key",
+			args: args{
+				input:   "-hp, +name,-stat_total",
+				fields:  fields,
+				maxKeys: 3,
+			},
+			wantRes: []Sort{{Field: "hp", Desc: true}, {Field: "name"}, {Field: "stat_total", Desc: true}},
+		},
+		// failed scenario: test with unknown field
+		{
+			name: "Failed_With_Unknown_Field",
+			args: args{
+				input:   "-hp,id; DROP TABLE monsters",
+				fields:  fields,
+				maxKeys: 3,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with empty key
+		{
+			name: "Failed_With_Empty_Key",
+			args: args{
+				input:   "hp,,name",
+				fields:  fields,
+				maxKeys: 3,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with duplicate field
+		{
+			name: "Failed_With_Duplicate_Field",
+			args: args{
+				input:   "hp,-hp",
+				fields:  fields,
+				maxKeys: 3,
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with too many key
+		{
+			name: "Failed_With_Too_Many_Key",
+			args: args{
+				input:   "hp,name,stat_total",
+				fields:  fields,
+				maxKeys: 2,
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := ParseSort(tt.args.input, tt.args.fields, tt.args.maxKeys)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package importer
+
+import (
+	"archive/zip"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// Archive is zip of image referenced by import file, image is matched by its base file name case-insensitively
+type Archive struct {
+	files        map[string]*zip.File
+	maxImageSize int64
+}
+
+// OpenArchive is function to open zip image archive, entry which is directory is ignored
+func OpenArchive(reader io.ReaderAt, size, maxImageSize int64) (archive *Archive, err error) {
+	zipReader, err := zip.NewReader(reader, size)
+	if err != nil {
+		return nil, err
+	}
+
+	archive = &Archive{
+		files:        map[string]*zip.File{},
+		maxImageSize: maxImageSize,
+	}
+	for i := 0; i < len(zipReader.File); i++ {
+		if zipReader.File[i].FileInfo().IsDir() {
+			continue
+		}
+		name := strings.ToLower(path.Base(zipReader.File[i].Name))
+		if _, ok := archive.files[name]; ok {
+			return nil, fmt.Errorf("duplicate image %q in archive", path.Base(zipReader.File[i].Name))
+		}
+		archive.files[name] = zipReader.File[i]
+	}
+
+	return archive, nil
+}
+
+// Has is function to check image exist in archive, nil archive has no image
+func (archive *Archive) Has(name string) bool {
+	if archive == nil {
+		return false
+	}
+	_, ok := archive.files[strings.ToLower(path.Base(name))]
+
+	return ok
+}
+
+// Read is function to read image from archive, image larger than maximum image size is rejected
+func (archive *Archive) Read(name string) (data []byte, err error) {
+	if !archive.Has(name) {
+		return nil, fmt.Errorf("image %q is not found in archive", name)
+	}
+	file := archive.files[strings.ToLower(path.Base(name))]
+	if file.UncompressedSize64 > uint64(archive.maxImageSize) {
+		return nil, fmt.Errorf("image %q cannot exceed %d bytes", name, archive.maxImageSize)
+	}
+
+	reader, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	// declared size of zip entry is not trusted, read is limited
+	data, err = io.ReadAll(io.LimitReader(reader, archive.maxImageSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > archive.maxImageSize {
+		return nil, fmt.Errorf("image %q cannot exceed %d bytes", name, archive.maxImageSize)
+	}
+
+	return data, nil
+}
@@ -0,0 +1,51 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestArchive(t *testing.T) {
+	// prepare image archive
+	var buffer bytes.Buffer
+	zipWriter := zip.NewWriter(&buffer)
+	for name, content := range map[string]string{"images/Bulbasaur.png": "small", "images/mew.png": "too large image"} {
+		writer, err := zipWriter.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = writer.Write([]byte(content))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := zipWriter.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err := OpenArchive(bytes.NewReader(buffer.Bytes()), int64(buffer.Len()), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// test image is matched by base file name case-insensitively
+	assert.Equal(t, archive.Has("bulbasaur.png"), true)
+	assert.Equal(t, archive.Has("ivysaur.png"), false)
+	data, err := archive.Read("BULBASAUR.PNG")
+	assert.Nil(t, err)
+	assert.Equal(t, string(data), "small")
+
+	// test image larger than maximum image size is rejected
+	_, err = archive.Read("mew.png")
+	assert.NotNil(t, err)
+
+	// test nil archive has no image
+	var nilArchive *Archive
+	assert.Equal(t, nilArchive.Has("bulbasaur.png"), false)
+
+	// test invalid archive
+	_, err = OpenArchive(bytes.NewReader([]byte("not zip")), 7, 10)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,209 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Row is monster of import file, category and type are referenced by name and image by its file name in image archive
+// row which cannot be parsed has its errors filled instead of failing the whole file
+type Row struct {
+	Line        int      `json:"-"`
+	MonsterCode uint16   `json:"monster_code"`
+	Name        string   `json:"name"`
+	Category    string   `json:"category"`
+	Types       []string `json:"types"`
+	Description string   `json:"description"`
+	Length      float32  `json:"length"`
+	Weight      uint16   `json:"weight"`
+	HP          uint16   `json:"hp"`
+	Attack      uint16   `json:"attack"`
+	Defends     uint16   `json:"defends"`
+	Speed       uint16   `json:"speed"`
+	IsCaught    bool     `json:"is_caught"`
+	Image       string   `json:"image"`
+	Errors      []string `json:"-"`
+}
+
+var (
+	requiredColumn = []string{"name", "category", "types", "description", "length", "weight", "hp", "attack", "defends", "speed"}
+	optionalColumn = []string{"monster_code", "is_caught", "image"}
+)
+
+// FormatOf is function to get import format from file name
+func FormatOf(fileName string) (format string, err error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	}
+
+	return "", errors.New("import file must be .csv or .json")
+}
+
+// Parse is function to parse import file of given format
+func Parse(format string, reader io.Reader, typeSeparator string) (rows []Row, err error) {
+	switch format {
+	case FormatCSV:
+		return ParseCSV(reader, typeSeparator)
+	case FormatJSON:
+		return ParseJSON(reader)
+	}
+
+	return nil, fmt.Errorf("import format %q is not supported", format)
+}
+
+// ParseCSV is function to parse CSV import file, first record is header and column is matched by its name
+// monster type is separated by type separator in single column
+func ParseCSV(reader io.Reader, typeSeparator string) (rows []Row, err error) {
+	csvReader := csv.NewReader(reader)
+	csvReader.TrimLeadingSpace = true
+
+	// read header
+	header, err := csvReader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("import file is empty")
+		}
+		return nil, err
+	}
+	columnIndex := map[string]int{}
+	for i := 0; i < len(header); i++ {
+		column := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(header[i], "\ufeff")))
+		if !isKnownColumn(column) {
+			return nil, fmt.Errorf("unknown column %q", header[i])
+		}
+		if _, ok := columnIndex[column]; ok {
+			return nil, fmt.Errorf("duplicate column %q", header[i])
+		}
+		columnIndex[column] = i
+	}
+	for i := 0; i < len(requiredColumn); i++ {
+		if _, ok := columnIndex[requiredColumn[i]]; !ok {
+			return nil, fmt.Errorf("column %q is required", requiredColumn[i])
+		}
+	}
+
+	// read record
+	for {
+		record, err := csvReader.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, err
+		}
+		line, _ := csvReader.FieldPos(0)
+		row := Row{Line: line}
+		value := func(column string) string {
+			index, ok := columnIndex[column]
+			if !ok {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		row.Name = value("name")
+		row.Category = value("category")
+		row.Description = value("description")
+		row.Image = value("image")
+		for _, typeName := range strings.Split(value("types"), typeSeparator) {
+			typeName = strings.TrimSpace(typeName)
+			if typeName != "" {
+				row.Types = append(row.Types, typeName)
+			}
+		}
+		row.MonsterCode = row.parseUint16("monster_code", value("monster_code"))
+		row.Weight = row.parseUint16("weight", value("weight"))
+		row.HP = row.parseUint16("hp", value("hp"))
+		row.Attack = row.parseUint16("attack", value("attack"))
+		row.Defends = row.parseUint16("defends", value("defends"))
+		row.Speed = row.parseUint16("speed", value("speed"))
+		if lengthStr := value("length"); lengthStr != "" {
+			length, err := strconv.ParseFloat(lengthStr, 32)
+			if err != nil {
+				row.Errors = append(row.Errors, "length must be number")
+			}
+			row.Length = float32(length)
+		}
+		if isCaughtStr := value("is_caught"); isCaughtStr != "" {
+			isCaught, err := strconv.ParseBool(isCaughtStr)
+			if err != nil {
+				row.Errors = append(row.Errors, "is_caught must be boolean")
+			}
+			row.IsCaught = isCaught
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// ParseJSON is function to parse JSON import file, file is array of monster object and unknown field is rejected
+func ParseJSON(reader io.Reader) (rows []Row, err error) {
+	var elements []json.RawMessage
+	err = json.NewDecoder(reader).Decode(&elements)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errors.New("import file is empty")
+		}
+		return nil, fmt.Errorf("import file must be JSON array: %w", err)
+	}
+
+	for i := 0; i < len(elements); i++ {
+		row := Row{}
+		decoder := json.NewDecoder(bytes.NewReader(elements[i]))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&row)
+		row.Line = i + 1
+		if err != nil {
+			row.Errors = append(row.Errors, err.Error())
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// isKnownColumn is
+func isKnownColumn(column string) bool {
+	for i := 0; i < len(requiredColumn); i++ {
+		if requiredColumn[i] == column {
+			return true
+		}
+	}
+	for i := 0; i < len(optionalColumn); i++ {
+		if optionalColumn[i] == column {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseUint16 is
+func (row *Row) parseUint16(column, value string) uint16 {
+	if value == "" {
+		return 0
+	}
+	number, err := strconv.ParseUint(value, 10, 16)
+	if err != nil {
+		row.Errors = append(row.Errors, fmt.Sprintf("%s must be number between 0 and 65535", column))
+		return 0
+	}
+
+	return uint16(number)
+}
@@ -0,0 +1,204 @@
+package importer
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestFormatOf(t *testing.T) {
+	// argument
+	type args struct {
+		fileName string
+	}
+
+	// test case
+	tests := []struct {
+		name       string
+		args       args
+		wantFormat string
+		wantErr    bool
+	}{
+		// success scenario: test with csv file
+		{
+			name:       "Success_With_CSV_File",
+			args:       args{fileName: "kanto.CSV"},
+			wantFormat: FormatCSV,
+			wantErr:    false,
+		},
+		// success scenario: test with json file
+		{
+			name:       "Success_With_JSON_File",
+			args:       args{fileName: "kanto.json"},
+			wantFormat: FormatJSON,
+			wantErr:    false,
+		},
+		// failed scenario: test with unsupported file
+		{
+			name:    "Failed_With_Unsupported_File",
+			args:    args{fileName: "kanto.xlsx"},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotFormat, err := FormatOf(tt.args.fileName)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotFormat, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestParseCSV(t *testing.T) {
+	// argument
+	type args struct {
+		content string
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantRows []Row
+		wantErr  bool
+	}{
+		// success scenario: test with valid row
+		{
+			name: "Success_With_Valid_Row",
+			args: args{content: "Name,Category,Types,Description,Length,Weight,HP,Attack,Defends,Speed,Monster_Code,Is_Caught,Image\n" +
+				"Bulbasaur,Seed,Grass | Poison,\"Seed, on its back\",0.7,69,45,49,49,45,1,true,bulbasaur.png\n"},
+			wantRows: []Row{
+				{
+					Line:        2,
+					MonsterCode: 1,
+					Name:        "Bulbasaur",
+					Category:    "Seed",
+					Types:       []string{"Grass", "Poison"},
+					Description: "Seed, on its back",
+					Length:      0.7,
+					Weight:      69,
+					HP:          45,
+					Attack:      49,
+					Defends:     49,
+					Speed:       45,
+					IsCaught:    true,
+					Image:       "bulbasaur.png",
+				},
+			},
+			wantErr: false,
+		},
+		// success scenario: test with row which cannot be parsed
+		{
+			name: "Success_With_Invalid_Row",
+			args: args{content: "name,category,types,description,length,weight,hp,attack,defends,speed\n" +
+				"Mew,Psychic,Psychic,New,short,4,100000,100,100,100\n"},
+			wantRows: []Row{
+				{
+					Line:        2,
+					Name:        "Mew",
+					Category:    "Psychic",
+					Types:       []string{"Psychic"},
+					Description: "New",
+					Weight:      4,
+					Attack:      100,
+					Defends:     100,
+					Speed:       100,
+					Errors:      []string{"hp must be number between 0 and 65535", "length must be number"},
+				},
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with missing required column
+		{
+			name:    "Failed_With_Missing_Column",
+			args:    args{content: "name,category\nMew,Psychic\n"},
+			wantErr: true,
+		},
+		// failed scenario: test with unknown column
+		{
+			name:    "Failed_With_Unknown_Column",
+			args:    args{content: "name,category,types,description,length,weight,hp,attack,defends,speed,color\n"},
+			wantErr: true,
+		},
+		// failed scenario: test with empty file
+		{
+			name:    "Failed_With_Empty_File",
+			args:    args{content: ""},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRows, err := ParseCSV(strings.NewReader(tt.args.content), "|")
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotRows, tt.wantRows)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	// argument
+	type args struct {
+		content string
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantRows []Row
+		wantErr  bool
+	}{
+		// success scenario: test with valid and invalid row
+		{
+			name: "Success_With_Valid_And_Invalid_Row",
+			args: args{content: `[{"name":"Bulbasaur","category":"Seed","types":["Grass"],"hp":45},{"name":"Mew","color":"pink"}]`},
+			wantRows: []Row{
+				{
+					Line:     1,
+					Name:     "Bulbasaur",
+					Category: "Seed",
+					Types:    []string{"Grass"},
+					HP:       45,
+				},
+				{
+					Line:   2,
+					Name:   "Mew",
+					Errors: []string{`json: unknown field "color"`},
+				},
+			},
+			wantErr: false,
+		},
+		// failed scenario: test with JSON object
+		{
+			name:    "Failed_With_JSON_Object",
+			args:    args{content: `{"name":"Mew"}`},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRows, err := ParseJSON(strings.NewReader(tt.args.content))
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotRows, tt.wantRows)
+			}
+		})
+	}
+}
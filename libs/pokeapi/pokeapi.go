@@ -0,0 +1,461 @@
+package pokeapi
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+
+	// englishLanguageId is id of english in languages.csv of PokeAPI
+	englishLanguageId = 9
+	englishLanguage   = "en"
+)
+
+// Pokemon is default form of pokemon of PokeAPI dataset, height and weight are in PokeAPI unit
+type Pokemon struct {
+	ID          int
+	Identifier  string
+	Name        string
+	Genus       string
+	Description string
+	Height      int // decimeter
+	Weight      int // hectogram
+	HP          int
+	Attack      int
+	Defense     int
+	Speed       int
+	Types       []Type // ordered by slot
+}
+
+// Type is type of PokeAPI dataset
+type Type struct {
+	ID         int
+	Identifier string
+}
+
+// PokemonExternalId is function to get stable external id of pokemon
+func PokemonExternalId(id int) string {
+	return fmt.Sprintf("pokeapi:pokemon:%d", id)
+}
+
+// TypeExternalId is function to get stable external id of type
+func TypeExternalId(id int) string {
+	return fmt.Sprintf("pokeapi:type:%d", id)
+}
+
+// GenusExternalId is function to get stable external id of genus, genus has no id in PokeAPI so it is keyed by its english name
+func GenusExternalId(genus string) string {
+	return fmt.Sprintf("pokeapi:genus:%s", strings.Join(strings.Fields(strings.ToLower(genus)), "-"))
+}
+
+// LengthMeter is function to convert PokeAPI height in decimeter into length in meter
+func LengthMeter(height int) float32 {
+	return float32(height) / 10
+}
+
+// WeightKilogram is function to convert PokeAPI weight in hectogram into weight in whole kilogram, weight is at least 1 kilogram
+func WeightKilogram(weight int) uint16 {
+	kilogram := math.Round(float64(weight) / 10)
+	if kilogram < 1 {
+		return 1
+	}
+	if kilogram > math.MaxUint16 {
+		return math.MaxUint16
+	}
+
+	return uint16(kilogram)
+}
+
+// FormatOf is function to detect format of PokeAPI dataset directory
+// CSV dataset is the data/v2/csv directory of PokeAPI, JSON dataset is the api/v2 directory of PokeAPI api-data
+func FormatOf(dir string) (format string, err error) {
+	info, err := os.Stat(filepath.Join(dir, "pokemon.csv"))
+	if err == nil && !info.IsDir() {
+		return FormatCSV, nil
+	}
+	info, err = os.Stat(filepath.Join(dir, "pokemon"))
+	if err == nil && info.IsDir() {
+		return FormatJSON, nil
+	}
+
+	return "", fmt.Errorf("%s is not PokeAPI dataset, it must contain pokemon.csv or pokemon directory", dir)
+}
+
+// Load is function to load default form of every pokemon from PokeAPI dataset directory, ordered by id
+func Load(dir string) (res []Pokemon, err error) {
+	format, err := FormatOf(dir)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatCSV {
+		return LoadCSV(dir)
+	}
+
+	return LoadJSON(dir)
+}
+
+// LoadCSV is function to load pokemon from PokeAPI CSV dataset directory
+// pokemon.csv, pokemon_species_names.csv, pokemon_stats.csv, stats.csv, pokemon_types.csv and types.csv are required
+// pokemon_species_flavor_text.csv is optional, description is the english flavor text of the latest version
+func LoadCSV(dir string) (res []Pokemon, err error) {
+	// pokemon
+	speciesId := map[int]int{}
+	err = readCSV(dir, "pokemon.csv", true, func(record map[string]string) error {
+		if record["is_default"] != "1" {
+			return nil
+		}
+		var err error
+		pokemon := Pokemon{Identifier: record["identifier"]}
+		pokemon.ID, err = strconv.Atoi(record["id"])
+		if err != nil {
+			return err
+		}
+		speciesId[pokemon.ID], err = strconv.Atoi(record["species_id"])
+		if err != nil {
+			return err
+		}
+		pokemon.Height, err = strconv.Atoi(record["height"])
+		if err != nil {
+			return err
+		}
+		pokemon.Weight, err = strconv.Atoi(record["weight"])
+		if err != nil {
+			return err
+		}
+		res = append(res, pokemon)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// species name and genus
+	speciesName := map[string][2]string{}
+	err = readCSV(dir, "pokemon_species_names.csv", true, func(record map[string]string) error {
+		if record["local_language_id"] == strconv.Itoa(englishLanguageId) {
+			speciesName[record["pokemon_species_id"]] = [2]string{record["name"], record["genus"]}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// species flavor text
+	flavorText := map[string]string{}
+	flavorVersion := map[string]int{}
+	err = readCSV(dir, "pokemon_species_flavor_text.csv", false, func(record map[string]string) error {
+		if record["language_id"] != strconv.Itoa(englishLanguageId) {
+			return nil
+		}
+		version, err := strconv.Atoi(record["version_id"])
+		if err != nil {
+			return err
+		}
+		if current, ok := flavorVersion[record["species_id"]]; !ok || version > current {
+			flavorVersion[record["species_id"]] = version
+			flavorText[record["species_id"]] = record["flavor_text"]
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// stat
+	statName := map[string]string{}
+	err = readCSV(dir, "stats.csv", true, func(record map[string]string) error {
+		statName[record["id"]] = record["identifier"]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	stats := map[string]map[string]int{}
+	err = readCSV(dir, "pokemon_stats.csv", true, func(record map[string]string) error {
+		baseStat, err := strconv.Atoi(record["base_stat"])
+		if err != nil {
+			return err
+		}
+		if stats[record["pokemon_id"]] == nil {
+			stats[record["pokemon_id"]] = map[string]int{}
+		}
+		stats[record["pokemon_id"]][statName[record["stat_id"]]] = baseStat
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// type
+	typeIdentifier := map[string]string{}
+	err = readCSV(dir, "types.csv", true, func(record map[string]string) error {
+		typeIdentifier[record["id"]] = record["identifier"]
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	types := map[string][]slotType{}
+	err = readCSV(dir, "pokemon_types.csv", true, func(record map[string]string) error {
+		typeId, err := strconv.Atoi(record["type_id"])
+		if err != nil {
+			return err
+		}
+		slot, err := strconv.Atoi(record["slot"])
+		if err != nil {
+			return err
+		}
+		if _, ok := typeIdentifier[record["type_id"]]; !ok {
+			return fmt.Errorf("type %d is not found in types.csv", typeId)
+		}
+		types[record["pokemon_id"]] = append(types[record["pokemon_id"]], slotType{
+			slot: slot,
+			Type: Type{ID: typeId, Identifier: typeIdentifier[record["type_id"]]},
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// mapping pokemon
+	for i := 0; i < len(res); i++ {
+		id := strconv.Itoa(res[i].ID)
+		species := strconv.Itoa(speciesId[res[i].ID])
+		name, ok := speciesName[species]
+		if !ok {
+			return nil, fmt.Errorf("english name of pokemon species %s is not found", species)
+		}
+		res[i].Name = name[0]
+		res[i].Genus = name[1]
+		res[i].Description = normalizeText(flavorText[species])
+		res[i].HP = stats[id]["hp"]
+		res[i].Attack = stats[id]["attack"]
+		res[i].Defense = stats[id]["defense"]
+		res[i].Speed = stats[id]["speed"]
+		res[i].Types = sortSlotType(types[id])
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res, nil
+}
+
+// LoadJSON is function to load pokemon from PokeAPI api-data directory, pokemon/<id>/index.json and pokemon-species/<id>/index.json are read
+func LoadJSON(dir string) (res []Pokemon, err error) {
+	pokemonFiles, err := filepath.Glob(filepath.Join(dir, "pokemon", "*", "index.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	species := map[string]jsonSpecies{}
+	for i := 0; i < len(pokemonFiles); i++ {
+		var resPokemon jsonPokemon
+		err = readJSON(pokemonFiles[i], &resPokemon)
+		if err != nil {
+			return nil, err
+		}
+		if !resPokemon.IsDefault {
+			continue
+		}
+
+		// species of pokemon
+		speciesId := path.Base(strings.TrimSuffix(resPokemon.Species.URL, "/"))
+		resSpecies, ok := species[speciesId]
+		if !ok {
+			err = readJSON(filepath.Join(dir, "pokemon-species", speciesId, "index.json"), &resSpecies)
+			if err != nil {
+				return nil, err
+			}
+			species[speciesId] = resSpecies
+		}
+
+		pokemon := Pokemon{
+			ID:         resPokemon.ID,
+			Identifier: resPokemon.Name,
+			Height:     resPokemon.Height,
+			Weight:     resPokemon.Weight,
+		}
+		for j := 0; j < len(resSpecies.Names); j++ {
+			if resSpecies.Names[j].Language.Name == englishLanguage {
+				pokemon.Name = resSpecies.Names[j].Name
+			}
+		}
+		if pokemon.Name == "" {
+			return nil, fmt.Errorf("english name of pokemon species %s is not found", speciesId)
+		}
+		for j := 0; j < len(resSpecies.Genera); j++ {
+			if resSpecies.Genera[j].Language.Name == englishLanguage {
+				pokemon.Genus = resSpecies.Genera[j].Genus
+			}
+		}
+		latestVersion := -1
+		for j := 0; j < len(resSpecies.FlavorTextEntries); j++ {
+			entry := resSpecies.FlavorTextEntries[j]
+			version, _ := strconv.Atoi(path.Base(strings.TrimSuffix(entry.Version.URL, "/")))
+			if entry.Language.Name == englishLanguage && version > latestVersion {
+				latestVersion = version
+				pokemon.Description = normalizeText(entry.FlavorText)
+			}
+		}
+		for j := 0; j < len(resPokemon.Stats); j++ {
+			switch resPokemon.Stats[j].Stat.Name {
+			case "hp":
+				pokemon.HP = resPokemon.Stats[j].BaseStat
+			case "attack":
+				pokemon.Attack = resPokemon.Stats[j].BaseStat
+			case "defense":
+				pokemon.Defense = resPokemon.Stats[j].BaseStat
+			case "speed":
+				pokemon.Speed = resPokemon.Stats[j].BaseStat
+			}
+		}
+		var types []slotType
+		for j := 0; j < len(resPokemon.Types); j++ {
+			typeId, err := strconv.Atoi(path.Base(strings.TrimSuffix(resPokemon.Types[j].Type.URL, "/")))
+			if err != nil {
+				return nil, fmt.Errorf("type url of pokemon %d is not valid: %w", resPokemon.ID, err)
+			}
+			types = append(types, slotType{
+				slot: resPokemon.Types[j].Slot,
+				Type: Type{ID: typeId, Identifier: resPokemon.Types[j].Type.Name},
+			})
+		}
+		pokemon.Types = sortSlotType(types)
+		res = append(res, pokemon)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res, nil
+}
+
+type slotType struct {
+	Type
+	slot int
+}
+
+type namedResource struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type jsonPokemon struct {
+	ID        int           `json:"id"`
+	Name      string        `json:"name"`
+	Height    int           `json:"height"`
+	Weight    int           `json:"weight"`
+	IsDefault bool          `json:"is_default"`
+	Species   namedResource `json:"species"`
+	Stats     []struct {
+		BaseStat int           `json:"base_stat"`
+		Stat     namedResource `json:"stat"`
+	} `json:"stats"`
+	Types []struct {
+		Slot int           `json:"slot"`
+		Type namedResource `json:"type"`
+	} `json:"types"`
+}
+
+type jsonSpecies struct {
+	Names []struct {
+		Name     string        `json:"name"`
+		Language namedResource `json:"language"`
+	} `json:"names"`
+	Genera []struct {
+		Genus    string        `json:"genus"`
+		Language namedResource `json:"language"`
+	} `json:"genera"`
+	FlavorTextEntries []struct {
+		FlavorText string        `json:"flavor_text"`
+		Language   namedResource `json:"language"`
+		Version    namedResource `json:"version"`
+	} `json:"flavor_text_entries"`
+}
+
+// readCSV is function to read every record of CSV file of dataset as map of column name, optional file which does not exist is skipped
+func readCSV(dir, fileName string, isRequired bool, fn func(record map[string]string) error) (err error) {
+	file, err := os.Open(filepath.Join(dir, fileName))
+	if err != nil {
+		if !isRequired && errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+	defer file.Close()
+
+	csvReader := csv.NewReader(file)
+	csvReader.ReuseRecord = true
+	header, err := csvReader.Read()
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+	header = append([]string{}, header...)
+	record := map[string]string{}
+	for {
+		values, err := csvReader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", fileName, err)
+		}
+		for i := 0; i < len(header) && i < len(values); i++ {
+			record[header[i]] = values[i]
+		}
+		err = fn(record)
+		if err != nil {
+			line, _ := csvReader.FieldPos(0)
+			return fmt.Errorf("%s line %d: %w", fileName, line, err)
+		}
+	}
+}
+
+// readJSON is function to decode JSON file of dataset
+func readJSON(fileName string, data interface{}) (err error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	err = json.NewDecoder(file).Decode(data)
+	if err != nil {
+		return fmt.Errorf("%s: %w", fileName, err)
+	}
+
+	return nil
+}
+
+// sortSlotType is function to order type by its slot
+func sortSlotType(types []slotType) (res []Type) {
+	sort.SliceStable(types, func(i, j int) bool {
+		return types[i].slot < types[j].slot
+	})
+	for i := 0; i < len(types); i++ {
+		res = append(res, types[i].Type)
+	}
+
+	return res
+}
+
+// normalizeText is function to collapse line break, form feed and repeated space of flavor text
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
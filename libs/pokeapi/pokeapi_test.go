@@ -0,0 +1,206 @@
+package pokeapi
+
+import (
+	"github.com/stretchr/testify/assert"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var wantPokemon = []Pokemon{
+	{
+		ID:          1,
+		Identifier:  "bulbasaur",
+		Name:        "Bulbasaur",
+		Genus:       "Seed Pokémon",
+		Description: "A strange seed was planted on its back at birth.",
+		Height:      7,
+		Weight:      69,
+		HP:          45,
+		Attack:      49,
+		Defense:     49,
+		Speed:       45,
+		Types:       []Type{{ID: 12, Identifier: "grass"}, {ID: 4, Identifier: "poison"}},
+	},
+	{
+		ID:         92,
+		Identifier: "gastly",
+		Name:       "Gastly",
+		Genus:      "Gas Pokémon",
+		Height:     13,
+		Weight:     1,
+		HP:         30,
+		Attack:     35,
+		Defense:    30,
+		Speed:      80,
+		Types:      []Type{{ID: 8, Identifier: "ghost"}, {ID: 4, Identifier: "poison"}},
+	},
+}
+
+// writeFiles is function to write dataset file into directory
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, content := range files {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+}
+
+// csvDataset is function to write CSV dataset, gastly types are listed out of slot order and mega venusaur is not default form
+func csvDataset(t *testing.T) string {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pokemon.csv": "id,identifier,species_id,height,weight,base_experience,order,is_default\n" +
+			"92,gastly,92,13,1,62,130,1\n" +
+			"1,bulbasaur,1,7,69,64,1,1\n" +
+			"10033,venusaur-mega,3,24,1555,281,4,0\n",
+		"pokemon_species_names.csv": "pokemon_species_id,local_language_id,name,genus\n" +
+			"1,1,フシギダネ,たねポケモン\n" +
+			"1,9,Bulbasaur,Seed Pokémon\n" +
+			"92,9,Gastly,Gas Pokémon\n",
+		"pokemon_species_flavor_text.csv": "species_id,version_id,language_id,flavor_text\n" +
+			"1,1,9,\"A strange seed was\nplanted on its back\fat birth.\"\n" +
+			"1,2,1,ふしぎな タネが\n" +
+			"1,0,9,Older text.\n",
+		"stats.csv": "id,damage_class_id,identifier,is_battle_only,game_index\n" +
+			"1,,hp,0,1\n2,2,attack,0,2\n3,2,defense,0,3\n4,3,special-attack,0,5\n5,3,special-defense,0,6\n6,,speed,0,4\n",
+		"pokemon_stats.csv": "pokemon_id,stat_id,base_stat,effort\n" +
+			"1,1,45,0\n1,2,49,0\n1,3,49,0\n1,4,65,1\n1,5,65,0\n1,6,45,0\n" +
+			"92,1,30,0\n92,2,35,0\n92,3,30,0\n92,4,100,1\n92,5,35,0\n92,6,80,0\n",
+		"types.csv": "id,identifier,generation_id,damage_class_id\n" +
+			"4,poison,1,2\n8,ghost,1,2\n12,grass,1,3\n",
+		"pokemon_types.csv": "pokemon_id,type_id,slot\n" +
+			"1,12,1\n1,4,2\n92,4,2\n92,8,1\n",
+	})
+
+	return dir
+}
+
+// jsonDataset is function to write JSON dataset of PokeAPI api-data
+func jsonDataset(t *testing.T) string {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{
+		"pokemon/1/index.json": `{"id":1,"name":"bulbasaur","height":7,"weight":69,"is_default":true,
+			"species":{"name":"bulbasaur","url":"/api/v2/pokemon-species/1/"},
+			"stats":[{"base_stat":45,"stat":{"name":"hp"}},{"base_stat":49,"stat":{"name":"attack"}},{"base_stat":49,"stat":{"name":"defense"}},
+			         {"base_stat":65,"stat":{"name":"special-attack"}},{"base_stat":45,"stat":{"name":"speed"}}],
+			"types":[{"slot":2,"type":{"name":"poison","url":"/api/v2/type/4/"}},{"slot":1,"type":{"name":"grass","url":"/api/v2/type/12/"}}]}`,
+		"pokemon/92/index.json": `{"id":92,"name":"gastly","height":13,"weight":1,"is_default":true,
+			"species":{"name":"gastly","url":"/api/v2/pokemon-species/92/"},
+			"stats":[{"base_stat":30,"stat":{"name":"hp"}},{"base_stat":35,"stat":{"name":"attack"}},{"base_stat":30,"stat":{"name":"defense"}},{"base_stat":80,"stat":{"name":"speed"}}],
+			"types":[{"slot":1,"type":{"name":"ghost","url":"/api/v2/type/8/"}},{"slot":2,"type":{"name":"poison","url":"/api/v2/type/4/"}}]}`,
+		"pokemon/10033/index.json": `{"id":10033,"name":"venusaur-mega","height":24,"weight":1555,"is_default":false,
+			"species":{"name":"venusaur","url":"/api/v2/pokemon-species/3/"},"stats":[],"types":[]}`,
+		"pokemon-species/1/index.json": `{"names":[{"name":"フシギダネ","language":{"name":"ja"}},{"name":"Bulbasaur","language":{"name":"en"}}],
+			"genera":[{"genus":"Seed Pokémon","language":{"name":"en"}}],
+			"flavor_text_entries":[{"flavor_text":"Older text.","language":{"name":"en"},"version":{"name":"red","url":"/api/v2/version/1/"}},
+			                       {"flavor_text":"A strange seed was\nplanted on its back\fat birth.","language":{"name":"en"},"version":{"name":"blue","url":"/api/v2/version/2/"}}]}`,
+		"pokemon-species/92/index.json": `{"names":[{"name":"Gastly","language":{"name":"en"}}],
+			"genera":[{"genus":"Gas Pokémon","language":{"name":"en"}}],"flavor_text_entries":[]}`,
+	})
+
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	// argument
+	type args struct {
+		dir string
+	}
+
+	// test case
+	tests := []struct {
+		name        string
+		args        args
+		wantPokemon []Pokemon
+		wantErr     bool
+	}{
+		// success scenario: test with csv dataset
+		{
+			name:        "Success_With_CSV_Dataset",
+			args:        args{dir: csvDataset(t)},
+			wantPokemon: wantPokemon,
+			wantErr:     false,
+		},
+		// success scenario: test with json dataset
+		{
+			name:        "Success_With_JSON_Dataset",
+			args:        args{dir: jsonDataset(t)},
+			wantPokemon: wantPokemon,
+			wantErr:     false,
+		},
+		// failed scenario: test with directory which is not dataset
+		{
+			name:    "Failed_With_Directory_Not_Dataset",
+			args:    args{dir: t.TempDir()},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotPokemon, err := Load(tt.args.dir)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+				assert.Equal(t, gotPokemon, tt.wantPokemon)
+			}
+		})
+	}
+}
+
+func TestLoadCSVWithMissingFile(t *testing.T) {
+	dir := csvDataset(t)
+	assert.Nil(t, os.Remove(filepath.Join(dir, "pokemon_types.csv")))
+
+	_, err := LoadCSV(dir)
+	assert.NotNil(t, err)
+}
+
+func TestWeightKilogram(t *testing.T) {
+	// argument
+	type args struct {
+		weight int
+	}
+
+	// test case
+	tests := []struct {
+		name       string
+		args       args
+		wantWeight uint16
+	}{
+		// success scenario: test with weight rounded down
+		{
+			name:       "Success_With_Weight_Rounded_Down",
+			args:       args{weight: 64},
+			wantWeight: 6,
+		},
+		// success scenario: test with weight rounded up
+		{
+			name:       "Success_With_Weight_Rounded_Up",
+			args:       args{weight: 69},
+			wantWeight: 7,
+		},
+		// success scenario: test with weight below one kilogram
+		{
+			name:       "Success_With_Weight_Below_One_Kilogram",
+			args:       args{weight: 1},
+			wantWeight: 1,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, WeightKilogram(tt.args.weight), tt.wantWeight)
+		})
+	}
+}
+
+func TestExternalId(t *testing.T) {
+	assert.Equal(t, PokemonExternalId(25), "pokeapi:pokemon:25")
+	assert.Equal(t, TypeExternalId(12), "pokeapi:type:12")
+	assert.Equal(t, GenusExternalId("Seed  Pokémon"), "pokeapi:genus:seed-pokémon")
+	assert.Equal(t, LengthMeter(7), float32(0.7))
+}
@@ -0,0 +1,108 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gofiber/fiber/v2"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrNotCacheable is returned by validator function when response must not be answered with 304
+var ErrNotCacheable = errors.New("response is not cacheable")
+
+// Validator is validator of requested resource used for conditional request
+type Validator struct {
+	ETag         string
+	LastModified time.Time
+}
+
+// ValidatorFunc is function to compute validator of requested resource without building the response body
+type ValidatorFunc func(ctx *fiber.Ctx) (validator Validator, err error)
+
+// ConditionalGet is middleware to set cache header and answer conditional GET with 304 Not Modified
+func ConditionalGet(cacheControl string, validatorFunc ValidatorFunc) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		if ctx.Method() != fiber.MethodGet && ctx.Method() != fiber.MethodHead {
+			return ctx.Next()
+		}
+
+		// compute validator, failed validator is left to handler to respond
+		validator, err := validatorFunc(ctx)
+		if err != nil {
+			return ctx.Next()
+		}
+
+		// cache header
+		if cacheControl != "" {
+			ctx.Set(fiber.HeaderCacheControl, cacheControl)
+		}
+		if validator.ETag != "" {
+			ctx.Set(fiber.HeaderETag, validator.ETag)
+		}
+		if !validator.LastModified.IsZero() {
+			ctx.Set(fiber.HeaderLastModified, validator.LastModified.UTC().Format(http.TimeFormat))
+		}
+
+		// not modified
+		if IsNotModified(ctx.Get(fiber.HeaderIfNoneMatch), ctx.Get(fiber.HeaderIfModifiedSince), validator) {
+			return ctx.SendStatus(http.StatusNotModified)
+		}
+
+		// modified, error response is not cached
+		err = ctx.Next()
+		if ctx.Response().StatusCode() >= http.StatusMultipleChoices {
+			ctx.Response().Header.Del(fiber.HeaderCacheControl)
+			ctx.Response().Header.Del(fiber.HeaderETag)
+			ctx.Response().Header.Del(fiber.HeaderLastModified)
+		}
+
+		return err
+	}
+}
+
+// IsNotModified is function to evaluate If-None-Match and If-Modified-Since (RFC 7232), If-None-Match takes precedence
+func IsNotModified(ifNoneMatch, ifModifiedSince string, validator Validator) bool {
+	ifNoneMatch = strings.TrimSpace(ifNoneMatch)
+	if ifNoneMatch != "" {
+		if validator.ETag == "" {
+			return false
+		}
+		if ifNoneMatch == "*" {
+			return true
+		}
+
+		// weak comparison
+		eTag := strings.TrimPrefix(validator.ETag, "W/")
+		for _, requestETag := range strings.Split(ifNoneMatch, ",") {
+			if strings.TrimPrefix(strings.TrimSpace(requestETag), "W/") == eTag {
+				return true
+			}
+		}
+		return false
+	}
+
+	ifModifiedSince = strings.TrimSpace(ifModifiedSince)
+	if ifModifiedSince != "" && !validator.LastModified.IsZero() {
+		since, err := http.ParseTime(ifModifiedSince)
+		if err != nil {
+			return false
+		}
+		return !validator.LastModified.Truncate(time.Second).After(since)
+	}
+
+	return false
+}
+
+// FingerprintETag is function to build strong entity tag from given parts
+func FingerprintETag(parts ...interface{}) (eTag string) {
+	hash := sha256.New()
+	for i := 0; i < len(parts); i++ {
+		_, _ = fmt.Fprintf(hash, "%v|", parts[i])
+	}
+
+	return fmt.Sprintf(`"%s"`, hex.EncodeToString(hash.Sum(nil)[:16]))
+}
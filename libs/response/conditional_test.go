@@ -0,0 +1,222 @@
+package response
+
+import (
+	"errors"
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestIsNotModified(t *testing.T) {
+	// argument
+	type args struct {
+		ifNoneMatch     string
+		ifModifiedSince string
+		validator       Validator
+	}
+	lastModified := time.Date(2023, 12, 1, 10, 0, 0, 500, time.UTC)
+
+	// test case
+	tests := []struct {
+		name              string
+		args              args
+		wantIsNotModified bool
+	}{
+		// success scenario: test with matching entity tag
+		{
+			name: "Success_With_Matching_ETag",
+			args: args{
+				ifNoneMatch: `"1", "2"`,
+				validator:   Validator{ETag: `"2"`},
+			},
+			wantIsNotModified: true,
+		},
+		// success scenario: test with weak matching entity tag
+		{
+			name: "Success_With_Weak_Matching_ETag",
+			args: args{
+				ifNoneMatch: `W/"2"`,
+				validator:   Validator{ETag: `"2"`},
+			},
+			wantIsNotModified: true,
+		},
+		// success scenario: test with wildcard entity tag
+		{
+			name: "Success_With_Wildcard_ETag",
+			args: args{
+				ifNoneMatch: `*`,
+				validator:   Validator{ETag: `"2"`},
+			},
+			wantIsNotModified: true,
+		},
+		// success scenario: test with not modified since
+		{
+			name: "Success_With_Not_Modified_Since",
+			args: args{
+				ifModifiedSince: lastModified.Format(http.TimeFormat),
+				validator:       Validator{LastModified: lastModified},
+			},
+			wantIsNotModified: true,
+		},
+		// failed scenario: test with different entity tag
+		{
+			name: "Failed_With_Different_ETag",
+			args: args{
+				ifNoneMatch: `"1"`,
+				validator:   Validator{ETag: `"2"`},
+			},
+			wantIsNotModified: false,
+		},
+		// failed scenario: test with different entity tag, If-Modified-Since is ignored
+		{
+			name: "Failed_With_Different_ETag_And_Not_Modified_Since",
+			args: args{
+				ifNoneMatch:     `"1"`,
+				ifModifiedSince: lastModified.Format(http.TimeFormat),
+				validator:       Validator{ETag: `"2"`, LastModified: lastModified},
+			},
+			wantIsNotModified: false,
+		},
+		// failed scenario: test with modified since
+		{
+			name: "Failed_With_Modified_Since",
+			args: args{
+				ifModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat),
+				validator:       Validator{LastModified: lastModified},
+			},
+			wantIsNotModified: false,
+		},
+		// failed scenario: test with invalid If-Modified-Since
+		{
+			name: "Failed_With_Invalid_If_Modified_Since",
+			args: args{
+				ifModifiedSince: "yesterday",
+				validator:       Validator{LastModified: lastModified},
+			},
+			wantIsNotModified: false,
+		},
+		// failed scenario: test without conditional header
+		{
+			name: "Failed_Without_Conditional_Header",
+			args: args{
+				validator: Validator{ETag: `"2"`, LastModified: lastModified},
+			},
+			wantIsNotModified: false,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotIsNotModified := IsNotModified(tt.args.ifNoneMatch, tt.args.ifModifiedSince, tt.args.validator)
+			assert.Equal(t, gotIsNotModified, tt.wantIsNotModified)
+		})
+	}
+}
+
+func TestFingerprintETag(t *testing.T) {
+	// test
+	eTag := FingerprintETag("page=1", int64(10), int64(25))
+	assert.Len(t, eTag, 34)
+	assert.Equal(t, eTag, FingerprintETag("page=1", int64(10), int64(25)))
+	assert.NotEqual(t, eTag, FingerprintETag("page=2", int64(10), int64(25)))
+}
+
+func TestConditionalGet(t *testing.T) {
+	// argument
+	type args struct {
+		ifNoneMatch  string
+		validatorErr error
+		handlerCode  int
+	}
+
+	// test case
+	tests := []struct {
+		name             string
+		args             args
+		wantCode         int
+		wantETag         string
+		wantCacheControl string
+	}{
+		// success scenario: test with matching entity tag
+		{
+			name: "Success_With_Matching_ETag",
+			args: args{
+				ifNoneMatch: `"2"`,
+				handlerCode: http.StatusOK,
+			},
+			wantCode:         http.StatusNotModified,
+			wantETag:         `"2"`,
+			wantCacheControl: "no-cache",
+		},
+		// success scenario: test with different entity tag
+		{
+			name: "Success_With_Different_ETag",
+			args: args{
+				ifNoneMatch: `"1"`,
+				handlerCode: http.StatusOK,
+			},
+			wantCode:         http.StatusOK,
+			wantETag:         `"2"`,
+			wantCacheControl: "no-cache",
+		},
+		// success scenario: test with error response, cache header is removed
+		{
+			name: "Success_With_Error_Response",
+			args: args{
+				handlerCode: http.StatusBadRequest,
+			},
+			wantCode:         http.StatusBadRequest,
+			wantETag:         "",
+			wantCacheControl: "",
+		},
+		// success scenario: test with not cacheable response
+		{
+			name: "Success_With_Not_Cacheable_Response",
+			args: args{
+				ifNoneMatch:  `"2"`,
+				validatorErr: ErrNotCacheable,
+				handlerCode:  http.StatusOK,
+			},
+			wantCode:         http.StatusOK,
+			wantETag:         "",
+			wantCacheControl: "",
+		},
+		// success scenario: test with failed validator
+		{
+			name: "Success_With_Failed_Validator",
+			args: args{
+				validatorErr: errors.New("record not found"),
+				handlerCode:  http.StatusNotFound,
+			},
+			wantCode:         http.StatusNotFound,
+			wantETag:         "",
+			wantCacheControl: "",
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := fiber.New()
+			app.Get("/", ConditionalGet("no-cache", func(ctx *fiber.Ctx) (validator Validator, err error) {
+				return Validator{ETag: `"2"`}, tt.args.validatorErr
+			}), func(ctx *fiber.Ctx) error {
+				return ctx.SendStatus(tt.args.handlerCode)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.args.ifNoneMatch != "" {
+				req.Header.Set(fiber.HeaderIfNoneMatch, tt.args.ifNoneMatch)
+			}
+			res, err := app.Test(req)
+			assert.Nil(t, err)
+			assert.Equal(t, res.StatusCode, tt.wantCode)
+			assert.Equal(t, res.Header.Get(fiber.HeaderETag), tt.wantETag)
+			assert.Equal(t, res.Header.Get(fiber.HeaderCacheControl), tt.wantCacheControl)
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package response
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// VersionETag is function to format resource version as strong entity tag
+func VersionETag(version int) (eTag string) {
+	return fmt.Sprintf(`"%d"`, version)
+}
+
+// VersionFingerprintETag is function to format resource version with fingerprint of the data embedded in the resource as
+// strong entity tag, so change of embedded data changes the entity tag while its version is still read by ParseVersionETag
+func VersionFingerprintETag(version int, parts ...interface{}) (eTag string) {
+	return fmt.Sprintf(`"%d.%s"`, version, strings.Trim(FingerprintETag(parts...), `"`)[:16])
+}
+
+// ParseVersionETag is function to parse strong entity tag into resource version, fingerprint after the version is ignored,
+// weak or malformed entity tag is not valid
+func ParseVersionETag(eTag string) (version int, isValid bool) {
+	eTag = strings.TrimSpace(eTag)
+	if len(eTag) < 3 || !strings.HasPrefix(eTag, `"`) || !strings.HasSuffix(eTag, `"`) {
+		return 0, false
+	}
+	value := eTag[1 : len(eTag)-1]
+	if i := strings.IndexByte(value, '.'); i >= 0 {
+		if i == len(value)-1 {
+			return 0, false
+		}
+		value = value[:i]
+	}
+	version, err := strconv.Atoi(value)
+	if err != nil || version < 1 {
+		return 0, false
+	}
+
+	return version, true
+}
@@ -0,0 +1,162 @@
+package response
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestVersionETag(t *testing.T) {
+	// argument
+	type args struct {
+		version int
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantETag string
+	}{
+		// success scenario: test with version
+		{
+			name: "Success_With_Version",
+			args: args{
+				version: 12,
+			},
+			wantETag: `"12"`,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotETag := VersionETag(tt.args.version)
+			assert.Equal(t, gotETag, tt.wantETag)
+		})
+	}
+}
+
+func TestVersionFingerprintETag(t *testing.T) {
+	// argument
+	type args struct {
+		version int
+		parts   []interface{}
+	}
+
+	// test case
+	tests := []struct {
+		name       string
+		args       args
+		wantPrefix string
+	}{
+		// success scenario: test with version and part
+		{
+			name: "Success_With_Version_And_Part",
+			args: args{
+				version: 12,
+				parts:   []interface{}{int64(1703059200000000000)},
+			},
+			wantPrefix: `"12.`,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotETag := VersionFingerprintETag(tt.args.version, tt.args.parts...)
+			assert.True(t, strings.HasPrefix(gotETag, tt.wantPrefix))
+			assert.Equal(t, len(gotETag), len(tt.wantPrefix)+16+1)
+			assert.Equal(t, gotETag, VersionFingerprintETag(tt.args.version, tt.args.parts...))
+			assert.NotEqual(t, gotETag, VersionFingerprintETag(tt.args.version, int64(1703059200000000001)))
+		})
+	}
+}
+
+func TestParseVersionETag(t *testing.T) {
+	// argument
+	type args struct {
+		eTag string
+	}
+
+	// test case
+	tests := []struct {
+		name        string
+		args        args
+		wantVersion int
+		wantIsValid bool
+	}{
+		// success scenario: test with strong entity tag
+		{
+			name: "Success_With_Strong_ETag",
+			args: args{
+				eTag: ` "12" `,
+			},
+			wantVersion: 12,
+			wantIsValid: true,
+		},
+		// success scenario: test with version and fingerprint entity tag
+		{
+			name: "Success_With_Version_And_Fingerprint_ETag",
+			args: args{
+				eTag: VersionFingerprintETag(12, "2023-12-20T08:00:00Z"),
+			},
+			wantVersion: 12,
+			wantIsValid: true,
+		},
+		// failed scenario: test with empty fingerprint
+		{
+			name: "Failed_With_Empty_Fingerprint",
+			args: args{
+				eTag: `"12."`,
+			},
+			wantVersion: 0,
+			wantIsValid: false,
+		},
+		// failed scenario: test with weak entity tag
+		{
+			name: "Failed_With_Weak_ETag",
+			args: args{
+				eTag: `W/"12"`,
+			},
+			wantVersion: 0,
+			wantIsValid: false,
+		},
+		// failed scenario: test with unquoted entity tag
+		{
+			name: "Failed_With_Unquoted_ETag",
+			args: args{
+				eTag: `12`,
+			},
+			wantVersion: 0,
+			wantIsValid: false,
+		},
+		// failed scenario: test with non numeric entity tag
+		{
+			name: "Failed_With_Non_Numeric_ETag",
+			args: args{
+				eTag: `"abc"`,
+			},
+			wantVersion: 0,
+			wantIsValid: false,
+		},
+		// failed scenario: test with zero version
+		{
+			name: "Failed_With_Zero_Version",
+			args: args{
+				eTag: `"0"`,
+			},
+			wantVersion: 0,
+			wantIsValid: false,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotVersion, gotIsValid := ParseVersionETag(tt.args.eTag)
+			assert.Equal(t, gotVersion, tt.wantVersion)
+			assert.Equal(t, gotIsValid, tt.wantIsValid)
+		})
+	}
+}
@@ -18,6 +18,12 @@ type PaginationResponse struct {
 	Data       interface{} `json:"data"`
 }
 
+type FacetResponse struct {
+	Meta   Meta        `json:"meta"`
+	Facets interface{} `json:"facets"`
+	Data   interface{} `json:"data"`
+}
+
 type Meta struct {
 	Code       int    `json:"code"`
 	Message    string `json:"message"`
@@ -101,6 +107,24 @@ func PaginationRes(ctx *fiber.Ctx, responseCode int, responseMessage, debugParam
 	return ctx.Status(http.StatusOK).JSON(res)
 }
 
+// FacetRes is function to response data with aggregate count of the same data, e.g. filter sidebar of list
+func FacetRes(ctx *fiber.Ctx, responseCode int, responseMessage, debugParam string, facets, data interface{}) error {
+	date := time.Now().Format(time.RFC1123)
+	res := FacetResponse{
+		Meta: Meta{
+			Code:       responseCode,
+			Message:    responseMessage,
+			DebugParam: debugParam,
+			ServerTime: date,
+		},
+		Facets: facets,
+		Data:   data,
+	}
+
+	ctx.Set("date", date)
+	return ctx.Status(responseCode).JSON(res)
+}
+
 // ErrorRes is
 func ErrorRes(ctx *fiber.Ctx, responseCode int, responseMessage, debugParam string) error {
 	date := time.Now().Format(time.RFC1123)
@@ -117,3 +141,20 @@ func ErrorRes(ctx *fiber.Ctx, responseCode int, responseMessage, debugParam stri
 	ctx.Set("date", date)
 	return ctx.Status(responseCode).JSON(res)
 }
+
+// ErrorDataRes is function to response error with data which explains the error, e.g. result per item of failed bulk request
+func ErrorDataRes(ctx *fiber.Ctx, responseCode int, responseMessage, debugParam string, data interface{}) error {
+	date := time.Now().Format(time.RFC1123)
+	res := Response{
+		Meta: Meta{
+			Code:       responseCode,
+			Message:    responseMessage,
+			DebugParam: debugParam,
+			ServerTime: date,
+		},
+		Data: data,
+	}
+
+	ctx.Set("date", date)
+	return ctx.Status(responseCode).JSON(res)
+}
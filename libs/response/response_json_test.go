@@ -264,6 +264,93 @@ func TestPaginationRes(t *testing.T) {
 	}
 }
 
+func TestFacetRes(t *testing.T) {
+	// argument
+	type args struct {
+		ctx             *fiber.Ctx
+		responseCode    int
+		responseMessage string
+		debugParam      string
+		facets          interface{}
+		data            interface{}
+	}
+
+	// test case
+	tests := []struct {
+		name       string
+		args       args
+		wantFacets interface{}
+	}{
+		// success scenario: test with facets and data
+		{
+			name: "Success_With_Facets_And_Data",
+			args: args{
+				responseCode:    http.StatusOK,
+				responseMessage: "success",
+				debugParam:      "",
+				facets: map[string]interface{}{
+					"caught": map[string]interface{}{"caught": 1, "uncaught": 2},
+				},
+				data: []struct {
+					ID   string
+					Name string
+				}{
+					{
+						ID:   "4624712e-d1a7-428c-8a72-84ec4ad79ab9",
+						Name: "Unit Testing",
+					},
+				},
+			},
+			wantFacets: map[string]interface{}{
+				"caught": map[string]interface{}{"caught": float64(1), "uncaught": float64(2)},
+			},
+		},
+		// success scenario: test with empty facets and nil data
+		{
+			name: "Success_With_Empty_Facets_And_Nil_Data",
+			args: args{
+				responseCode:    http.StatusOK,
+				responseMessage: "success",
+				debugParam:      "",
+				facets:          map[string]interface{}{},
+				data:            nil,
+			},
+			wantFacets: map[string]interface{}{},
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fiber.New()
+			f.Get("/", func(ctx *fiber.Ctx) error {
+				err := FacetRes(ctx, tt.args.responseCode, tt.args.responseMessage, tt.args.debugParam, tt.args.facets, tt.args.data)
+				return err
+			})
+			res, err := f.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			// error
+			assert.NoError(t, err)
+			// header
+			assert.Equal(t, res.StatusCode, tt.args.responseCode)
+			assert.NotNil(t, res.Header.Get("date"))
+			// body
+			var resBody FacetResponse
+			body, err := io.ReadAll(res.Body)
+			assert.NoError(t, err)
+			err = json.Unmarshal(body, &resBody)
+			assert.NoError(t, err)
+			assert.Equal(t, resBody.Meta.Code, tt.args.responseCode)
+			assert.Equal(t, resBody.Meta.Message, tt.args.responseMessage)
+			assert.Equal(t, resBody.Facets, tt.wantFacets)
+			if tt.args.data != nil {
+				assert.NotNil(t, resBody.Data)
+			} else {
+				assert.Nil(t, resBody.Data)
+			}
+		})
+	}
+}
+
 func TestErrorRes(t *testing.T) {
 	// argument
 	type args struct {
@@ -316,6 +403,57 @@ func TestErrorRes(t *testing.T) {
 	}
 }
 
+func TestErrorDataRes(t *testing.T) {
+	// argument
+	type args struct {
+		responseCode    int
+		responseMessage string
+		debugParam      string
+		data            interface{}
+	}
+
+	// test case
+	tests := []struct {
+		name string
+		args args
+	}{
+		// success scenario: test with data and error
+		{
+			name: "Success_With_Data_And_Not_Nil_Error",
+			args: args{
+				responseCode:    http.StatusBadRequest,
+				responseMessage: "failed",
+				debugParam:      "unit testing failed",
+				data:            []string{"first failure", "second failure"},
+			},
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := fiber.New()
+			f.Get("/", func(ctx *fiber.Ctx) error {
+				err := ErrorDataRes(ctx, tt.args.responseCode, tt.args.responseMessage, tt.args.debugParam, tt.args.data)
+				return err
+			})
+			res, err := f.Test(httptest.NewRequest(http.MethodGet, "/", nil))
+			// error
+			assert.NoError(t, err)
+			// header
+			assert.Equal(t, res.StatusCode, tt.args.responseCode)
+			assert.NotNil(t, res.Header.Get("date"))
+			// body
+			resBody, err := readBodyRes(res)
+			assert.NoError(t, err)
+			assert.Equal(t, resBody.Meta.Code, tt.args.responseCode)
+			assert.Equal(t, resBody.Meta.Message, tt.args.responseMessage)
+			assert.Equal(t, resBody.Meta.DebugParam, tt.args.debugParam)
+			assert.Len(t, resBody.Data, 2)
+		})
+	}
+}
+
 // readBodyRes is
 func readBodyRes(res *http.Response) (resBody Response, err error) {
 	response, err := io.ReadAll(res.Body)
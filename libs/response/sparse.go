@@ -0,0 +1,35 @@
+package response
+
+import (
+	"encoding/json"
+)
+
+// SparseFields is function to keep only given JSON key of every element of list data, so response only has the field
+// which is asked for, nil keys keeps every key
+func SparseFields(data interface{}, keys map[string]bool) (res interface{}, err error) {
+	if keys == nil {
+		return data, nil
+	}
+	dataJSON, err := json.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	var elements []map[string]json.RawMessage
+	err = json.Unmarshal(dataJSON, &elements)
+	if err != nil {
+		return nil, err
+	}
+
+	sparse := make([]map[string]json.RawMessage, 0, len(elements))
+	for i := 0; i < len(elements); i++ {
+		element := map[string]json.RawMessage{}
+		for key, value := range elements[i] {
+			if keys[key] {
+				element[key] = value
+			}
+		}
+		sparse = append(sparse, element)
+	}
+
+	return sparse, nil
+}
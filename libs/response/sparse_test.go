@@ -0,0 +1,81 @@
+package response
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSparseFields(t *testing.T) {
+	type monster struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+		HP   *int   `json:"hp,omitempty"`
+	}
+	hp := 45
+
+	// argument
+	type args struct {
+		data interface{}
+		keys map[string]bool
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantJSON string
+		wantErr  bool
+	}{
+		// success scenario: test with keys
+		{
+			name: "Success_With_Keys",
+			args: args{
+				data: []monster{{ID: "1", Name: "Bulbasaur", HP: &hp}, {ID: "2", Name: "Ivysaur"}},
+				keys: map[string]bool{"id": true, "hp": true},
+			},
+			wantJSON: `[{"hp":45,"id":"1"},{"id":"2"}]`,
+		},
+		// success scenario: test without keys
+		{
+			name: "Success_Without_Keys",
+			args: args{
+				data: []monster{{ID: "1", Name: "Bulbasaur"}},
+				keys: nil,
+			},
+			wantJSON: `[{"id":"1","name":"Bulbasaur"}]`,
+		},
+		// success scenario: test with empty data
+		{
+			name: "Success_With_Empty_Data",
+			args: args{
+				data: []monster{},
+				keys: map[string]bool{"id": true},
+			},
+			wantJSON: `[]`,
+		},
+		// failed scenario: test with data which is not list
+		{
+			name: "Failed_With_Data_Not_List",
+			args: args{
+				data: monster{ID: "1"},
+				keys: map[string]bool{"id": true},
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := SparseFields(tt.args.data, tt.args.keys)
+			assert.Equal(t, err != nil, tt.wantErr)
+			if tt.wantErr {
+				return
+			}
+			gotJSON, err := json.Marshal(gotRes)
+			assert.NoError(t, err)
+			assert.Equal(t, string(gotJSON), tt.wantJSON)
+		})
+	}
+}
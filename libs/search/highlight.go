@@ -0,0 +1,41 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// HighlightStart and HighlightStop are delimiters of matched term given to ts_headline, control characters are used
+	// instead of HTML tag so text around the match can be escaped before the tag is added
+	HighlightStart = "\x02"
+	HighlightStop  = "\x03"
+)
+
+// Highlight is function to convert snippet of ts_headline into HTML, text is escaped and matched term is wrapped with <mark>
+// unpaired delimiter is dropped, so the result always has balanced tag
+func Highlight(snippet string) (res string) {
+	var builder strings.Builder
+	isOpen := false
+	for {
+		index := strings.IndexAny(snippet, HighlightStart+HighlightStop)
+		if index < 0 {
+			builder.WriteString(html.EscapeString(snippet))
+			break
+		}
+		builder.WriteString(html.EscapeString(snippet[:index]))
+		if snippet[index:index+1] == HighlightStart && !isOpen {
+			builder.WriteString("<mark>")
+			isOpen = true
+		} else if snippet[index:index+1] == HighlightStop && isOpen {
+			builder.WriteString("</mark>")
+			isOpen = false
+		}
+		snippet = snippet[index+1:]
+	}
+	if isOpen {
+		builder.WriteString("</mark>")
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,69 @@
+package search
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	// argument
+	type args struct {
+		snippet string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes string
+	}{
+		// success scenario: test without match
+		{
+			name: "Success_Without_Match",
+			args: args{
+				snippet: "It stores electricity in its cheeks.",
+			},
+			wantRes: "It stores electricity in its cheeks.",
+		},
+		// success scenario: test with multiple match
+		{
+			name: "Success_With_Multiple_Match",
+			args: args{
+				snippet: "It stores \x02electricity\x03 in its \x02cheeks\x03.",
+			},
+			wantRes: "It stores <mark>electricity</mark> in its <mark>cheeks</mark>.",
+		},
+		// success scenario: test with HTML in text
+		{
+			name: "Success_With_HTML_In_Text",
+			args: args{
+				snippet: "<script>\x02fire\x03 & \"ice\"</script>",
+			},
+			wantRes: "&lt;script&gt;<mark>fire</mark> &amp; &#34;ice&#34;&lt;/script&gt;",
+		},
+		// success scenario: test with unpaired delimiter
+		{
+			name: "Success_With_Unpaired_Delimiter",
+			args: args{
+				snippet: "\x03a \x02b \x02c",
+			},
+			wantRes: "a <mark>b c</mark>",
+		},
+		// success scenario: test with empty snippet
+		{
+			name: "Success_With_Empty_Snippet",
+			args: args{
+				snippet: "",
+			},
+			wantRes: "",
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := Highlight(tt.args.snippet)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
@@ -0,0 +1,10 @@
+package search
+
+import "strings"
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// EscapeLike is function to escape wildcard of LIKE pattern, so user input is matched literally with default escape character
+func EscapeLike(input string) string {
+	return likeEscaper.Replace(input)
+}
@@ -0,0 +1,102 @@
+package search
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEscapeLike(t *testing.T) {
+	// argument
+	type args struct {
+		input string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes string
+	}{
+		// success scenario: test without wildcard
+		{
+			name: "Success_Without_Wildcard",
+			args: args{
+				input: "charmander",
+			},
+			wantRes: "charmander",
+		},
+		// success scenario: test with wildcard
+		{
+			name: "Success_With_Wildcard",
+			args: args{
+				input: "mr_mime 100%",
+			},
+			wantRes: `mr\_mime 100\%`,
+		},
+		// success scenario: test with escape character
+		{
+			name: "Success_With_Escape_Character",
+			args: args{
+				input: `a\%`,
+			},
+			wantRes: `a\\\%`,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := EscapeLike(tt.args.input)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
+
+// matchLike is LIKE matcher with backslash escape, it follows Postgres LIKE so escaped pattern can be checked without database
+func matchLike(value, pattern []rune) bool {
+	if len(pattern) == 0 {
+		return len(value) == 0
+	}
+	switch pattern[0] {
+	case '%':
+		for i := 0; i <= len(value); i++ {
+			if matchLike(value[i:], pattern[1:]) {
+				return true
+			}
+		}
+		return false
+	case '_':
+		return len(value) > 0 && matchLike(value[1:], pattern[1:])
+	case '\\':
+		if len(pattern) == 1 {
+			return false
+		}
+		pattern = pattern[1:]
+	}
+
+	return len(value) > 0 && value[0] == pattern[0] && matchLike(value[1:], pattern[1:])
+}
+
+// FuzzEscapeLike checks that escaped input matches itself only, so punctuation in name is never used as wildcard
+func FuzzEscapeLike(f *testing.F) {
+	f.Add("Farfetch'd", "Farfetchxd")
+	f.Add("100%", "1000")
+	f.Add("Mr_Mime", "MrXMime")
+	f.Add(`back\slash`, `back\\slash`)
+	f.Add("", "a")
+
+	f.Fuzz(func(t *testing.T, input, other string) {
+		// invalid UTF-8 is rejected by request parsing before reaching query
+		if !utf8.ValidString(input) || !utf8.ValidString(other) {
+			return
+		}
+		pattern := []rune(EscapeLike(input))
+		if !matchLike([]rune(input), pattern) {
+			t.Fatalf("escaped %q does not match itself", input)
+		}
+		if other != input && matchLike([]rune(other), pattern) {
+			t.Fatalf("escaped %q matches %q", input, other)
+		}
+	})
+}
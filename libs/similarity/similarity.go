@@ -0,0 +1,154 @@
+package similarity
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	// TypeBonus is score added for identical set of type, partial overlap earns the bonus by Jaccard index of both set
+	TypeBonus = 0.2
+	// CategoryBonus is score added for the same category
+	CategoryBonus = 0.1
+)
+
+// Stat is stat vector compared by distance, it is also used as range of every stat to normalize the vector
+type Stat struct {
+	HP      float64
+	Attack  float64
+	Defends float64
+	Speed   float64
+}
+
+// Profile is what similarity is scored from
+type Profile struct {
+	Stat       Stat
+	CategoryId string
+	TypeIds    []string
+}
+
+// Columns is SQL of candidate profile used by ScoreSQL, SQL is written by caller and never by the profile
+type Columns struct {
+	HP      string
+	Attack  string
+	Defends string
+	Speed   string
+	// CategoryId is SQL of candidate category id
+	CategoryId string
+	// SharedTypeCount is SQL counting type of candidate with one ? bound by type ids of target
+	SharedTypeCount string
+	// TypeCount is SQL counting every type of candidate
+	TypeCount string
+}
+
+// Score is function to score similarity of candidate to target, higher is more similar
+//
+//	score = 1 - distance + TypeBonus * jaccard(types) + CategoryBonus * [same category]
+//
+// distance is Euclidean distance of stat normalized by statRange and divided by the number of stat, so it is between 0
+// and 1 for stat within range, stat with zero range does not differ between any monster and is left out
+func Score(target, candidate Profile, statRange Stat) float64 {
+	score := 1 - Distance(target.Stat, candidate.Stat, statRange)
+	score += TypeBonus * Jaccard(target.TypeIds, candidate.TypeIds)
+	if target.CategoryId != "" && target.CategoryId == candidate.CategoryId {
+		score += CategoryBonus
+	}
+
+	return score
+}
+
+// Distance is function to get normalized Euclidean distance of two stat vector
+func Distance(a, b, statRange Stat) float64 {
+	var sum float64
+	for _, d := range []struct{ a, b, statRange float64 }{
+		{a: a.HP, b: b.HP, statRange: statRange.HP},
+		{a: a.Attack, b: b.Attack, statRange: statRange.Attack},
+		{a: a.Defends, b: b.Defends, statRange: statRange.Defends},
+		{a: a.Speed, b: b.Speed, statRange: statRange.Speed},
+	} {
+		if d.statRange <= 0 {
+			continue
+		}
+		sum += math.Pow((d.a-d.b)/d.statRange, 2)
+	}
+
+	return math.Sqrt(sum / 4)
+}
+
+// Jaccard is function to get size of intersection divided by size of union of two set, two empty set share nothing
+func Jaccard(a, b []string) float64 {
+	isExist := map[string]bool{}
+	for i := 0; i < len(a); i++ {
+		isExist[a[i]] = true
+	}
+	union := len(isExist)
+	shared := 0
+	isCounted := map[string]bool{}
+	for i := 0; i < len(b); i++ {
+		if isCounted[b[i]] {
+			continue
+		}
+		isCounted[b[i]] = true
+		if isExist[b[i]] {
+			shared++
+		} else {
+			union++
+		}
+	}
+	if union == 0 {
+		return 0
+	}
+
+	return float64(shared) / float64(union)
+}
+
+// ScoreSQL is function to write Score of target as SQL expression of candidate, so candidate can be ranked by the database
+// without reading every row, every value of target is returned as argument
+func ScoreSQL(target Profile, statRange Stat, columns Columns) (sql string, args []interface{}) {
+	var distance []string
+	for _, d := range []struct {
+		column    string
+		value     float64
+		statRange float64
+	}{
+		{column: columns.HP, value: target.Stat.HP, statRange: statRange.HP},
+		{column: columns.Attack, value: target.Stat.Attack, statRange: statRange.Attack},
+		{column: columns.Defends, value: target.Stat.Defends, statRange: statRange.Defends},
+		{column: columns.Speed, value: target.Stat.Speed, statRange: statRange.Speed},
+	} {
+		if d.statRange <= 0 {
+			continue
+		}
+		distance = append(distance, "power((CAST("+d.column+" AS double precision) - ?) / ?, 2)")
+		args = append(args, d.value, d.statRange)
+	}
+	sql = "1"
+	if len(distance) > 0 {
+		sql += " - sqrt((" + strings.Join(distance, " + ") + ") / 4)"
+	}
+
+	// shared type is counted once, union is type of candidate and type of target which candidate does not have
+	typeIds := uniqueTypeIds(target.TypeIds)
+	if len(typeIds) > 0 {
+		sql += " + ? * CAST(" + columns.SharedTypeCount + " AS double precision) / (" + columns.TypeCount + " + ? - " + columns.SharedTypeCount + ")"
+		args = append(args, TypeBonus, typeIds, len(typeIds), typeIds)
+	}
+	if target.CategoryId != "" {
+		sql += " + CASE WHEN " + columns.CategoryId + " = ? THEN ? ELSE 0 END"
+		args = append(args, target.CategoryId, CategoryBonus)
+	}
+
+	return "(" + sql + ")", args
+}
+
+func uniqueTypeIds(typeIds []string) (res []string) {
+	isExist := map[string]bool{}
+	for i := 0; i < len(typeIds); i++ {
+		if !isExist[typeIds[i]] {
+			isExist[typeIds[i]] = true
+			res = append(res, typeIds[i])
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,240 @@
+package similarity
+
+import (
+	"github.com/stretchr/testify/assert"
+	"math"
+	"math/rand"
+	"testing"
+)
+
+var statRange = Stat{HP: 200, Attack: 100, Defends: 100, Speed: 100}
+
+func TestScore(t *testing.T) {
+	// argument
+	type args struct {
+		target    Profile
+		candidate Profile
+		statRange Stat
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes float64
+	}{
+		// success scenario: test with identical profile
+		{
+			name: "Success_With_Identical_Profile",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 45, Attack: 49, Defends: 49, Speed: 45}, CategoryId: "seed", TypeIds: []string{"grass", "poison"}},
+				candidate: Profile{Stat: Stat{HP: 45, Attack: 49, Defends: 49, Speed: 45}, CategoryId: "seed", TypeIds: []string{"poison", "grass"}},
+				statRange: statRange,
+			},
+			wantRes: 1 + TypeBonus + CategoryBonus,
+		},
+		// success scenario: test with opposite stat and nothing shared
+		{
+			name: "Success_With_Opposite_Stat_And_Nothing_Shared",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 0, Attack: 0, Defends: 0, Speed: 0}, CategoryId: "seed", TypeIds: []string{"grass"}},
+				candidate: Profile{Stat: Stat{HP: 200, Attack: 100, Defends: 100, Speed: 100}, CategoryId: "flame", TypeIds: []string{"fire"}},
+				statRange: statRange,
+			},
+			wantRes: 0,
+		},
+		// success scenario: test with half of type shared
+		{
+			name: "Success_With_Half_Of_Type_Shared",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 100, Attack: 50, Defends: 50, Speed: 50}, TypeIds: []string{"grass", "poison"}},
+				candidate: Profile{Stat: Stat{HP: 100, Attack: 50, Defends: 50, Speed: 50}, TypeIds: []string{"grass"}},
+				statRange: statRange,
+			},
+			wantRes: 1 + TypeBonus/2,
+		},
+		// success scenario: test with one stat differing by whole range
+		{
+			name: "Success_With_One_Stat_Differing_By_Whole_Range",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 0, Attack: 50, Defends: 50, Speed: 50}},
+				candidate: Profile{Stat: Stat{HP: 200, Attack: 50, Defends: 50, Speed: 50}},
+				statRange: statRange,
+			},
+			wantRes: 0.5,
+		},
+		// success scenario: test with zero stat range
+		{
+			name: "Success_With_Zero_Stat_Range",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 10, Attack: 10, Defends: 10, Speed: 10}},
+				candidate: Profile{Stat: Stat{HP: 10, Attack: 10, Defends: 10, Speed: 10}},
+				statRange: Stat{},
+			},
+			wantRes: 1,
+		},
+		// success scenario: test with empty category
+		{
+			name: "Success_With_Empty_Category",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 10, Attack: 10, Defends: 10, Speed: 10}},
+				candidate: Profile{Stat: Stat{HP: 10, Attack: 10, Defends: 10, Speed: 10}},
+				statRange: statRange,
+			},
+			wantRes: 1,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := Score(tt.args.target, tt.args.candidate, tt.args.statRange)
+			assert.InDelta(t, gotRes, tt.wantRes, 1e-9)
+		})
+	}
+}
+
+func TestJaccard(t *testing.T) {
+	// argument
+	type args struct {
+		a []string
+		b []string
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes float64
+	}{
+		// success scenario: test with empty set
+		{
+			name: "Success_With_Empty_Set",
+			args: args{
+				a: nil,
+				b: nil,
+			},
+			wantRes: 0,
+		},
+		// success scenario: test with duplicate
+		{
+			name: "Success_With_Duplicate",
+			args: args{
+				a: []string{"fire", "fire"},
+				b: []string{"fire", "flying", "flying"},
+			},
+			wantRes: 0.5,
+		},
+		// success scenario: test with disjoint set
+		{
+			name: "Success_With_Disjoint_Set",
+			args: args{
+				a: []string{"fire"},
+				b: []string{"water"},
+			},
+			wantRes: 0,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes := Jaccard(tt.args.a, tt.args.b)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}
+
+func TestScoreSQL(t *testing.T) {
+	columns := Columns{
+		HP:              "hp",
+		Attack:          "attack",
+		Defends:         "defends",
+		Speed:           "speed",
+		CategoryId:      "category_id",
+		SharedTypeCount: "shared(?)",
+		TypeCount:       "total",
+	}
+
+	// argument
+	type args struct {
+		target    Profile
+		statRange Stat
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantSQL  string
+		wantArgs []interface{}
+	}{
+		// success scenario: test with every part of score
+		{
+			name: "Success_With_Every_Part",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 45, Attack: 49, Defends: 49, Speed: 45}, CategoryId: "seed", TypeIds: []string{"grass", "poison", "grass"}},
+				statRange: Stat{HP: 200, Attack: 0, Defends: 100, Speed: 100},
+			},
+			wantSQL: "(1 - sqrt((power((CAST(hp AS double precision) - ?) / ?, 2) + power((CAST(defends AS double precision) - ?) / ?, 2) + " +
+				"power((CAST(speed AS double precision) - ?) / ?, 2)) / 4) + ? * CAST(shared(?) AS double precision) / (total + ? - shared(?)) + " +
+				"CASE WHEN category_id = ? THEN ? ELSE 0 END)",
+			wantArgs: []interface{}{
+				float64(45), float64(200), float64(49), float64(100), float64(45), float64(100),
+				TypeBonus, []string{"grass", "poison"}, 2, []string{"grass", "poison"},
+				"seed", CategoryBonus,
+			},
+		},
+		// success scenario: test without stat range, type, and category
+		{
+			name: "Success_Without_Stat_Range_Type_And_Category",
+			args: args{
+				target:    Profile{Stat: Stat{HP: 45}},
+				statRange: Stat{},
+			},
+			wantSQL:  "(1)",
+			wantArgs: nil,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotSQL, gotArgs := ScoreSQL(tt.args.target, tt.args.statRange, columns)
+			assert.Equal(t, gotSQL, tt.wantSQL)
+			assert.Equal(t, gotArgs, tt.wantArgs)
+		})
+	}
+}
+
+// TestScore_Property checks that score is symmetric, bounded, and highest for monster compared with itself
+func TestScore_Property(t *testing.T) {
+	types := []string{"fire", "water", "grass", "poison", "flying"}
+	categories := []string{"", "seed", "flame"}
+	random := rand.New(rand.NewSource(1))
+	profile := func() Profile {
+		p := Profile{
+			Stat: Stat{
+				HP:      float64(random.Intn(201)),
+				Attack:  float64(random.Intn(101)),
+				Defends: float64(random.Intn(101)),
+				Speed:   float64(random.Intn(101)),
+			},
+			CategoryId: categories[random.Intn(len(categories))],
+		}
+		for i := 0; i < random.Intn(3); i++ {
+			p.TypeIds = append(p.TypeIds, types[random.Intn(len(types))])
+		}
+		return p
+	}
+
+	for i := 0; i < 1000; i++ {
+		a, b := profile(), profile()
+		score := Score(a, b, statRange)
+		assert.InDelta(t, score, Score(b, a, statRange), 1e-9)
+		assert.GreaterOrEqual(t, score, 0.0)
+		assert.LessOrEqual(t, score, 1+TypeBonus+CategoryBonus)
+		assert.GreaterOrEqual(t, Score(a, a, statRange)+1e-9, score)
+		assert.False(t, math.IsNaN(score))
+	}
+}
@@ -0,0 +1,49 @@
+package uploader
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/blurhash"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"os"
+)
+
+// ImagePlaceholder is function to compute BlurHash and dominant color of saved image
+func ImagePlaceholder(imageName string) (blurHash, dominantColor string, err error) {
+	path := constants.ImageDirectory
+
+	// open image
+	file, err := os.Open(fmt.Sprintf("%s/%s", path, imageName))
+	if err != nil {
+		return "", "", err
+	}
+	defer file.Close()
+
+	// decode image
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return "", "", err
+	}
+
+	// compute placeholder
+	blurHash, err = blurhash.Encode(img, constants.BlurHashXComponents, constants.BlurHashYComponents)
+	if err != nil {
+		return "", "", err
+	}
+	dominantColor = blurhash.DominantColor(img)
+
+	return blurHash, dominantColor, nil
+}
+
+// ValidateImage is function to check image content can be decoded as supported image
+func ValidateImage(data []byte) (err error) {
+	_, _, err = image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
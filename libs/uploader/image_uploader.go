@@ -1,18 +1,24 @@
 package uploader
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
 	"github.com/gofiber/fiber/v2"
+	"io"
+	"io/fs"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 )
 
 // SaveImage is
 func SaveImage(ctx *fiber.Ctx, imageFile *multipart.FileHeader) (imageName string, err error) {
-	path := "./images"
+	path := constants.ImageDirectory
 
 	// make directory, if not exist
 	_, err = os.Stat(path)
@@ -24,7 +30,7 @@ func SaveImage(ctx *fiber.Ctx, imageFile *multipart.FileHeader) (imageName strin
 	}
 
 	// save image
-	newImageName := fmt.Sprintf("monster_%d%s", time.Now().Unix(), filepath.Ext(imageFile.Filename))
+	newImageName := fmt.Sprintf("monster_%d%s", time.Now().UnixNano(), filepath.Ext(imageFile.Filename))
 	err = ctx.SaveFile(imageFile, fmt.Sprintf("%s/%s", path, newImageName))
 	if err != nil {
 		return "", err
@@ -33,9 +39,32 @@ func SaveImage(ctx *fiber.Ctx, imageFile *multipart.FileHeader) (imageName strin
 	return newImageName, nil
 }
 
+// SaveImageData is function to save image content with given extension into image directory
+func SaveImageData(data []byte, extension string) (imageName string, err error) {
+	path := constants.ImageDirectory
+
+	// make directory, if not exist
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Mkdir(path, os.ModePerm)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	// save image
+	newImageName := fmt.Sprintf("monster_%d%s", time.Now().UnixNano(), extension)
+	err = os.WriteFile(fmt.Sprintf("%s/%s", path, newImageName), data, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return newImageName, nil
+}
+
 // DeleteImage is
 func DeleteImage(imageName string) (err error) {
-	path := "./images"
+	path := constants.ImageDirectory
 
 	// delete image from directory
 	err = os.Remove(fmt.Sprintf("%s/%s", path, imageName))
@@ -45,3 +74,127 @@ func DeleteImage(imageName string) (err error) {
 
 	return nil
 }
+
+// ListImage is function to list all image file in image directory
+func ListImage() (res []fs.FileInfo, err error) {
+	path := constants.ImageDirectory
+
+	// read image directory, empty if not exist
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	for i := 0; i < len(entries); i++ {
+		if entries[i].IsDir() {
+			continue
+		}
+		info, err := entries[i].Info()
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, info)
+	}
+
+	return res, nil
+}
+
+// IsImageExist is function to check image file exist in image directory
+func IsImageExist(imageName string) (isExist bool, err error) {
+	path := constants.ImageDirectory
+
+	_, err = os.Stat(fmt.Sprintf("%s/%s", path, imageName))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// OpenImage is function to open image file in image directory
+func OpenImage(imageName string) (file *os.File, err error) {
+	path := constants.ImageDirectory
+
+	err = validateImageName(imageName)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(fmt.Sprintf("%s/%s", path, imageName))
+}
+
+// ImageChecksum is function to get sha256 checksum of image file in image directory, checksum is empty if image does not exist
+func ImageChecksum(imageName string) (checksum string, err error) {
+	file, err := OpenImage(imageName)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	_, err = io.Copy(hash, file)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// WriteImage is function to write image content with given image name into image directory
+// content is written to temporary file first, so existing image is never left half written
+func WriteImage(imageName string, reader io.Reader) (err error) {
+	path := constants.ImageDirectory
+
+	err = validateImageName(imageName)
+	if err != nil {
+		return err
+	}
+
+	// make directory, if not exist
+	_, err = os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		err = os.Mkdir(path, os.ModePerm)
+		if err != nil {
+			return err
+		}
+	}
+
+	// write image
+	tempFile, err := os.CreateTemp(path, ".restore_*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tempFile.Name())
+	_, err = io.Copy(tempFile, reader)
+	if err != nil {
+		tempFile.Close()
+		return err
+	}
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+	err = os.Chmod(tempFile.Name(), 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), fmt.Sprintf("%s/%s", path, imageName))
+}
+
+// validateImageName is function to validate image name is plain file name inside image directory
+func validateImageName(imageName string) (err error) {
+	if imageName == "" || imageName == "." || imageName == ".." || filepath.Base(imageName) != imageName || strings.ContainsAny(imageName, `/\`) {
+		return fmt.Errorf("image name %q is not valid", imageName)
+	}
+
+	return nil
+}
@@ -0,0 +1,41 @@
+package uploader
+
+import (
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/encrypt"
+	"net/url"
+	"time"
+)
+
+// ImageURL is url generator for image served by image handler
+type ImageURL struct {
+	BaseURL    string
+	SigningKey string
+	TTL        time.Duration
+}
+
+// ImagePath is function to get path of image served by image handler
+func ImagePath(imageName string) string {
+	return fmt.Sprintf("/api/v1/monster/images/%s", imageName)
+}
+
+// Generate is function to generate image url, private image url is signed and expiring
+func (imageURL ImageURL) Generate(imageName string, isPrivate bool) string {
+	path := ImagePath(imageName)
+	if !isPrivate {
+		return imageURL.BaseURL + path
+	}
+
+	// expiry is rounded up to the minute, so the same url is generated within a minute
+	expires := time.Now().Add(imageURL.TTL).Truncate(time.Minute).Add(time.Minute).Unix()
+	query := url.Values{}
+	query.Set("expires", fmt.Sprintf("%d", expires))
+	query.Set("signature", encrypt.SignURL(path, expires, imageURL.SigningKey))
+
+	return fmt.Sprintf("%s%s?%s", imageURL.BaseURL, path, query.Encode())
+}
+
+// Verify is function to verify signed image url
+func (imageURL ImageURL) Verify(imageName, expires, signature string) (err error) {
+	return encrypt.VerifyURL(ImagePath(imageName), expires, signature, imageURL.SigningKey)
+}
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monsters
+    DROP COLUMN IF EXISTS is_image_missing;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monsters
+    ADD COLUMN IF NOT EXISTS is_image_missing bool NOT NULL DEFAULT FALSE;
\ No newline at end of file
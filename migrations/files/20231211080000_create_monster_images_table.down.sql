@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.monster_images;
\ No newline at end of file
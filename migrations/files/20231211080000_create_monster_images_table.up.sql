@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS public.monster_images
+(
+    id           uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    monster_id   uuid             NOT NULL,
+    image_name   text             NOT NULL,
+    caption      varchar(255)     NOT NULL DEFAULT '',
+    sort_order   integer          NOT NULL DEFAULT 0,
+    is_primary   bool             NOT NULL DEFAULT FALSE,
+    variant_tags text[]           NOT NULL DEFAULT '{}',
+    created_at   timestamp        NOT NULL DEFAULT now(),
+    updated_at   timestamp        NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS monster_images_monster_id_sort_order_idx
+    ON public.monster_images (monster_id, sort_order);
+
+CREATE UNIQUE INDEX IF NOT EXISTS monster_images_monster_id_primary_idx
+    ON public.monster_images (monster_id)
+    WHERE is_primary;
+
+-- existing monster image become primary image of its gallery
+INSERT INTO public.monster_images (monster_id, image_name, sort_order, is_primary)
+SELECT id, image_name, 0, TRUE
+FROM public.monsters
+WHERE image_name <> '';
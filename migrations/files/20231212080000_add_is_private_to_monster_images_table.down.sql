@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monster_images
+    DROP COLUMN IF EXISTS is_private;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monster_images
+    ADD COLUMN IF NOT EXISTS is_private bool NOT NULL DEFAULT FALSE;
\ No newline at end of file
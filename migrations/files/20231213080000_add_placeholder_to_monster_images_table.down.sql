@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS public.monster_images
+    DROP COLUMN IF EXISTS blur_hash,
+    DROP COLUMN IF EXISTS dominant_color;
\ No newline at end of file
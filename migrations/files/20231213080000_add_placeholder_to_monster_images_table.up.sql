@@ -0,0 +1,3 @@
+ALTER TABLE IF EXISTS public.monster_images
+    ADD COLUMN IF NOT EXISTS blur_hash varchar(100) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS dominant_color varchar(7) NOT NULL DEFAULT '';
\ No newline at end of file
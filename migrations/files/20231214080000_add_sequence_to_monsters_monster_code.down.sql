@@ -0,0 +1,6 @@
+ALTER TABLE IF EXISTS public.monsters
+    DROP CONSTRAINT IF EXISTS monsters_monster_code_check,
+    DROP CONSTRAINT IF EXISTS monsters_monster_code_key,
+    ALTER COLUMN monster_code DROP DEFAULT;
+
+DROP SEQUENCE IF EXISTS public.monsters_monster_code_seq;
\ No newline at end of file
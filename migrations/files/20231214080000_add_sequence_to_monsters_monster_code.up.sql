@@ -0,0 +1,29 @@
+CREATE SEQUENCE IF NOT EXISTS public.monsters_monster_code_seq
+    AS integer
+    MINVALUE 1
+    MAXVALUE 65535
+    OWNED BY public.monsters.monster_code;
+
+-- renumber duplicated monster code, oldest monster keep its code
+WITH duplicated AS (SELECT id,
+                           ROW_NUMBER() OVER (PARTITION BY monster_code ORDER BY created_at, id) AS row_number
+                    FROM public.monsters),
+     renumbered AS (SELECT monsters.id,
+                           (SELECT COALESCE(MAX(monster_code), 0) FROM public.monsters) +
+                           ROW_NUMBER() OVER (ORDER BY monsters.created_at, monsters.id) AS monster_code
+                    FROM public.monsters
+                             JOIN duplicated ON duplicated.id = monsters.id
+                    WHERE duplicated.row_number > 1)
+UPDATE public.monsters
+SET monster_code = renumbered.monster_code
+FROM renumbered
+WHERE monsters.id = renumbered.id;
+
+SELECT setval('public.monsters_monster_code_seq',
+              GREATEST((SELECT COALESCE(MAX(monster_code), 0) FROM public.monsters), 1),
+              (SELECT COUNT(*) > 0 FROM public.monsters));
+
+ALTER TABLE IF EXISTS public.monsters
+    ALTER COLUMN monster_code SET DEFAULT nextval('public.monsters_monster_code_seq'),
+    ADD CONSTRAINT monsters_monster_code_key UNIQUE (monster_code),
+    ADD CONSTRAINT monsters_monster_code_check CHECK (monster_code BETWEEN 1 AND 65535);
\ No newline at end of file
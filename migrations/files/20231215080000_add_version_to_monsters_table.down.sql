@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monsters
+    DROP COLUMN IF EXISTS version;
\ No newline at end of file
@@ -0,0 +1,2 @@
+ALTER TABLE IF EXISTS public.monsters
+    ADD COLUMN IF NOT EXISTS version integer NOT NULL DEFAULT 1;
\ No newline at end of file
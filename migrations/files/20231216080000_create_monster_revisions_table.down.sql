@@ -0,0 +1,5 @@
+DROP TRIGGER IF EXISTS monster_revisions_append_only_trigger ON public.monster_revisions;
+
+DROP FUNCTION IF EXISTS public.monster_revisions_append_only();
+
+DROP TABLE IF EXISTS public.monster_revisions;
\ No newline at end of file
@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS public.monster_revisions
+(
+    id         uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    monster_id uuid             NOT NULL,
+    version    integer          NOT NULL,
+    action     varchar(20)      NOT NULL,
+    actor_id   uuid,
+    diff       jsonb            NOT NULL DEFAULT '{}',
+    snapshot   jsonb            NOT NULL,
+    created_at timestamp        NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS monster_revisions_monster_id_created_at_idx
+    ON public.monster_revisions (monster_id, created_at DESC);
+
+-- revision is append-only, it cannot be edited after recorded
+CREATE OR REPLACE FUNCTION public.monster_revisions_append_only() RETURNS trigger AS
+$$
+BEGIN
+    RAISE EXCEPTION 'monster revision is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER monster_revisions_append_only_trigger
+    BEFORE UPDATE
+    ON public.monster_revisions
+    FOR EACH ROW
+EXECUTE FUNCTION public.monster_revisions_append_only();
\ No newline at end of file
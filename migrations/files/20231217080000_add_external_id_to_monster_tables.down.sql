@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS public.monsters_external_id_key;
+DROP INDEX IF EXISTS public.monster_types_external_id_key;
+DROP INDEX IF EXISTS public.monster_categories_external_id_key;
+
+ALTER TABLE IF EXISTS public.monsters
+    DROP COLUMN IF EXISTS external_id;
+ALTER TABLE IF EXISTS public.monster_types
+    DROP COLUMN IF EXISTS external_id;
+ALTER TABLE IF EXISTS public.monster_categories
+    DROP COLUMN IF EXISTS external_id;
\ No newline at end of file
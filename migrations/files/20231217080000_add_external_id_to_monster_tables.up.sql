@@ -0,0 +1,14 @@
+ALTER TABLE IF EXISTS public.monster_categories
+    ADD COLUMN IF NOT EXISTS external_id varchar(100);
+ALTER TABLE IF EXISTS public.monster_types
+    ADD COLUMN IF NOT EXISTS external_id varchar(100);
+ALTER TABLE IF EXISTS public.monsters
+    ADD COLUMN IF NOT EXISTS external_id varchar(100);
+
+-- external id is unique per source record, row without external id is not constrained
+CREATE UNIQUE INDEX IF NOT EXISTS monster_categories_external_id_key
+    ON public.monster_categories (external_id);
+CREATE UNIQUE INDEX IF NOT EXISTS monster_types_external_id_key
+    ON public.monster_types (external_id);
+CREATE UNIQUE INDEX IF NOT EXISTS monsters_external_id_key
+    ON public.monsters (external_id);
\ No newline at end of file
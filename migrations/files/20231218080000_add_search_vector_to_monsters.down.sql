@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.monsters_search_vector_idx;
+
+ALTER TABLE IF EXISTS public.monsters
+    DROP COLUMN IF EXISTS search_vector;
\ No newline at end of file
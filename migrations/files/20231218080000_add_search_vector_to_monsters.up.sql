@@ -0,0 +1,9 @@
+-- name is weighted above description so match on name ranks first
+ALTER TABLE IF EXISTS public.monsters
+    ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+        setweight(to_tsvector('english', coalesce(name, '')), 'A') ||
+        setweight(to_tsvector('english', coalesce(description, '')), 'B')
+    ) STORED;
+
+CREATE INDEX IF NOT EXISTS monsters_search_vector_idx
+    ON public.monsters USING GIN (search_vector);
\ No newline at end of file
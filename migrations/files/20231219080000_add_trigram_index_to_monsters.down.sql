@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS public.monsters_name_prefix_idx;
+DROP INDEX IF EXISTS public.monsters_name_trgm_idx;
+
+-- pg_trgm extension is kept since it may be used outside of this migration
\ No newline at end of file
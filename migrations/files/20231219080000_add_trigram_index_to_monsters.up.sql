@@ -0,0 +1,10 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- trigram index backs typo tolerant name search, it also backs substring LIKE of name filter
+CREATE INDEX IF NOT EXISTS monsters_name_trgm_idx
+    ON public.monsters USING GIN (lower(name) gin_trgm_ops);
+
+-- prefix index backs autocomplete of active monster
+CREATE INDEX IF NOT EXISTS monsters_name_prefix_idx
+    ON public.monsters (lower(name) text_pattern_ops)
+    WHERE deleted_at IS NULL;
\ No newline at end of file
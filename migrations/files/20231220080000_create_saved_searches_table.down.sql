@@ -0,0 +1 @@
+DROP TABLE IF EXISTS public.saved_searches;
\ No newline at end of file
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS public.saved_searches
+(
+    id         uuid PRIMARY KEY NOT NULL DEFAULT gen_random_uuid(),
+    user_id    uuid             NOT NULL REFERENCES public.users (id) ON DELETE CASCADE,
+    name       varchar(100)     NOT NULL,
+    query      jsonb            NOT NULL DEFAULT '{}',
+    created_at timestamp        NOT NULL DEFAULT now(),
+    updated_at timestamp        NOT NULL DEFAULT now()
+);
+
+-- name of saved search is unique per user regardless of case
+CREATE UNIQUE INDEX IF NOT EXISTS saved_searches_user_id_name_idx
+    ON public.saved_searches (user_id, lower(name));
\ No newline at end of file
@@ -0,0 +1,136 @@
+package model
+
+import (
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/lib/pq"
+	"time"
+)
+
+// BackupMonsterCategory is row of monster category table in backup archive, soft deleted row is included
+type BackupMonsterCategory struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	ExternalId *string    `json:"external_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+func (BackupMonsterCategory) TableName() string {
+	return constants.MonsterCategoryTable
+}
+
+// BackupMonsterType is row of monster type table in backup archive, soft deleted row is included
+type BackupMonsterType struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	ExternalId *string    `json:"external_id"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+	DeletedAt  *time.Time `json:"deleted_at"`
+}
+
+func (BackupMonsterType) TableName() string {
+	return constants.MonsterTypeTable
+}
+
+// BackupMonster is row of monster table in backup archive, soft deleted row is included
+type BackupMonster struct {
+	ID                string     `json:"id"`
+	MonsterCode       uint16     `json:"monster_code"`
+	Name              string     `json:"name"`
+	MonsterCategoryId string     `json:"monster_category_id"`
+	Description       string     `json:"description"`
+	Length            float32    `json:"length"`
+	Weight            uint16     `json:"weight"`
+	HP                uint16     `json:"hp"`
+	Attack            uint16     `json:"attack"`
+	Defends           uint16     `json:"defends"`
+	Speed             uint16     `json:"speed"`
+	IsCaught          bool       `json:"is_caught"`
+	ImageName         string     `json:"image_name"`
+	IsImageMissing    bool       `json:"is_image_missing"`
+	Version           int        `json:"version"`
+	ExternalId        *string    `json:"external_id"`
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+	DeletedAt         *time.Time `json:"deleted_at"`
+}
+
+func (BackupMonster) TableName() string {
+	return constants.MonsterTable
+}
+
+// BackupMappingMonsterAndType is row of mapping monster and type table in backup archive
+type BackupMappingMonsterAndType struct {
+	MonsterId     string `json:"monster_id"`
+	MonsterTypeId string `json:"monster_type_id"`
+}
+
+func (BackupMappingMonsterAndType) TableName() string {
+	return constants.MappingMonsterAndTypes
+}
+
+// BackupMonsterImage is row of monster image table in backup archive
+type BackupMonsterImage struct {
+	ID            string         `json:"id"`
+	MonsterId     string         `json:"monster_id"`
+	ImageName     string         `json:"image_name"`
+	Caption       string         `json:"caption"`
+	SortOrder     int            `json:"sort_order"`
+	IsPrimary     bool           `json:"is_primary"`
+	IsPrivate     bool           `json:"is_private"`
+	VariantTags   pq.StringArray `json:"variant_tags" gorm:"type:text[]"`
+	BlurHash      string         `json:"blur_hash"`
+	DominantColor string         `json:"dominant_color"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+func (BackupMonsterImage) TableName() string {
+	return constants.MonsterImageTable
+}
+
+// BackupMonsterRevision is row of monster revision table in backup archive
+type BackupMonsterRevision struct {
+	ID        string    `json:"id"`
+	MonsterId string    `json:"monster_id"`
+	Version   int       `json:"version"`
+	Action    string    `json:"action"`
+	ActorId   *string   `json:"actor_id"`
+	Diff      string    `json:"diff" gorm:"type:jsonb"`
+	Snapshot  string    `json:"snapshot" gorm:"type:jsonb"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (BackupMonsterRevision) TableName() string {
+	return constants.MonsterRevisionTable
+}
+
+// BackupRes is result of backup
+type BackupRes struct {
+	SchemaVersion         uint     `json:"schema_version"`
+	MonsterCategory       int      `json:"monster_category"`
+	MonsterType           int      `json:"monster_type"`
+	Monster               int      `json:"monster"`
+	MappingMonsterAndType int      `json:"mapping_monster_and_type"`
+	MonsterImage          int      `json:"monster_image"`
+	MonsterRevision       int      `json:"monster_revision"`
+	ImageFile             int      `json:"image_file"`
+	MissingImageFiles     []string `json:"missing_image_files"`
+}
+
+// RestoreRes is result of restore
+type RestoreRes struct {
+	DryRun                bool   `json:"dry_run"`
+	Strategy              string `json:"strategy"`
+	SchemaVersion         uint   `json:"schema_version"`
+	MonsterCategory       int    `json:"monster_category"`
+	MonsterType           int    `json:"monster_type"`
+	Monster               int    `json:"monster"`
+	MappingMonsterAndType int    `json:"mapping_monster_and_type"`
+	MonsterImage          int    `json:"monster_image"`
+	MonsterRevision       int    `json:"monster_revision"`
+	ImageFileWritten      int    `json:"image_file_written"`
+	ImageFileUnchanged    int    `json:"image_file_unchanged"`
+}
@@ -0,0 +1,10 @@
+package model
+
+import "time"
+
+type Fingerprint struct {
+	Total           int64      `json:"total"`
+	Version         int64      `json:"version"`
+	LastModified    *time.Time `json:"last_modified"`
+	HasPrivateImage bool       `json:"has_private_image"`
+}
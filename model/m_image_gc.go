@@ -0,0 +1,30 @@
+package model
+
+import "time"
+
+type ImageGCReq struct {
+	GracePeriod time.Duration `json:"grace_period"`
+	DryRun      bool          `json:"dry_run"`
+}
+
+type ImageGCRes struct {
+	DryRun         bool            `json:"dry_run"`
+	OrphanedImages []OrphanedImage `json:"orphaned_images"`
+	MissingImages  []MissingImage  `json:"missing_images"`
+}
+
+type OrphanedImage struct {
+	ImageName  string    `json:"image_name"`
+	Size       int64     `json:"size"`
+	ModifiedAt time.Time `json:"modified_at"`
+	IsDeleted  bool      `json:"is_deleted"`
+}
+
+type MissingImage struct {
+	MonsterId      string `json:"monster_id"`
+	MonsterImageId string `json:"monster_image_id,omitempty"`
+	MonsterCode    uint16 `json:"monster_code"`
+	Name           string `json:"name"`
+	ImageName      string `json:"image_name"`
+	IsFlagged      bool   `json:"is_flagged"`
+}
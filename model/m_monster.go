@@ -2,17 +2,19 @@ package model
 
 import (
 	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/form"
 	"gorm.io/gorm"
 	"time"
 )
 
 type Monster struct {
 	ID                string          `json:"id" gorm:"unique;default:gen_random_uuid()"`
-	MonsterCode       uint16          `json:"monster_code"`
+	MonsterCode       uint16          `json:"monster_code" gorm:"default:(-)"`
 	Name              string          `json:"name"`
 	MonsterCategoryId string          `json:"monster_category_id"`
 	MonsterCategory   MonsterCategory `json:"monster_category" gorm:"foreignKey:MonsterCategoryId;references:ID"`
 	MonsterTypes      []MonsterType   `json:"monster_types" gorm:"many2many:mapping_monster_and_types;save_association:false"`
+	MonsterImages     []MonsterImage  `json:"monster_images" gorm:"foreignKey:MonsterId;references:ID"`
 	Description       string          `json:"description"`
 	Length            float32         `json:"length"`
 	Weight            uint16          `json:"weight"`
@@ -22,9 +24,17 @@ type Monster struct {
 	Speed             uint16          `json:"speed"`
 	IsCaught          bool            `json:"is_caught"`
 	ImageName         string          `json:"image_name"`
+	IsImageMissing    bool            `json:"is_image_missing"`
+	Version           int             `json:"version"`
+	ExternalId        *string         `json:"external_id"`
 	CreatedAt         time.Time       `json:"created_at"`
 	UpdatedAt         time.Time       `json:"updated_at"`
 	DeletedAt         *gorm.DeletedAt `json:"deleted_at"`
+
+	// selected by full-text search only
+	SearchRank           float32 `json:"-" gorm:"->"`
+	NameHighlight        string  `json:"-" gorm:"->"`
+	DescriptionHighlight string  `json:"-" gorm:"->"`
 }
 
 func (Monster) TableName() string {
@@ -32,14 +42,76 @@ func (Monster) TableName() string {
 }
 
 type MonsterQueryReq struct {
-	SortBy        string   `json:"sort_by"`
-	OrderBy       string   `json:"order_by"`
-	Name          string   `json:"name"`
-	MonsterTypeId []string `json:"monster_type_id"`
-	IsCaught      string   `json:"is_caught"`
+	Sort              []form.Sort `json:"sort"`
+	Name              string      `json:"name"`
+	Q                 string      `json:"q"`
+	Filter            string      `json:"filter"`
+	MonsterTypeId     []string    `json:"monster_type_id"`
+	MonsterTypeMatch  string      `json:"monster_type_match"`
+	MonsterCategoryId string      `json:"monster_category_id"`
+	IsCaught          *bool       `json:"is_caught"`
+	Fields            []string    `json:"fields,omitempty"`
+	Include           []string    `json:"include,omitempty"`
+	MonsterCode       RangeReq    `json:"monster_code"`
+	HP                RangeReq    `json:"hp"`
+	Attack            RangeReq    `json:"attack"`
+	Defends           RangeReq    `json:"defends"`
+	Speed             RangeReq    `json:"speed"`
+	Weight            RangeReq    `json:"weight"`
+	Length            RangeReq    `json:"length"`
+}
+
+// MonsterFacetRes is count of list monster per facet value, facet which is not requested or has no matched monster is omitted
+type MonsterFacetRes struct {
+	Type     []MonsterFacetCountRes `json:"type,omitempty"`
+	Category []MonsterFacetCountRes `json:"category,omitempty"`
+	Caught   *MonsterCaughtFacetRes `json:"caught,omitempty"`
+}
+
+// MonsterFacetCountRes is count of monster with facet value, value is id of monster type or monster category
+type MonsterFacetCountRes struct {
+	Value string `json:"value"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// MonsterCaughtFacetRes is count of caught and uncaught monster
+type MonsterCaughtFacetRes struct {
+	Caught   int64 `json:"caught"`
+	Uncaught int64 `json:"uncaught"`
+}
+
+// MonsterStatRange is difference of highest and lowest stat of active monster
+type MonsterStatRange struct {
+	HP      float64 `json:"hp"`
+	Attack  float64 `json:"attack"`
+	Defends float64 `json:"defends"`
+	Speed   float64 `json:"speed"`
+}
+
+// SimilarMonsterRes is monster ranked by similarity of stat, type, and category
+type SimilarMonsterRes struct {
+	ID              string          `json:"id"`
+	MonsterCode     uint16          `json:"monster_code"`
+	Name            string          `json:"name"`
+	MonsterCategory MonsterCategory `json:"monster_category"`
+	MonsterTypes    []MonsterType   `json:"monster_types"`
+	HP              uint16          `json:"hp"`
+	Attack          uint16          `json:"attack"`
+	Defends         uint16          `json:"defends"`
+	Speed           uint16          `json:"speed"`
+	ImageURL        string          `json:"image_url"`
+	Score           float64         `json:"score"`
+}
+
+// RangeReq is inclusive range filter, nil bound is not applied
+type RangeReq struct {
+	Min *float64 `json:"min"`
+	Max *float64 `json:"max"`
 }
 
 type CreateMonsterReq struct {
+	MonsterCode       uint16   `json:"monster_code" form:"monster_code"`
 	Name              string   `json:"name" form:"name" validate:"required"`
 	MonsterCategoryId string   `json:"monster_category_id" form:"monster_category_id" validate:"required"`
 	MonsterTypes      []string `json:"monster_types" form:"monster_types"`
@@ -51,50 +123,99 @@ type CreateMonsterReq struct {
 	Defends           uint16   `json:"defends" form:"defends" validate:"required"`
 	Speed             uint16   `json:"speed" form:"speed" validate:"required"`
 	ImageName         string   `json:"image_name"`
+	BlurHash          string   `json:"-"`
+	DominantColor     string   `json:"-"`
+	IsCaught          bool     `json:"-" form:"-"`
+	ExternalId        *string  `json:"-" form:"-"`
 }
 
 type GetListMonsterRes struct {
-	ID              string          `json:"id"`
-	MonsterCode     uint16          `json:"monster_code"`
-	Name            string          `json:"name"`
-	MonsterCategory MonsterCategory `json:"monster_category"`
-	MonsterTypes    []MonsterType   `json:"monster_types"`
-	IsCaught        bool            `json:"is_caught"`
-	ImageName       string          `json:"image_name"`
-	ImageURL        string          `json:"image_url"`
+	ID              string           `json:"id"`
+	MonsterCode     uint16           `json:"monster_code"`
+	Name            string           `json:"name"`
+	MonsterCategory MonsterCategory  `json:"monster_category"`
+	MonsterTypes    []MonsterType    `json:"monster_types"`
+	IsCaught        bool             `json:"is_caught"`
+	ImageName       string           `json:"image_name"`
+	ImageURL        string           `json:"image_url"`
+	BlurHash        string           `json:"blur_hash"`
+	DominantColor   string           `json:"dominant_color"`
+	PrimaryImage    *MonsterImageRes `json:"primary_image"`
+	// filled only when asked for by sparse field
+	Description *string           `json:"description,omitempty"`
+	Length      *float32          `json:"length,omitempty"`
+	Weight      *uint16           `json:"weight,omitempty"`
+	HP          *uint16           `json:"hp,omitempty"`
+	Attack      *uint16           `json:"attack,omitempty"`
+	Defends     *uint16           `json:"defends,omitempty"`
+	Speed       *uint16           `json:"speed,omitempty"`
+	CreatedAt   *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty"`
+	SearchRank  *float32          `json:"search_rank,omitempty"`
+	Highlight   *MonsterHighlight `json:"highlight,omitempty"`
+}
+
+type MonsterHighlight struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type MonsterSuggestionRes struct {
+	ID          string `json:"id"`
+	MonsterCode uint16 `json:"monster_code"`
+	Name        string `json:"name"`
 }
 
 type GetDetailMonsterRes struct {
-	ID              string          `json:"id" gorm:"unique;default:gen_random_uuid()"`
-	MonsterCode     uint16          `json:"monster_code"`
-	Name            string          `json:"name"`
-	MonsterCategory MonsterCategory `json:"monster_category" gorm:"foreignKey:MonsterCategoryId;references:ID"`
-	MonsterTypes    []MonsterType   `json:"monster_types" gorm:"many2many:mapping_monster_and_types;save_association:false"`
-	Description     string          `json:"description"`
-	Length          float32         `json:"length"`
-	Weight          uint16          `json:"weight"`
-	HP              uint16          `json:"hp"`
-	Attack          uint16          `json:"attack"`
-	Defends         uint16          `json:"defends"`
-	Speed           uint16          `json:"speed"`
-	IsCaught        bool            `json:"is_caught"`
-	ImageName       string          `json:"image_name"`
-	ImageURL        string          `json:"image_url"`
+	ID              string            `json:"id" gorm:"unique;default:gen_random_uuid()"`
+	MonsterCode     uint16            `json:"monster_code"`
+	Name            string            `json:"name"`
+	MonsterCategory MonsterCategory   `json:"monster_category" gorm:"foreignKey:MonsterCategoryId;references:ID"`
+	MonsterTypes    []MonsterType     `json:"monster_types" gorm:"many2many:mapping_monster_and_types;save_association:false"`
+	Description     string            `json:"description"`
+	Length          float32           `json:"length"`
+	Weight          uint16            `json:"weight"`
+	HP              uint16            `json:"hp"`
+	Attack          uint16            `json:"attack"`
+	Defends         uint16            `json:"defends"`
+	Speed           uint16            `json:"speed"`
+	IsCaught        bool              `json:"is_caught"`
+	ImageName       string            `json:"image_name"`
+	ImageURL        string            `json:"image_url"`
+	IsImageMissing  bool              `json:"is_image_missing"`
+	Version         int               `json:"version"`
+	Images          []MonsterImageRes `json:"images"`
 }
 
 type UpdateMonsterReq struct {
-	Name              string   `json:"name" form:"name"`
-	MonsterCategoryId string   `json:"monster_category_id" form:"monster_category_id"`
+	Name              string   `json:"name" form:"name" validate:"required"`
+	MonsterCategoryId string   `json:"monster_category_id" form:"monster_category_id" validate:"required"`
 	MonsterTypes      []string `json:"monster_types" form:"monster_types"`
-	Description       string   `json:"description" form:"description"`
-	Length            float32  `json:"length" form:"length"`
-	Weight            uint16   `json:"weight" form:"weight"`
-	HP                uint16   `json:"hp" form:"hp"`
-	Attack            uint16   `json:"attack" form:"attack"`
-	Defends           uint16   `json:"defends" form:"defends"`
-	Speed             uint16   `json:"speed" form:"speed"`
-	IsCaught          bool     `json:"is_caught" form:"is_caught"`
+	Description       *string  `json:"description" form:"description" validate:"required"`
+	Length            *float32 `json:"length" form:"length" validate:"required"`
+	Weight            *uint16  `json:"weight" form:"weight" validate:"required"`
+	HP                *uint16  `json:"hp" form:"hp" validate:"required"`
+	Attack            *uint16  `json:"attack" form:"attack" validate:"required"`
+	Defends           *uint16  `json:"defends" form:"defends" validate:"required"`
+	Speed             *uint16  `json:"speed" form:"speed" validate:"required"`
+	IsCaught          *bool    `json:"is_caught" form:"is_caught" validate:"required"`
 	ImageName         string   `json:"image_name"`
+	BlurHash          string   `json:"-"`
+	DominantColor     string   `json:"-"`
+}
+
+type PatchMonsterReq struct {
+	Name              form.Optional[string]   `json:"name"`
+	MonsterCategoryId form.Optional[string]   `json:"monster_category_id"`
+	MonsterTypes      form.Optional[[]string] `json:"monster_types"`
+	Description       form.Optional[string]   `json:"description"`
+	Length            form.Optional[float32]  `json:"length"`
+	Weight            form.Optional[uint16]   `json:"weight"`
+	HP                form.Optional[uint16]   `json:"hp"`
+	Attack            form.Optional[uint16]   `json:"attack"`
+	Defends           form.Optional[uint16]   `json:"defends"`
+	Speed             form.Optional[uint16]   `json:"speed"`
+	IsCaught          form.Optional[bool]     `json:"is_caught"`
 }
 
 type UpdateMonsterCapturedReq struct {
@@ -0,0 +1,23 @@
+package model
+
+import "encoding/json"
+
+type BulkMonsterOperation struct {
+	Op            string           `json:"op"`
+	ID            string           `json:"id"`
+	Version       int              `json:"version"`
+	Data          json.RawMessage  `json:"data"`
+	Index         int              `json:"-"`
+	InvalidReason string           `json:"-"`
+	CreateReq     CreateMonsterReq `json:"-"`
+	UpdateReq     UpdateMonsterReq `json:"-"`
+}
+
+type BulkMonsterResultRes struct {
+	Index   int    `json:"index"`
+	Op      string `json:"op"`
+	ID      string `json:"id,omitempty"`
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"github.com/lib/pq"
+	"time"
+)
+
+// MonsterExport is monster row of export, category and type are referenced by name
+type MonsterExport struct {
+	ID           string         `json:"id"`
+	MonsterCode  uint16         `json:"monster_code"`
+	Name         string         `json:"name"`
+	CategoryName string         `json:"category_name"`
+	TypeNames    pq.StringArray `json:"type_names" gorm:"type:text[]"`
+	Description  string         `json:"description"`
+	Length       float32        `json:"length"`
+	Weight       uint16         `json:"weight"`
+	HP           uint16         `json:"hp"`
+	Attack       uint16         `json:"attack"`
+	Defends      uint16         `json:"defends"`
+	Speed        uint16         `json:"speed"`
+	IsCaught     bool           `json:"is_caught"`
+	ImageName    string         `json:"image_name"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/lib/pq"
+	"time"
+)
+
+type MonsterImage struct {
+	ID            string         `json:"id" gorm:"unique;default:gen_random_uuid()"`
+	MonsterId     string         `json:"monster_id"`
+	ImageName     string         `json:"image_name"`
+	Caption       string         `json:"caption"`
+	SortOrder     int            `json:"sort_order"`
+	IsPrimary     bool           `json:"is_primary"`
+	IsPrivate     bool           `json:"is_private"`
+	VariantTags   pq.StringArray `json:"variant_tags" gorm:"type:text[]"`
+	BlurHash      string         `json:"blur_hash"`
+	DominantColor string         `json:"dominant_color"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+func (MonsterImage) TableName() string {
+	return constants.MonsterImageTable
+}
+
+type CreateMonsterImageReq struct {
+	Caption       string   `json:"caption" form:"caption" validate:"max=255"`
+	IsPrimary     bool     `json:"is_primary" form:"is_primary"`
+	IsPrivate     bool     `json:"is_private" form:"is_private"`
+	VariantTags   []string `json:"variant_tags" form:"variant_tags"`
+	ImageName     string   `json:"image_name"`
+	BlurHash      string   `json:"-"`
+	DominantColor string   `json:"-"`
+}
+
+type ReorderMonsterImageReq struct {
+	ImageIds []string `json:"image_ids" form:"image_ids" validate:"required,min=1,dive,uuid"`
+}
+
+type UpdateMonsterImageVisibilityReq struct {
+	IsPrivate bool `json:"is_private" form:"is_private"`
+}
+
+type MonsterImageRes struct {
+	ID            string   `json:"id"`
+	ImageName     string   `json:"image_name"`
+	ImageURL      string   `json:"image_url"`
+	Caption       string   `json:"caption"`
+	SortOrder     int      `json:"sort_order"`
+	IsPrimary     bool     `json:"is_primary"`
+	IsPrivate     bool     `json:"is_private"`
+	VariantTags   []string `json:"variant_tags"`
+	BlurHash      string   `json:"blur_hash"`
+	DominantColor string   `json:"dominant_color"`
+}
+
+type ImagePlaceholderBackfillRes struct {
+	Total   int                       `json:"total"`
+	Updated int                       `json:"updated"`
+	Failed  []ImagePlaceholderFailure `json:"failed"`
+}
+
+type ImagePlaceholderFailure struct {
+	ID        string `json:"id"`
+	ImageName string `json:"image_name"`
+	Reason    string `json:"reason"`
+}
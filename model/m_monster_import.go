@@ -0,0 +1,17 @@
+package model
+
+type ImportMonsterRes struct {
+	DryRun   bool                  `json:"dry_run"`
+	Total    int                   `json:"total"`
+	Valid    int                   `json:"valid"`
+	Invalid  int                   `json:"invalid"`
+	Imported int                   `json:"imported"`
+	Rows     []ImportMonsterRowRes `json:"rows"`
+}
+
+type ImportMonsterRowRes struct {
+	Line        int      `json:"line"`
+	MonsterCode uint16   `json:"monster_code"`
+	Name        string   `json:"name"`
+	Errors      []string `json:"errors"`
+}
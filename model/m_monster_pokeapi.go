@@ -0,0 +1,24 @@
+package model
+
+type PokeAPIImportRes struct {
+	DryRun           bool                   `json:"dry_run"`
+	Total            int                    `json:"total"`
+	TypeCreated      int                    `json:"type_created"`
+	TypeLinked       int                    `json:"type_linked"`
+	CategoryCreated  int                    `json:"category_created"`
+	CategoryLinked   int                    `json:"category_linked"`
+	MonsterCreated   int                    `json:"monster_created"`
+	MonsterUpdated   int                    `json:"monster_updated"`
+	MonsterLinked    int                    `json:"monster_linked"`
+	MonsterUnchanged int                    `json:"monster_unchanged"`
+	MonsterSkipped   int                    `json:"monster_skipped"`
+	Failed           []PokeAPIImportFailure `json:"failed"`
+}
+
+type PokeAPIImportFailure struct {
+	ExternalId string `json:"external_id"`
+	Name       string `json:"name"`
+	Code       int    `json:"code"`
+	Message    string `json:"message"`
+	Error      string `json:"error"`
+}
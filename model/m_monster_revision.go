@@ -0,0 +1,57 @@
+package model
+
+import (
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/diff"
+	"github.com/lib/pq"
+	"time"
+)
+
+type MonsterRevision struct {
+	ID        string    `json:"id" gorm:"unique;default:gen_random_uuid()"`
+	MonsterId string    `json:"monster_id"`
+	Version   int       `json:"version"`
+	Action    string    `json:"action"`
+	ActorId   *string   `json:"actor_id"`
+	ActorName *string   `json:"actor_name" gorm:"->"`
+	Diff      string    `json:"diff" gorm:"type:jsonb"`
+	Snapshot  string    `json:"snapshot" gorm:"type:jsonb"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (MonsterRevision) TableName() string {
+	return constants.MonsterRevisionTable
+}
+
+// MonsterSnapshot is state of monster after an action, monster types is sorted so snapshot can be compared
+type MonsterSnapshot struct {
+	ID                string         `json:"-"`
+	Version           int            `json:"-"`
+	MonsterCode       uint16         `json:"monster_code"`
+	Name              string         `json:"name"`
+	MonsterCategoryId string         `json:"monster_category_id"`
+	MonsterTypes      pq.StringArray `json:"monster_types" gorm:"type:text[]"`
+	Description       string         `json:"description"`
+	Length            float32        `json:"length"`
+	Weight            uint16         `json:"weight"`
+	HP                uint16         `json:"hp"`
+	Attack            uint16         `json:"attack"`
+	Defends           uint16         `json:"defends"`
+	Speed             uint16         `json:"speed"`
+	IsCaught          bool           `json:"is_caught"`
+	ImageName         string         `json:"image_name"`
+	IsImageMissing    bool           `json:"is_image_missing"`
+	IsDeleted         bool           `json:"is_deleted"`
+}
+
+type MonsterRevisionRes struct {
+	ID        string                 `json:"id"`
+	MonsterId string                 `json:"monster_id"`
+	Version   int                    `json:"version"`
+	Action    string                 `json:"action"`
+	ActorId   *string                `json:"actor_id"`
+	ActorName *string                `json:"actor_name"`
+	Diff      map[string]diff.Change `json:"diff"`
+	Snapshot  MonsterSnapshot        `json:"snapshot"`
+	CreatedAt time.Time              `json:"created_at"`
+}
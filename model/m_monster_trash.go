@@ -0,0 +1,27 @@
+package model
+
+import "time"
+
+type DeletedMonsterRes struct {
+	ID              string          `json:"id"`
+	MonsterCode     uint16          `json:"monster_code"`
+	Name            string          `json:"name"`
+	MonsterCategory MonsterCategory `json:"monster_category"`
+	MonsterTypes    []MonsterType   `json:"monster_types"`
+	ImageName       string          `json:"image_name"`
+	Version         int             `json:"version"`
+	DeletedAt       time.Time       `json:"deleted_at"`
+}
+
+type MonsterPurgeRes struct {
+	RetentionDays int                   `json:"retention_days"`
+	DeletedBefore time.Time             `json:"deleted_before"`
+	Total         int                   `json:"total"`
+	Purged        []string              `json:"purged"`
+	Failed        []MonsterPurgeFailure `json:"failed"`
+}
+
+type MonsterPurgeFailure struct {
+	ID     string `json:"id"`
+	Reason string `json:"reason"`
+}
@@ -7,11 +7,12 @@ import (
 )
 
 type MonsterType struct {
-	ID        string          `json:"id" gorm:"unique;default:gen_random_uuid()"`
-	Name      string          `json:"name"`
-	CreatedAt time.Time       `json:"-"`
-	UpdatedAt time.Time       `json:"-"`
-	DeletedAt *gorm.DeletedAt `json:"-"`
+	ID         string          `json:"id" gorm:"unique;default:gen_random_uuid()"`
+	Name       string          `json:"name"`
+	ExternalId *string         `json:"-"`
+	CreatedAt  time.Time       `json:"-"`
+	UpdatedAt  time.Time       `json:"-"`
+	DeletedAt  *gorm.DeletedAt `json:"-"`
 }
 
 func (MonsterType) TableName() string {
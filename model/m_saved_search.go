@@ -0,0 +1,42 @@
+package model
+
+import (
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"time"
+)
+
+type SavedSearch struct {
+	ID        string    `json:"id" gorm:"unique;default:gen_random_uuid()"`
+	UserId    string    `json:"user_id"`
+	Name      string    `json:"name"`
+	Query     string    `json:"query" gorm:"type:jsonb"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (SavedSearch) TableName() string {
+	return constants.SavedSearchTable
+}
+
+type SavedSearchReq struct {
+	Name  string          `json:"name" validate:"required,max=100"`
+	Query MonsterQueryReq `json:"query"`
+}
+
+// SavedSearchRes is saved search of user, saved search is stale when monster type or monster category it filters by
+// has been deleted since it was saved
+type SavedSearchRes struct {
+	ID        string               `json:"id"`
+	Name      string               `json:"name"`
+	Query     MonsterQueryReq      `json:"query"`
+	IsStale   bool                 `json:"is_stale"`
+	Stale     *SavedSearchStaleRes `json:"stale,omitempty"`
+	CreatedAt time.Time            `json:"created_at"`
+	UpdatedAt time.Time            `json:"updated_at"`
+}
+
+// SavedSearchStaleRes is deleted monster type and monster category referenced by saved search
+type SavedSearchStaleRes struct {
+	MonsterTypeId     []string `json:"monster_type_id,omitempty"`
+	MonsterCategoryId string   `json:"monster_category_id,omitempty"`
+}
@@ -0,0 +1,376 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+)
+
+// backupBatchSize is number of row inserted per statement on restore
+const backupBatchSize = 500
+
+// BackupRepositoryInterface is
+type BackupRepositoryInterface interface {
+	GetSchemaVersion(ctx context.Context) (version uint, dirty bool, err error)
+	GetAllBackupMonsterCategory(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterCategory, err error)
+	GetAllBackupMonsterType(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterType, err error)
+	GetAllBackupMonster(tx *gorm.DB, ctx context.Context) (res []model.BackupMonster, err error)
+	GetAllBackupMappingMonsterAndType(tx *gorm.DB, ctx context.Context) (res []model.BackupMappingMonsterAndType, err error)
+	GetAllBackupMonsterImage(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterImage, err error)
+	GetAllBackupMonsterRevision(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterRevision, err error)
+	DeleteAllMonsterData(tx *gorm.DB, ctx context.Context) (err error)
+	UpsertMonsterCategory(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterCategory) (err error)
+	UpsertMonsterType(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterType) (err error)
+	UpsertMonster(tx *gorm.DB, ctx context.Context, req []model.BackupMonster) (err error)
+	ReplaceMappingMonsterAndType(tx *gorm.DB, ctx context.Context, monsterIds []string, req []model.BackupMappingMonsterAndType) (err error)
+	ReplaceMonsterImage(tx *gorm.DB, ctx context.Context, monsterIds []string, req []model.BackupMonsterImage) (err error)
+	CreateMonsterRevision(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterRevision) (err error)
+	ResetMonsterCodeSequence(tx *gorm.DB, ctx context.Context) (err error)
+	Transaction() (tx *gorm.DB, resCode int, err error)
+}
+
+type backupRepository struct {
+	dbConn *gorm.DB
+}
+
+func NewBackupRepository(db *gorm.DB) BackupRepositoryInterface {
+	return &backupRepository{
+		dbConn: db,
+	}
+}
+
+// GetSchemaVersion is repository to get database schema version of migration
+func (rBackup *backupRepository) GetSchemaVersion(ctx context.Context) (version uint, dirty bool, err error) {
+	var res struct {
+		Version uint
+		Dirty   bool
+	}
+	result := rBackup.dbConn.WithContext(ctx).Raw(`SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&res)
+	if result.Error != nil {
+		return 0, false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, false, gorm.ErrRecordNotFound
+	}
+
+	return res.Version, res.Dirty, nil
+}
+
+// GetAllBackupMonsterCategory is repository to get all monster category, including soft deleted monster category
+func (rBackup *backupRepository) GetAllBackupMonsterCategory(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterCategory, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all monster category
+	err = conn.WithContext(ctx).Table(constants.MonsterCategoryTable).Order(`id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllBackupMonsterType is repository to get all monster type, including soft deleted monster type
+func (rBackup *backupRepository) GetAllBackupMonsterType(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterType, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all monster type
+	err = conn.WithContext(ctx).Table(constants.MonsterTypeTable).Order(`id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllBackupMonster is repository to get all monster, including soft deleted monster
+func (rBackup *backupRepository) GetAllBackupMonster(tx *gorm.DB, ctx context.Context) (res []model.BackupMonster, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all monster
+	err = conn.WithContext(ctx).Table(constants.MonsterTable).Order(`monster_code ASC, id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllBackupMappingMonsterAndType is repository to get all mapping monster and type
+func (rBackup *backupRepository) GetAllBackupMappingMonsterAndType(tx *gorm.DB, ctx context.Context) (res []model.BackupMappingMonsterAndType, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all mapping monster and type
+	err = conn.WithContext(ctx).Table(constants.MappingMonsterAndTypes).Order(`monster_id ASC, monster_type_id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllBackupMonsterImage is repository to get all monster image
+func (rBackup *backupRepository) GetAllBackupMonsterImage(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterImage, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).Order(`monster_id ASC, sort_order ASC, id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllBackupMonsterRevision is repository to get all monster revision
+func (rBackup *backupRepository) GetAllBackupMonsterRevision(tx *gorm.DB, ctx context.Context) (res []model.BackupMonsterRevision, err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get all monster revision
+	err = conn.WithContext(ctx).Table(constants.MonsterRevisionTable).Order(`monster_id ASC, created_at ASC, id ASC`).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// DeleteAllMonsterData is repository to delete every monster, its mapping, image, revision, and every monster category and type
+func (rBackup *backupRepository) DeleteAllMonsterData(tx *gorm.DB, ctx context.Context) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete all monster data
+	tables := []string{
+		constants.MonsterRevisionTable,
+		constants.MonsterImageTable,
+		constants.MappingMonsterAndTypes,
+		constants.MonsterTable,
+		constants.MonsterTypeTable,
+		constants.MonsterCategoryTable,
+	}
+	for i := 0; i < len(tables); i++ {
+		err = conn.WithContext(ctx).Exec(fmt.Sprintf(`DELETE FROM %s`, tables[i])).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// UpsertMonsterCategory is repository to create monster category, or update monster category with same id
+func (rBackup *backupRepository) UpsertMonsterCategory(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterCategory) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// upsert monster category
+	err = conn.WithContext(ctx).Table(constants.MonsterCategoryTable).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "external_id", "created_at", "updated_at", "deleted_at"}),
+		}).
+		CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertMonsterType is repository to create monster type, or update monster type with same id
+func (rBackup *backupRepository) UpsertMonsterType(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterType) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// upsert monster type
+	err = conn.WithContext(ctx).Table(constants.MonsterTypeTable).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "external_id", "created_at", "updated_at", "deleted_at"}),
+		}).
+		CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UpsertMonster is repository to create monster, or update monster with same id
+func (rBackup *backupRepository) UpsertMonster(tx *gorm.DB, ctx context.Context, req []model.BackupMonster) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// upsert monster
+	err = conn.WithContext(ctx).Table(constants.MonsterTable).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"monster_code", "name", "monster_category_id", "description", "length",
+				"weight", "hp", "attack", "defends", "speed", "is_caught", "image_name", "is_image_missing", "version",
+				"external_id", "created_at", "updated_at", "deleted_at"}),
+		}).
+		CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceMappingMonsterAndType is repository to replace mapping monster and type of given monster
+func (rBackup *backupRepository) ReplaceMappingMonsterAndType(tx *gorm.DB, ctx context.Context, monsterIds []string, req []model.BackupMappingMonsterAndType) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete mapping monster and type of monster
+	err = conn.WithContext(ctx).Table(constants.MappingMonsterAndTypes).
+		Where(`monster_id = ANY(?)`, pq.StringArray(monsterIds)).
+		Delete(&model.BackupMappingMonsterAndType{}).Error
+	if err != nil {
+		return err
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// create mapping monster and type
+	err = conn.WithContext(ctx).Table(constants.MappingMonsterAndTypes).CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReplaceMonsterImage is repository to replace monster image of given monster, image file is not touched
+func (rBackup *backupRepository) ReplaceMonsterImage(tx *gorm.DB, ctx context.Context, monsterIds []string, req []model.BackupMonsterImage) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete monster image of monster, image of same id may belong to other monster
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`monster_id = ANY(?)`, pq.StringArray(monsterIds)).
+		Delete(&model.BackupMonsterImage{}).Error
+	if err != nil {
+		return err
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// upsert monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"monster_id", "image_name", "caption", "sort_order", "is_primary",
+				"is_private", "variant_tags", "blur_hash", "dominant_color", "created_at", "updated_at"}),
+		}).
+		CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreateMonsterRevision is repository to create monster revision, revision which already exists is skipped since revision is append-only
+func (rBackup *backupRepository) CreateMonsterRevision(tx *gorm.DB, ctx context.Context, req []model.BackupMonsterRevision) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+	if len(req) == 0 {
+		return nil
+	}
+
+	// create monster revision
+	err = conn.WithContext(ctx).Table(constants.MonsterRevisionTable).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		CreateInBatches(&req, backupBatchSize).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ResetMonsterCodeSequence is repository to set monster code sequence to the highest monster code, sequence may go backward
+func (rBackup *backupRepository) ResetMonsterCodeSequence(tx *gorm.DB, ctx context.Context) (err error) {
+	// transaction
+	conn := rBackup.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// reset monster code sequence
+	err = conn.WithContext(ctx).
+		Exec(fmt.Sprintf(`SELECT setval('%s', GREATEST((SELECT COALESCE(MAX(monster_code), 0) FROM %s), 1), (SELECT COUNT(*) > 0 FROM %s))`,
+			constants.MonsterCodeSequence, constants.MonsterTable, constants.MonsterTable)).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Transaction is repository to create transactional database
+func (rBackup *backupRepository) Transaction() (tx *gorm.DB, resCode int, err error) {
+	return rBackup.dbConn, http.StatusInternalServerError, nil
+}
@@ -2,24 +2,41 @@ package repository
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/search"
 	"github.com/frianlh/pokedex-api/model"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"net/http"
+	"time"
 )
 
 // MonsterRepositoryInterface is
 type MonsterRepositoryInterface interface {
 	CreateMonster(tx *gorm.DB, ctx context.Context, req model.Monster) (monsterId string, err error)
-	GetMonsterById(ctx context.Context, reqId string, params map[string]interface{}) (res model.Monster, err error)
+	GetMonsterById(tx *gorm.DB, ctx context.Context, reqId string, params map[string]interface{}) (res model.Monster, err error)
 	GetListMonster(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}) (res []model.Monster, err error)
+	GetListMonsterFacet(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}, facet string) (res []model.MonsterFacetCountRes, err error)
+	GetMonsterStatRange(ctx context.Context) (res model.MonsterStatRange, err error)
+	StreamListMonster(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}, fn func(res model.MonsterExport) error) (err error)
+	GetListMonsterSuggestion(ctx context.Context, prefix string, limit int) (res []model.MonsterSuggestionRes, err error)
 	UpdateMonster(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error)
+	UpdateMonsterVersion(tx *gorm.DB, ctx context.Context, reqId string, expectedVersion int) (err error)
 	SoftDeleterMonster(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error)
 	CreateMappingMonsterAndType(tx *gorm.DB, ctx context.Context, req []model.MappingMonsterAndTypes) (err error)
 	DeleteMappingMonsterAndType(tx *gorm.DB, ctx context.Context, reqId string) (err error)
-	GetLastMonsterCode() (res uint16, err error)
+	SyncMonsterCodeSequence(tx *gorm.DB, ctx context.Context, monsterCode uint16) (err error)
+	GetAllMonsterImage(ctx context.Context) (res []model.Monster, err error)
+	GetMonsterFingerprint(ctx context.Context, reqId string) (res model.Fingerprint, err error)
+	GetMonsterSnapshot(tx *gorm.DB, ctx context.Context, reqId string) (res model.MonsterSnapshot, err error)
+	GetListDeletedMonster(ctx context.Context, page, perPage int) (res []model.Monster, count int64, err error)
+	GetDeletedMonsterById(ctx context.Context, reqId string) (res model.Monster, err error)
+	GetListDeletedMonsterId(ctx context.Context, deletedBefore time.Time) (res []string, err error)
+	RestoreMonster(tx *gorm.DB, ctx context.Context, reqId string, isImageMissing bool) (err error)
+	HardDeleteMonster(tx *gorm.DB, ctx context.Context, reqId string) (err error)
+	GetListMonsterByNameOrCode(ctx context.Context, names []string, monsterCodes []uint16) (res []model.Monster, err error)
+	GetListMonsterByExternalIdOrName(ctx context.Context, externalIds []string, names []string) (res []model.Monster, err error)
 	Transaction() (tx *gorm.DB, resCode int, err error)
 }
 
@@ -51,8 +68,14 @@ func (rMonster *monsterRepository) CreateMonster(tx *gorm.DB, ctx context.Contex
 }
 
 // GetMonsterById is repository to get monster by id
-func (rMonster *monsterRepository) GetMonsterById(ctx context.Context, reqId string, params map[string]interface{}) (res model.Monster, err error) {
-	query := rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable)
+func (rMonster *monsterRepository) GetMonsterById(tx *gorm.DB, ctx context.Context, reqId string, params map[string]interface{}) (res model.Monster, err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	query := conn.WithContext(ctx).Table(constants.MonsterTable)
 
 	// query params
 	if params["preloadParams"] != nil {
@@ -66,6 +89,14 @@ func (rMonster *monsterRepository) GetMonsterById(ctx context.Context, reqId str
 				query = query.Preload(index, func(query *gorm.DB) *gorm.DB {
 					return query.Select(`id, name`)
 				})
+			case "MonsterImages":
+				query = query.Preload(index, func(query *gorm.DB) *gorm.DB {
+					return query.Order(`sort_order ASC, created_at ASC`)
+				})
+			case "PrimaryMonsterImage":
+				query = query.Preload("MonsterImages", func(query *gorm.DB) *gorm.DB {
+					return query.Where(`is_primary = ?`, true)
+				})
 			}
 		}
 	}
@@ -86,20 +117,11 @@ func (rMonster *monsterRepository) GetMonsterById(ctx context.Context, reqId str
 func (rMonster *monsterRepository) GetListMonster(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}) (res []model.Monster, err error) {
 	query := rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable)
 
-	// query params
-	query = query.Order(fmt.Sprintf("monsters.%s %s", queryReq.SortBy, queryReq.OrderBy))
-	if params["whereParams"] != nil {
-		if params["whereParams"].(map[string]interface{})["default"] != nil {
-			for index, value := range params["whereParams"].(map[string]interface{})["default"].(map[string]interface{}) {
-				query = query.Where(index, value)
-			}
-		}
-		if params["whereParams"].(map[string]interface{})["in"] != nil {
-			for index, value := range params["whereParams"].(map[string]interface{})["in"].(map[string]interface{}) {
-				query = query.Where(index, value)
-			}
-		}
+	// query params, order is built from whitelisted sort field
+	if params["orderParams"] != nil {
+		query = query.Clauses(clause.OrderBy{Expression: params["orderParams"].(clause.Expr)})
 	}
+	query = monsterListWhere(query, queryReq, params)
 	if params["preloadParams"] != nil {
 		for index, _ := range params["preloadParams"].(map[string]interface{}) {
 			switch index {
@@ -111,19 +133,35 @@ func (rMonster *monsterRepository) GetListMonster(ctx context.Context, queryReq
 				query = query.Preload(index, func(query *gorm.DB) *gorm.DB {
 					return query.Select(`id, name`)
 				})
+			case "MonsterImages":
+				query = query.Preload(index, func(query *gorm.DB) *gorm.DB {
+					return query.Order(`sort_order ASC, created_at ASC`)
+				})
+			case "PrimaryMonsterImage":
+				query = query.Preload("MonsterImages", func(query *gorm.DB) *gorm.DB {
+					return query.Where(`is_primary = ?`, true)
+				})
 			}
 		}
 	}
 	if params["joinParams"] != nil {
 		for index, value := range params["joinParams"].(map[string]interface{}) {
-			if value.(bool) {
-				query = query.Joins(index)
+			switch value := value.(type) {
+			case bool:
+				if value {
+					query = query.Joins(index)
+				}
+			default:
+				query = query.Joins(index, value)
 			}
 		}
 	}
 	if params["selectParams"] != nil {
 		query = query.Select(params["selectParams"])
 	}
+	if params["limitParams"] != nil {
+		query = query.Limit(params["limitParams"].(int))
+	}
 
 	// get list monster
 	err = query.Find(&res).Error
@@ -134,6 +172,189 @@ func (rMonster *monsterRepository) GetListMonster(ctx context.Context, queryReq
 	return res, nil
 }
 
+// GetListMonsterFacet is repository to count active monster of list monster condition grouped by facet value, monster type
+// is counted from mapping of matched monster so monster with many type is counted once per type
+func (rMonster *monsterRepository) GetListMonsterFacet(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}, facet string) (res []model.MonsterFacetCountRes, err error) {
+	query := rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).Where(`monsters.deleted_at IS NULL`)
+	query = monsterListWhere(query, queryReq, params)
+
+	// count monster per facet value
+	switch facet {
+	case constants.MonsterFacetType:
+		query = rMonster.dbConn.WithContext(ctx).Table(constants.MappingMonsterAndTypes+` map`).
+			Select(`map.monster_type_id AS value, monster_types.name, COUNT(*) AS count`).
+			Joins(`INNER JOIN monster_types ON monster_types.id = map.monster_type_id`).
+			Where(`map.monster_id IN (?)`, query.Select(`monsters.id`)).
+			Group(`map.monster_type_id, monster_types.name`)
+	case constants.MonsterFacetCategory:
+		query = query.Select(`monsters.monster_category_id AS value, monster_categories.name, COUNT(*) AS count`).
+			Joins(`INNER JOIN monster_categories ON monster_categories.id = monsters.monster_category_id`).
+			Group(`monsters.monster_category_id, monster_categories.name`)
+	case constants.MonsterFacetCaught:
+		query = query.Select(`CAST(monsters.is_caught AS text) AS value, COUNT(*) AS count`).
+			Group(`monsters.is_caught`)
+	default:
+		return nil, fmt.Errorf("facet %q is not supported", facet)
+	}
+	err = query.Order(`count DESC, value ASC`).Scan(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetMonsterStatRange is repository to get range of every stat of active monster, it is used to normalize stat vector
+func (rMonster *monsterRepository) GetMonsterStatRange(ctx context.Context) (res model.MonsterStatRange, err error) {
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Select(`COALESCE(MAX(hp) - MIN(hp), 0) AS hp, COALESCE(MAX(attack) - MIN(attack), 0) AS attack,
+			COALESCE(MAX(defends) - MIN(defends), 0) AS defends, COALESCE(MAX(speed) - MIN(speed), 0) AS speed`).
+		Where(`deleted_at IS NULL`).
+		Scan(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// StreamListMonster is repository to stream list monster from database cursor, fn is called for every row as it is read
+// so memory usage does not grow with row count, streaming stops at the first error of fn
+func (rMonster *monsterRepository) StreamListMonster(ctx context.Context, queryReq model.MonsterQueryReq, params map[string]interface{}, fn func(res model.MonsterExport) error) (err error) {
+	query := rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Select(`monsters.id, monsters.monster_code, monsters.name, monster_categories.name AS category_name,
+			COALESCE((SELECT array_agg(mt.name ORDER BY mt.name)
+			          FROM mapping_monster_and_types map
+			          INNER JOIN monster_types mt ON mt.id = map.monster_type_id
+			          WHERE map.monster_id = monsters.id), '{}') AS type_names,
+			monsters.description, monsters.length, monsters.weight, monsters.hp, monsters.attack, monsters.defends,
+			monsters.speed, monsters.is_caught,
+			COALESCE((SELECT img.image_name
+			          FROM monster_images img
+			          WHERE img.monster_id = monsters.id AND img.is_primary = true
+			          LIMIT 1), monsters.image_name) AS image_name,
+			monsters.created_at, monsters.updated_at`).
+		Joins(`LEFT JOIN monster_categories ON monster_categories.id = monsters.monster_category_id`).
+		Where(`monsters.deleted_at IS NULL`)
+
+	// query params, order is built from whitelisted sort field and condition is shared with list monster
+	if params["orderParams"] != nil {
+		query = query.Clauses(clause.OrderBy{Expression: params["orderParams"].(clause.Expr)})
+	}
+	query = monsterListWhere(query, queryReq, params)
+
+	// stream list monster
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var res model.MonsterExport
+		err = rMonster.dbConn.ScanRows(rows, &res)
+		if err != nil {
+			return err
+		}
+		err = fn(res)
+		if err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// monsterListWhere is function to apply where params, filter, and filter expression of list monster
+func monsterListWhere(query *gorm.DB, queryReq model.MonsterQueryReq, params map[string]interface{}) *gorm.DB {
+	if params["whereParams"] != nil {
+		if params["whereParams"].(map[string]interface{})["default"] != nil {
+			for index, value := range params["whereParams"].(map[string]interface{})["default"].(map[string]interface{}) {
+				query = query.Where(index, value)
+			}
+		}
+		if params["whereParams"].(map[string]interface{})["in"] != nil {
+			for index, value := range params["whereParams"].(map[string]interface{})["in"].(map[string]interface{}) {
+				query = query.Where(index, value)
+			}
+		}
+	}
+	query = monsterFilter(query, queryReq)
+	if params["filterParams"] != nil {
+		query = query.Where(params["filterParams"])
+	}
+
+	return query
+}
+
+// monsterFilter is function to apply category, monster type, and range filter of list monster, range column is fixed by field
+// and every value is bound as query argument
+func monsterFilter(query *gorm.DB, queryReq model.MonsterQueryReq) *gorm.DB {
+	if queryReq.MonsterCategoryId != "" {
+		query = query.Where(`monsters.monster_category_id = ?`, queryReq.MonsterCategoryId)
+	}
+	if len(queryReq.MonsterTypeId) > 0 {
+		if queryReq.MonsterTypeMatch == constants.MonsterTypeMatchAll {
+			query = query.Where(`(SELECT COUNT(DISTINCT map.monster_type_id) FROM mapping_monster_and_types map
+				WHERE map.monster_id = monsters.id AND map.monster_type_id IN (?)) = ?`, queryReq.MonsterTypeId, len(queryReq.MonsterTypeId))
+		} else {
+			query = query.Where(`EXISTS (SELECT 1 FROM mapping_monster_and_types map
+				WHERE map.monster_id = monsters.id AND map.monster_type_id IN (?))`, queryReq.MonsterTypeId)
+		}
+	}
+
+	// length is written from float32, so it is compared as real to match the value as it was given
+	ranges := []struct {
+		column      string
+		placeholder string
+		value       model.RangeReq
+	}{
+		{column: "monsters.monster_code", placeholder: "?", value: queryReq.MonsterCode},
+		{column: "monsters.hp", placeholder: "?", value: queryReq.HP},
+		{column: "monsters.attack", placeholder: "?", value: queryReq.Attack},
+		{column: "monsters.defends", placeholder: "?", value: queryReq.Defends},
+		{column: "monsters.speed", placeholder: "?", value: queryReq.Speed},
+		{column: "monsters.weight", placeholder: "?", value: queryReq.Weight},
+		{column: "CAST(monsters.length AS real)", placeholder: "CAST(? AS real)", value: queryReq.Length},
+	}
+	for i := 0; i < len(ranges); i++ {
+		if ranges[i].value.Min != nil {
+			query = query.Where(ranges[i].column+" >= "+ranges[i].placeholder, *ranges[i].value.Min)
+		}
+		if ranges[i].value.Max != nil {
+			query = query.Where(ranges[i].column+" <= "+ranges[i].placeholder, *ranges[i].value.Max)
+		}
+	}
+
+	return query
+}
+
+// GetListMonsterSuggestion is repository to get active monster name for autocomplete, name starting with prefix comes first
+// then similar name by trigram so typo is tolerated
+func (rMonster *monsterRepository) GetListMonsterSuggestion(ctx context.Context, prefix string, limit int) (res []model.MonsterSuggestionRes, err error) {
+	args := map[string]interface{}{
+		"prefix":    search.EscapeLike(prefix) + "%",
+		"name":      prefix,
+		"threshold": constants.SearchSimilarityThreshold,
+	}
+
+	// get list monster suggestion
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Select(`id, monster_code, name`).
+		Where(`deleted_at IS NULL`).
+		Where(`lower(name) LIKE lower(@prefix) OR (lower(name) % lower(@name) AND similarity(lower(name), lower(@name)) >= @threshold)`, args).
+		Clauses(clause.OrderBy{Expression: clause.NamedExpr{
+			SQL:  `lower(name) LIKE lower(@prefix) DESC, similarity(lower(name), lower(@name)) DESC, name ASC, id ASC`,
+			Vars: []interface{}{args},
+		}}).
+		Limit(limit).
+		Scan(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // UpdateMonster is repository to update monster
 func (rMonster *monsterRepository) UpdateMonster(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error) {
 	// transaction
@@ -162,6 +383,32 @@ func (rMonster *monsterRepository) UpdateMonster(tx *gorm.DB, ctx context.Contex
 	return nil
 }
 
+// UpdateMonsterVersion is repository to increment monster version, current version must equal expected version if expected version is not zero
+func (rMonster *monsterRepository) UpdateMonsterVersion(tx *gorm.DB, ctx context.Context, reqId string, expectedVersion int) (err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	query := conn.WithContext(ctx).Table(constants.MonsterTable).Model(&model.Monster{}).
+		Where(`id = ?`, reqId)
+	if expectedVersion != 0 {
+		query = query.Where(`version = ?`, expectedVersion)
+	}
+
+	// update monster version, no affected row means monster is not found or version is stale
+	result := query.Update(`version`, gorm.Expr(`version + 1`))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
 // SoftDeleterMonster is repository to soft delete monster
 func (rMonster *monsterRepository) SoftDeleterMonster(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error) {
 	// transaction
@@ -226,24 +473,277 @@ func (rMonster *monsterRepository) DeleteMappingMonsterAndType(tx *gorm.DB, ctx
 	return nil
 }
 
-// GetLastMonsterCode is repository to get last monster code
-func (rMonster *monsterRepository) GetLastMonsterCode() (res uint16, err error) {
-	// get last monster code
-	err = rMonster.dbConn.Table(constants.MonsterTable).
-		Select(`monster_code`).
-		Model(&model.Monster{}).
-		Order(`created_at DESC`).
-		First(&res).Error
+// SyncMonsterCodeSequence is repository to move monster code sequence past explicitly picked monster code
+func (rMonster *monsterRepository) SyncMonsterCodeSequence(tx *gorm.DB, ctx context.Context, monsterCode uint16) (err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// sync monster code sequence, sequence never goes backward
+	err = conn.WithContext(ctx).
+		Exec(fmt.Sprintf(`SELECT setval('%[1]s', GREATEST(?, (SELECT last_value FROM %[1]s)))`, constants.MonsterCodeSequence), monsterCode).Error
 	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return 0, nil
+		return err
+	}
+
+	return nil
+}
+
+// GetMonsterFingerprint is repository to get cheap fingerprint of monster by id, or of all monster if id is empty
+func (rMonster *monsterRepository) GetMonsterFingerprint(ctx context.Context, reqId string) (res model.Fingerprint, err error) {
+	// get fingerprint of monster by id, detail also embeds name of monster category and monster type
+	if reqId != "" {
+		result := rMonster.dbConn.WithContext(ctx).Raw(`
+			SELECT 1 AS total,
+			       monsters.version,
+			       GREATEST(monsters.updated_at, monster_categories.updated_at,
+			                (SELECT MAX(monster_types.updated_at)
+			                 FROM mapping_monster_and_types map
+			                 JOIN monster_types ON monster_types.id = map.monster_type_id
+			                 WHERE map.monster_id = monsters.id)) AS last_modified,
+			       EXISTS(SELECT 1 FROM monster_images WHERE monster_images.monster_id = monsters.id AND monster_images.is_private) AS has_private_image
+			FROM monsters
+			LEFT JOIN monster_categories ON monster_categories.id = monsters.monster_category_id
+			WHERE monsters.id = ? AND monsters.deleted_at IS NULL`, reqId).
+			Scan(&res)
+		if result.Error != nil {
+			return res, result.Error
+		}
+		if result.RowsAffected == 0 {
+			return res, gorm.ErrRecordNotFound
 		}
+
+		return res, nil
+	}
+
+	// get fingerprint of all monster, list also depends on monster category and monster type
+	err = rMonster.dbConn.WithContext(ctx).Raw(`
+		SELECT COUNT(*) FILTER (WHERE monsters.deleted_at IS NULL) AS total,
+		       COALESCE(SUM(monsters.version) FILTER (WHERE monsters.deleted_at IS NULL), 0) AS version,
+		       GREATEST(MAX(monsters.updated_at), MAX(monsters.deleted_at),
+		                (SELECT MAX(updated_at) FROM monster_categories),
+		                (SELECT MAX(updated_at) FROM monster_types)) AS last_modified,
+		       EXISTS(SELECT 1 FROM monster_images WHERE monster_images.is_primary AND monster_images.is_private) AS has_private_image
+		FROM monsters`).
+		Scan(&res).Error
+	if err != nil {
 		return res, err
 	}
 
 	return res, nil
 }
 
+// GetMonsterSnapshot is repository to get current state of monster by id, including soft deleted monster
+func (rMonster *monsterRepository) GetMonsterSnapshot(tx *gorm.DB, ctx context.Context, reqId string) (res model.MonsterSnapshot, err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get monster snapshot
+	result := conn.WithContext(ctx).Raw(`
+		SELECT monsters.id, monsters.version, monsters.monster_code, monsters.name, monsters.monster_category_id,
+		       COALESCE((SELECT array_agg(map.monster_type_id::text ORDER BY map.monster_type_id)
+		                 FROM mapping_monster_and_types map
+		                 WHERE map.monster_id = monsters.id), '{}') AS monster_types,
+		       monsters.description, monsters.length, monsters.weight, monsters.hp, monsters.attack, monsters.defends,
+		       monsters.speed, monsters.is_caught, monsters.image_name, monsters.is_image_missing,
+		       monsters.deleted_at IS NOT NULL AS is_deleted
+		FROM monsters
+		WHERE monsters.id = ?`, reqId).
+		Scan(&res)
+	if result.Error != nil {
+		return res, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return res, gorm.ErrRecordNotFound
+	}
+
+	return res, nil
+}
+
+// GetListDeletedMonster is repository to get list soft deleted monster, latest deleted monster first
+func (rMonster *monsterRepository) GetListDeletedMonster(ctx context.Context, page, perPage int) (res []model.Monster, count int64, err error) {
+	query := rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Where(`monsters.deleted_at IS NOT NULL`).
+		Session(&gorm.Session{})
+
+	// count soft deleted monster
+	err = query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// get list soft deleted monster
+	err = query.Select(`id, monster_code, name, monster_category_id, image_name, version, deleted_at`).
+		Preload("MonsterCategory", func(query *gorm.DB) *gorm.DB {
+			return query.Select(`id, name`)
+		}).
+		Preload("MonsterTypes", func(query *gorm.DB) *gorm.DB {
+			return query.Select(`id, name`)
+		}).
+		Order(`monsters.deleted_at DESC`).
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, count, nil
+}
+
+// GetDeletedMonsterById is repository to get soft deleted monster by id
+func (rMonster *monsterRepository) GetDeletedMonsterById(ctx context.Context, reqId string) (res model.Monster, err error) {
+	// get soft deleted monster by id
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Select(`id, image_name, version, deleted_at`).
+		Where(`id = ? AND deleted_at IS NOT NULL`, reqId).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetListDeletedMonsterId is repository to get id of monster which is soft deleted before given time
+func (rMonster *monsterRepository) GetListDeletedMonsterId(ctx context.Context, deletedBefore time.Time) (res []string, err error) {
+	// get list soft deleted monster id
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Where(`deleted_at IS NOT NULL AND deleted_at < ?`, deletedBefore).
+		Order(`deleted_at ASC`).
+		Pluck(`id`, &res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// RestoreMonster is repository to restore soft deleted monster and increment its version
+func (rMonster *monsterRepository) RestoreMonster(tx *gorm.DB, ctx context.Context, reqId string, isImageMissing bool) (err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// restore monster, no affected row means monster is not found or not deleted
+	result := conn.WithContext(ctx).Table(constants.MonsterTable).Model(&model.Monster{}).
+		Unscoped().
+		Where(`id = ? AND deleted_at IS NOT NULL`, reqId).
+		Updates(map[string]interface{}{
+			"deleted_at":       nil,
+			"is_image_missing": isImageMissing,
+			"version":          gorm.Expr(`version + 1`),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// HardDeleteMonster is repository to permanently delete soft deleted monster
+func (rMonster *monsterRepository) HardDeleteMonster(tx *gorm.DB, ctx context.Context, reqId string) (err error) {
+	// transaction
+	conn := rMonster.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// hard delete monster, no affected row means monster is not found or not deleted
+	result := conn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Where(`id = ? AND deleted_at IS NOT NULL`, reqId).
+		Delete(&model.Monster{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// GetListMonsterByNameOrCode is repository to get monster which name case-insensitively or monster code is in given list, including soft deleted monster
+func (rMonster *monsterRepository) GetListMonsterByNameOrCode(ctx context.Context, names []string, monsterCodes []uint16) (res []model.Monster, err error) {
+	if len(names) == 0 && len(monsterCodes) == 0 {
+		return nil, nil
+	}
+	if len(names) == 0 {
+		names = []string{""}
+	}
+	if len(monsterCodes) == 0 {
+		monsterCodes = []uint16{0}
+	}
+
+	// get list monster by name or monster code
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Select(`id, monster_code, name, deleted_at`).
+		Where(`lower(name) IN (?) OR monster_code IN (?)`, names, monsterCodes).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetListMonsterByExternalIdOrName is repository to get list monster with its monster type by external id or lowercase name, including soft deleted monster
+func (rMonster *monsterRepository) GetListMonsterByExternalIdOrName(ctx context.Context, externalIds []string, names []string) (res []model.Monster, err error) {
+	if len(externalIds) == 0 && len(names) == 0 {
+		return nil, nil
+	}
+	if len(externalIds) == 0 {
+		externalIds = []string{""}
+	}
+	if len(names) == 0 {
+		names = []string{""}
+	}
+
+	// get list monster by external id or name
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Select(`id, monster_code, name, monster_category_id, description, length, weight, hp, attack, defends, speed, is_caught, version, external_id, deleted_at`).
+		Preload("MonsterTypes", func(query *gorm.DB) *gorm.DB {
+			return query.Select(`id, name`)
+		}).
+		Where(`external_id IN (?) OR lower(name) IN (?)`, externalIds, names).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllMonsterImage is repository to get image name of all monster, including soft deleted monster
+func (rMonster *monsterRepository) GetAllMonsterImage(ctx context.Context) (res []model.Monster, err error) {
+	// get all monster image
+	err = rMonster.dbConn.WithContext(ctx).Table(constants.MonsterTable).
+		Unscoped().
+		Select(`id, monster_code, name, image_name, is_image_missing, deleted_at`).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
 // Transaction is repository to create transactional database
 func (rMonster *monsterRepository) Transaction() (tx *gorm.DB, resCode int, err error) {
 	return rMonster.dbConn, http.StatusInternalServerError, nil
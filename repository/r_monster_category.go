@@ -10,6 +10,9 @@ import (
 // MCategoryRepositoryInterface is
 type MCategoryRepositoryInterface interface {
 	GetAllMonsterCategory(ctx context.Context, selectParams []string) (res []model.MonsterCategory, err error)
+	GetMonsterCategoryFingerprint(ctx context.Context) (res model.Fingerprint, err error)
+	CreateMonsterCategory(tx *gorm.DB, ctx context.Context, req model.MonsterCategory) (monsterCategoryId string, err error)
+	UpdateMonsterCategory(tx *gorm.DB, ctx context.Context, reqId string, value map[string]interface{}) (err error)
 }
 
 type mCategoryRepository struct {
@@ -39,3 +42,50 @@ func (rMCategory *mCategoryRepository) GetAllMonsterCategory(ctx context.Context
 
 	return res, nil
 }
+
+// GetMonsterCategoryFingerprint is repository to get cheap fingerprint of all monster category
+func (rMCategory *mCategoryRepository) GetMonsterCategoryFingerprint(ctx context.Context) (res model.Fingerprint, err error) {
+	// get fingerprint of all monster category
+	err = rMCategory.dbConn.WithContext(ctx).Table(constants.MonsterCategoryTable).
+		Select(`COUNT(*) FILTER (WHERE deleted_at IS NULL) AS total, GREATEST(MAX(updated_at), MAX(deleted_at)) AS last_modified`).
+		Scan(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// CreateMonsterCategory is repository to create monster category
+func (rMCategory *mCategoryRepository) CreateMonsterCategory(tx *gorm.DB, ctx context.Context, req model.MonsterCategory) (monsterCategoryId string, err error) {
+	// transaction
+	conn := rMCategory.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// create monster category
+	err = conn.WithContext(ctx).Table(constants.MonsterCategoryTable).Create(&req).Error
+	if err != nil {
+		return "", err
+	}
+
+	return req.ID, nil
+}
+
+// UpdateMonsterCategory is repository to update monster category by id
+func (rMCategory *mCategoryRepository) UpdateMonsterCategory(tx *gorm.DB, ctx context.Context, reqId string, value map[string]interface{}) (err error) {
+	// transaction
+	conn := rMCategory.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// update monster category
+	err = conn.WithContext(ctx).Table(constants.MonsterCategoryTable).Model(&model.MonsterCategory{}).Where(`id = ?`, reqId).Updates(value).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
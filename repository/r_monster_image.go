@@ -0,0 +1,190 @@
+package repository
+
+import (
+	"context"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/model"
+	"gorm.io/gorm"
+)
+
+// MonsterImageRepositoryInterface is
+type MonsterImageRepositoryInterface interface {
+	CreateMonsterImage(tx *gorm.DB, ctx context.Context, req model.MonsterImage) (monsterImageId string, err error)
+	GetMonsterImageById(ctx context.Context, monsterId, reqId string) (res model.MonsterImage, err error)
+	GetMonsterImageByName(ctx context.Context, imageName string) (res model.MonsterImage, err error)
+	GetListMonsterImage(tx *gorm.DB, ctx context.Context, monsterId string) (res []model.MonsterImage, err error)
+	GetAllMonsterImage(ctx context.Context) (res []model.MonsterImage, err error)
+	GetListMonsterImageWithoutPlaceholder(ctx context.Context, lastId string, limit int) (res []model.MonsterImage, err error)
+	UpdateMonsterImage(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error)
+	DeleteMonsterImage(tx *gorm.DB, ctx context.Context, reqId string) (err error)
+	DeleteMonsterImageByMonsterId(tx *gorm.DB, ctx context.Context, monsterId string) (err error)
+}
+
+type monsterImageRepository struct {
+	dbConn *gorm.DB
+}
+
+func NewMonsterImageRepository(db *gorm.DB) MonsterImageRepositoryInterface {
+	return &monsterImageRepository{
+		dbConn: db,
+	}
+}
+
+// CreateMonsterImage is repository to create monster image
+func (rMonsterImage *monsterImageRepository) CreateMonsterImage(tx *gorm.DB, ctx context.Context, req model.MonsterImage) (monsterImageId string, err error) {
+	// transaction
+	conn := rMonsterImage.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// create monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).Create(&req).Error
+	if err != nil {
+		return "", err
+	}
+
+	return req.ID, nil
+}
+
+// GetMonsterImageById is repository to get monster image by id and monster id
+func (rMonsterImage *monsterImageRepository) GetMonsterImageById(ctx context.Context, monsterId, reqId string) (res model.MonsterImage, err error) {
+	// get monster image by id
+	err = rMonsterImage.dbConn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`id = ? AND monster_id = ?`, reqId, monsterId).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetMonsterImageByName is repository to get monster image by image name
+func (rMonsterImage *monsterImageRepository) GetMonsterImageByName(ctx context.Context, imageName string) (res model.MonsterImage, err error) {
+	// get monster image by image name
+	err = rMonsterImage.dbConn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`image_name = ?`, imageName).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetListMonsterImage is repository to get list monster image based on monster id ordered by sort order
+func (rMonsterImage *monsterImageRepository) GetListMonsterImage(tx *gorm.DB, ctx context.Context, monsterId string) (res []model.MonsterImage, err error) {
+	// transaction
+	conn := rMonsterImage.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// get list monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`monster_id = ?`, monsterId).
+		Order(`sort_order ASC, created_at ASC`).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetAllMonsterImage is repository to get image name of all monster image with its monster id
+func (rMonsterImage *monsterImageRepository) GetAllMonsterImage(ctx context.Context) (res []model.MonsterImage, err error) {
+	// get all monster image
+	err = rMonsterImage.dbConn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Select(`id, monster_id, image_name`).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetListMonsterImageWithoutPlaceholder is repository to get batch of monster image without BlurHash, ordered by id after last id
+func (rMonsterImage *monsterImageRepository) GetListMonsterImageWithoutPlaceholder(ctx context.Context, lastId string, limit int) (res []model.MonsterImage, err error) {
+	query := rMonsterImage.dbConn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`blur_hash = ''`)
+	if lastId != "" {
+		query = query.Where(`id > ?`, lastId)
+	}
+
+	// get list monster image without placeholder
+	err = query.Order(`id ASC`).Limit(limit).Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// UpdateMonsterImage is repository to update monster image
+func (rMonsterImage *monsterImageRepository) UpdateMonsterImage(tx *gorm.DB, ctx context.Context, req map[string]interface{}) (err error) {
+	// transaction
+	conn := rMonsterImage.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	query := conn.WithContext(ctx).Table(constants.MonsterImageTable).Model(&model.MonsterImage{})
+
+	// query params
+	if req["whereParams"] != nil {
+		if req["whereParams"].(map[string]interface{})["default"] != nil {
+			for index, value := range req["whereParams"].(map[string]interface{})["default"].(map[string]interface{}) {
+				query = query.Where(index, value)
+			}
+		}
+	}
+
+	// update monster image
+	err = query.Updates(req["value"]).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMonsterImage is repository to delete monster image by id
+func (rMonsterImage *monsterImageRepository) DeleteMonsterImage(tx *gorm.DB, ctx context.Context, reqId string) (err error) {
+	// transaction
+	conn := rMonsterImage.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`id = ?`, reqId).
+		Delete(&model.MonsterImage{}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteMonsterImageByMonsterId is repository to delete all monster image of monster
+func (rMonsterImage *monsterImageRepository) DeleteMonsterImageByMonsterId(tx *gorm.DB, ctx context.Context, monsterId string) (err error) {
+	// transaction
+	conn := rMonsterImage.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete monster image
+	err = conn.WithContext(ctx).Table(constants.MonsterImageTable).
+		Where(`monster_id = ?`, monsterId).
+		Delete(&model.MonsterImage{}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,116 @@
+package repository
+
+import (
+	"context"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/model"
+	"gorm.io/gorm"
+)
+
+// MonsterRevisionRepositoryInterface is
+type MonsterRevisionRepositoryInterface interface {
+	CreateMonsterRevision(tx *gorm.DB, ctx context.Context, req model.MonsterRevision) (err error)
+	GetListMonsterRevision(ctx context.Context, monsterId string, page, perPage int) (res []model.MonsterRevision, count int64, err error)
+	GetMonsterRevisionById(ctx context.Context, monsterId, reqId string) (res model.MonsterRevision, err error)
+	GetLatestMonsterRevision(ctx context.Context, monsterId, excludeAction string) (res model.MonsterRevision, err error)
+	DeleteMonsterRevision(tx *gorm.DB, ctx context.Context, monsterId string) (err error)
+}
+
+type monsterRevisionRepository struct {
+	dbConn *gorm.DB
+}
+
+func NewMonsterRevisionRepository(db *gorm.DB) MonsterRevisionRepositoryInterface {
+	return &monsterRevisionRepository{
+		dbConn: db,
+	}
+}
+
+// CreateMonsterRevision is repository to create monster revision
+func (rMRevision *monsterRevisionRepository) CreateMonsterRevision(tx *gorm.DB, ctx context.Context, req model.MonsterRevision) (err error) {
+	// transaction
+	conn := rMRevision.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// create monster revision
+	err = conn.WithContext(ctx).Table(constants.MonsterRevisionTable).Create(&req).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// GetListMonsterRevision is repository to get list monster revision by monster id, newest revision first
+func (rMRevision *monsterRevisionRepository) GetListMonsterRevision(ctx context.Context, monsterId string, page, perPage int) (res []model.MonsterRevision, count int64, err error) {
+	query := rMRevision.dbConn.WithContext(ctx).Table(constants.MonsterRevisionTable).
+		Where(`monster_revisions.monster_id = ?`, monsterId).
+		Session(&gorm.Session{})
+
+	// count monster revision
+	err = query.Count(&count).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// get list monster revision
+	err = query.Select(`monster_revisions.*, users.name AS actor_name`).
+		Joins(`LEFT JOIN users ON users.id = monster_revisions.actor_id`).
+		Order(`monster_revisions.created_at DESC, monster_revisions.version DESC`).
+		Offset((page - 1) * perPage).
+		Limit(perPage).
+		Find(&res).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return res, count, nil
+}
+
+// GetMonsterRevisionById is repository to get monster revision by id
+func (rMRevision *monsterRevisionRepository) GetMonsterRevisionById(ctx context.Context, monsterId, reqId string) (res model.MonsterRevision, err error) {
+	// get monster revision by id
+	err = rMRevision.dbConn.WithContext(ctx).Table(constants.MonsterRevisionTable).
+		Where(`id = ? AND monster_id = ?`, reqId, monsterId).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// GetLatestMonsterRevision is repository to get latest monster revision by monster id, revision with excluded action is skipped
+func (rMRevision *monsterRevisionRepository) GetLatestMonsterRevision(ctx context.Context, monsterId, excludeAction string) (res model.MonsterRevision, err error) {
+	// get latest monster revision
+	err = rMRevision.dbConn.WithContext(ctx).Table(constants.MonsterRevisionTable).
+		Where(`monster_id = ? AND action <> ?`, monsterId, excludeAction).
+		Order(`created_at DESC, version DESC`).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// DeleteMonsterRevision is repository to delete all monster revision of monster
+func (rMRevision *monsterRevisionRepository) DeleteMonsterRevision(tx *gorm.DB, ctx context.Context, monsterId string) (err error) {
+	// transaction
+	conn := rMRevision.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// delete monster revision
+	err = conn.WithContext(ctx).Table(constants.MonsterRevisionTable).
+		Where(`monster_id = ?`, monsterId).
+		Delete(&model.MonsterRevision{}).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
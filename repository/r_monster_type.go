@@ -10,6 +10,9 @@ import (
 // MTypeRepositoryInterface is
 type MTypeRepositoryInterface interface {
 	GetAllMonsterType(ctx context.Context, selectParams []string) (res []model.MonsterType, err error)
+	GetMonsterTypeFingerprint(ctx context.Context) (res model.Fingerprint, err error)
+	CreateMonsterType(tx *gorm.DB, ctx context.Context, req model.MonsterType) (monsterTypeId string, err error)
+	UpdateMonsterType(tx *gorm.DB, ctx context.Context, reqId string, value map[string]interface{}) (err error)
 }
 
 type mTypeRepository struct {
@@ -39,3 +42,50 @@ func (rMType *mTypeRepository) GetAllMonsterType(ctx context.Context, selectPara
 
 	return res, nil
 }
+
+// GetMonsterTypeFingerprint is repository to get cheap fingerprint of all monster type
+func (rMType *mTypeRepository) GetMonsterTypeFingerprint(ctx context.Context) (res model.Fingerprint, err error) {
+	// get fingerprint of all monster type
+	err = rMType.dbConn.WithContext(ctx).Table(constants.MonsterTypeTable).
+		Select(`COUNT(*) FILTER (WHERE deleted_at IS NULL) AS total, GREATEST(MAX(updated_at), MAX(deleted_at)) AS last_modified`).
+		Scan(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// CreateMonsterType is repository to create monster type
+func (rMType *mTypeRepository) CreateMonsterType(tx *gorm.DB, ctx context.Context, req model.MonsterType) (monsterTypeId string, err error) {
+	// transaction
+	conn := rMType.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// create monster type
+	err = conn.WithContext(ctx).Table(constants.MonsterTypeTable).Create(&req).Error
+	if err != nil {
+		return "", err
+	}
+
+	return req.ID, nil
+}
+
+// UpdateMonsterType is repository to update monster type by id
+func (rMType *mTypeRepository) UpdateMonsterType(tx *gorm.DB, ctx context.Context, reqId string, value map[string]interface{}) (err error) {
+	// transaction
+	conn := rMType.dbConn
+	if tx != nil {
+		conn = tx
+	}
+
+	// update monster type
+	err = conn.WithContext(ctx).Table(constants.MonsterTypeTable).Model(&model.MonsterType{}).Where(`id = ?`, reqId).Updates(value).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
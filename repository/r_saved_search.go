@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/model"
+	"gorm.io/gorm"
+)
+
+// SavedSearchRepositoryInterface is
+type SavedSearchRepositoryInterface interface {
+	CreateSavedSearch(ctx context.Context, req model.SavedSearch) (savedSearchId string, err error)
+	GetListSavedSearch(ctx context.Context, userId string) (res []model.SavedSearch, err error)
+	GetSavedSearchById(ctx context.Context, userId, reqId string) (res model.SavedSearch, err error)
+	UpdateSavedSearch(ctx context.Context, userId, reqId string, value map[string]interface{}) (err error)
+	DeleteSavedSearch(ctx context.Context, userId, reqId string) (err error)
+}
+
+type savedSearchRepository struct {
+	dbConn *gorm.DB
+}
+
+func NewSavedSearchRepository(db *gorm.DB) SavedSearchRepositoryInterface {
+	return &savedSearchRepository{
+		dbConn: db,
+	}
+}
+
+// CreateSavedSearch is repository to create saved search
+func (rSavedSearch *savedSearchRepository) CreateSavedSearch(ctx context.Context, req model.SavedSearch) (savedSearchId string, err error) {
+	// create saved search
+	err = rSavedSearch.dbConn.WithContext(ctx).Table(constants.SavedSearchTable).Create(&req).Error
+	if err != nil {
+		return "", err
+	}
+
+	return req.ID, nil
+}
+
+// GetListSavedSearch is repository to get list saved search of user ordered by name
+func (rSavedSearch *savedSearchRepository) GetListSavedSearch(ctx context.Context, userId string) (res []model.SavedSearch, err error) {
+	// get list saved search
+	err = rSavedSearch.dbConn.WithContext(ctx).Table(constants.SavedSearchTable).
+		Where(`user_id = ?`, userId).
+		Order(`lower(name) ASC, id ASC`).
+		Find(&res).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// GetSavedSearchById is repository to get saved search by id, saved search of other user is not found
+func (rSavedSearch *savedSearchRepository) GetSavedSearchById(ctx context.Context, userId, reqId string) (res model.SavedSearch, err error) {
+	// get saved search by id
+	err = rSavedSearch.dbConn.WithContext(ctx).Table(constants.SavedSearchTable).
+		Where(`id = ? AND user_id = ?`, reqId, userId).
+		First(&res).Error
+	if err != nil {
+		return res, err
+	}
+
+	return res, nil
+}
+
+// UpdateSavedSearch is repository to update saved search by id, saved search of other user is not found
+func (rSavedSearch *savedSearchRepository) UpdateSavedSearch(ctx context.Context, userId, reqId string, value map[string]interface{}) (err error) {
+	// update saved search
+	result := rSavedSearch.dbConn.WithContext(ctx).Table(constants.SavedSearchTable).
+		Where(`id = ? AND user_id = ?`, reqId, userId).
+		Updates(value)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
+
+// DeleteSavedSearch is repository to delete saved search by id, saved search of other user is not found
+func (rSavedSearch *savedSearchRepository) DeleteSavedSearch(ctx context.Context, userId, reqId string) (err error) {
+	// delete saved search
+	result := rSavedSearch.dbConn.WithContext(ctx).Table(constants.SavedSearchTable).
+		Where(`id = ? AND user_id = ?`, reqId, userId).
+		Delete(&model.SavedSearch{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"github.com/frianlh/pokedex-api/configs"
 	"github.com/frianlh/pokedex-api/delivery"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/frianlh/pokedex-api/libs/uploader"
 	"github.com/frianlh/pokedex-api/repository"
 	"github.com/frianlh/pokedex-api/routers/middleware"
 	"github.com/frianlh/pokedex-api/usecase"
@@ -16,18 +18,34 @@ func V1Route(route fiber.Router, config *configs.Config) {
 	rMCategory := repository.NewMonsterCategory(config.PostgresConfig.DbConn)
 	rMType := repository.NewMTypeRepository(config.PostgresConfig.DbConn)
 	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+	rSavedSearch := repository.NewSavedSearchRepository(config.PostgresConfig.DbConn)
 
 	// use case
 	uAuth := usecase.NewAuthUseCase(config.TimeoutCtx, config.JWTKey, rUser)
 	uMCategory := usecase.NewMCategoryUseCase(config.TimeoutCtx, rMCategory)
 	uMType := usecase.NewMTypeUseCase(config.TimeoutCtx, rMType)
-	uMonster := usecase.NewMonsterUseCase(config.TimeoutCtx, config.BaseURL, rMonster)
+	imageURL := uploader.ImageURL{
+		BaseURL:    config.BaseURL,
+		SigningKey: config.ImageConfig.SigningKey,
+		TTL:        config.ImageConfig.URLTTL,
+	}
+	uMonster := usecase.NewMonsterUseCase(config.TimeoutCtx, imageURL, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterImage := usecase.NewMonsterImageUseCase(config.TimeoutCtx, imageURL, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterTrash := usecase.NewMonsterTrashUseCase(config.TimeoutCtx, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterImport := usecase.NewMonsterImportUseCase(config.TimeoutCtx, uMonster, rMonster, rMCategory, rMType)
+	uSavedSearch := usecase.NewSavedSearchUseCase(config.TimeoutCtx, uMonster, rSavedSearch, rMCategory, rMType)
 
 	// delivery
 	hAuth := delivery.NewAuthHandler(uAuth)
 	hMCategory := delivery.NewMCategoryHandler(uMCategory)
 	hMType := delivery.NewMTypeHandler(uMType)
 	hMonster := delivery.NewMonsterHandler(uMonster)
+	hMonsterImage := delivery.NewMonsterImageHandler(config.ImageConfig.CacheMaxAge, uMonsterImage)
+	hMonsterTrash := delivery.NewMonsterTrashHandler(uMonsterTrash)
+	hMonsterImport := delivery.NewMonsterImportHandler(uMonsterImport)
+	hSavedSearch := delivery.NewSavedSearchHandler(uSavedSearch)
 
 	// route group
 	// auth group
@@ -39,24 +57,50 @@ func V1Route(route fiber.Router, config *configs.Config) {
 	// monster category group
 	mCategory := route.Group("/monster-category")
 	{
-		mCategory.Get("", hMCategory.GetAllMonsterCategory)
+		mCategory.Get("", response.ConditionalGet(config.CacheConfig.MonsterCategory, hMCategory.GetAllMonsterCategoryValidator), hMCategory.GetAllMonsterCategory)
 	}
 
 	// monster type group
 	mType := route.Group("/monster-type")
 	{
-		mType.Get("", hMType.GetAllMonsterType)
+		mType.Get("", response.ConditionalGet(config.CacheConfig.MonsterType, hMType.GetAllMonsterTypeValidator), hMType.GetAllMonsterType)
 	}
 
 	// monster group
 	monster := route.Group("/monster")
 	{
 		monster.Post("", middleware.AuthMiddleware(config.JWTKey, "write_monster"), hMonster.CreateMonster)
-		monster.Get("/:id", hMonster.GetMonsterById)
-		monster.Get("", hMonster.GetListMonster)
+		monster.Post("/import", middleware.AuthMiddleware(config.JWTKey, "write_monster"), hMonsterImport.ImportMonster)
+		monster.Post("/bulk", middleware.AuthMiddleware(config.JWTKey, "write_monster"), middleware.AuthMiddleware(config.JWTKey, "update_monster"), middleware.AuthMiddleware(config.JWTKey, "delete_monster"), hMonster.BulkMonster)
+		monster.Get("/export", hMonster.ExportMonster)
+		monster.Get("/suggest", hMonster.SuggestMonster)
+		monster.Get("/trash", middleware.AuthMiddleware(config.JWTKey, "delete_monster"), hMonsterTrash.GetListDeletedMonster)
+		monster.Post("/trash/:id/restore", middleware.AuthMiddleware(config.JWTKey, "delete_monster"), hMonsterTrash.RestoreMonster)
+		monster.Delete("/trash/:id", middleware.AuthMiddleware(config.JWTKey, "delete_monster"), hMonsterTrash.PurgeMonster)
+		monster.Get("/:id", response.ConditionalGet(config.CacheConfig.MonsterDetail, hMonster.GetMonsterByIdValidator), hMonster.GetMonsterById)
+		monster.Get("", response.ConditionalGet(config.CacheConfig.MonsterList, hMonster.GetListMonsterValidator), hMonster.GetListMonster)
 		monster.Put("/:id", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonster.UpdateMonster)
+		monster.Patch("/:id", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonster.PatchMonster)
 		monster.Put("captured/:id", hMonster.UpdateMonsterCaptured)
 		monster.Delete("/:id", middleware.AuthMiddleware(config.JWTKey, "delete_monster"), hMonster.DeleteMonster)
-		monster.Static("/images", "./images")
+		monster.Get("/:id/similar", hMonster.SimilarMonster)
+		monster.Get("/:id/history", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonster.GetMonsterHistory)
+		monster.Post("/:id/revert/:revisionId", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonster.RevertMonster)
+		monster.Post("/:id/images", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonsterImage.CreateMonsterImage)
+		monster.Put("/:id/images/order", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonsterImage.ReorderMonsterImage)
+		monster.Put("/:id/images/:imageId/primary", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonsterImage.SetPrimaryMonsterImage)
+		monster.Put("/:id/images/:imageId/visibility", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonsterImage.UpdateMonsterImageVisibility)
+		monster.Delete("/:id/images/:imageId", middleware.AuthMiddleware(config.JWTKey, "update_monster"), hMonsterImage.DeleteMonsterImage)
+		monster.Get("/images/:imageName", hMonsterImage.ServeImage)
+	}
+	// me group, saved search belongs to authenticated user
+	me := route.Group("/me")
+	{
+		me.Get("/saved-searches", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.GetListSavedSearch)
+		me.Post("/saved-searches", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.CreateSavedSearch)
+		me.Get("/saved-searches/:id", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.GetSavedSearchById)
+		me.Put("/saved-searches/:id", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.UpdateSavedSearch)
+		me.Delete("/saved-searches/:id", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.DeleteSavedSearch)
+		me.Get("/saved-searches/:id/results", middleware.AuthMiddleware(config.JWTKey, "read_monster"), hSavedSearch.GetSavedSearchResult)
 	}
 }
@@ -2,6 +2,7 @@ package middleware
 
 import (
 	"encoding/json"
+	"github.com/frianlh/pokedex-api/libs/constants"
 	"github.com/frianlh/pokedex-api/libs/encrypt"
 	"github.com/frianlh/pokedex-api/libs/response"
 	"github.com/frianlh/pokedex-api/model"
@@ -38,6 +39,9 @@ func AuthMiddleware(jwtKey, menuPermission string) fiber.Handler {
 			return response.ErrorRes(ctx, http.StatusUnauthorized, "unauthorized", "unauthorized")
 		}
 
+		// set authenticated user as actor of the request
+		ctx.Locals(constants.ActorIdKey, claims["id"])
+
 		return ctx.Next()
 	}
 }
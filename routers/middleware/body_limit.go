@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/response"
+	"github.com/gofiber/fiber/v2"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// BodyLimit is function for request body limit middleware, request body over the server limit is streamed so it is
+// checked here before it is read, route in routeLimits ("METHOD /path") is given its own limit instead of the default one
+func BodyLimit(limit int, routeLimits map[string]int) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		maxSize := limit
+		route := fmt.Sprintf("%s %s", ctx.Method(), strings.ToLower(strings.TrimSuffix(ctx.Path(), "/")))
+		if routeLimit, ok := routeLimits[route]; ok {
+			maxSize = routeLimit
+		}
+
+		// body with content length
+		contentLength := ctx.Request().Header.ContentLength()
+		if contentLength > maxSize {
+			return response.ErrorRes(ctx, http.StatusRequestEntityTooLarge, "request body too large", fmt.Sprintf("request body must not exceed %d bytes", maxSize))
+		}
+
+		// chunked body has no content length, so it is read up to the limit
+		if contentLength < 0 && ctx.Request().IsBodyStream() {
+			body, err := io.ReadAll(io.LimitReader(ctx.Request().BodyStream(), int64(maxSize)+1))
+			if err != nil {
+				return response.ErrorRes(ctx, http.StatusBadRequest, "failed to read request body", err.Error())
+			}
+			if len(body) > maxSize {
+				return response.ErrorRes(ctx, http.StatusRequestEntityTooLarge, "request body too large", fmt.Sprintf("request body must not exceed %d bytes", maxSize))
+			}
+			ctx.Request().SetBodyRaw(body)
+		}
+
+		return ctx.Next()
+	}
+}
@@ -2,7 +2,9 @@ package routers
 
 import (
 	"github.com/frianlh/pokedex-api/configs"
+	"github.com/frianlh/pokedex-api/libs/constants"
 	"github.com/frianlh/pokedex-api/routers/api"
+	"github.com/frianlh/pokedex-api/routers/middleware"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
@@ -10,9 +12,15 @@ import (
 
 // SetupRoute is
 func SetupRoute(config *configs.Config) *fiber.App {
-	f := fiber.New()
+	// body over the default limit is streamed instead of rejected, so only import route accepts import file with its image archive
+	f := fiber.New(fiber.Config{
+		StreamRequestBody: true,
+	})
 	f.Use(cors.New(configs.CorsConfig()))
 	f.Use(logger.New(configs.LoggerConfig()))
+	f.Use(middleware.BodyLimit(fiber.DefaultBodyLimit, map[string]int{
+		"POST /api/v1/monster/import": constants.ImportMaxFileSize + constants.ImportMaxArchiveSize,
+	}))
 
 	// api route
 	apiRoute := f.Group("/api")
@@ -0,0 +1,70 @@
+package schedulers
+
+import (
+	"context"
+	"github.com/frianlh/pokedex-api/configs"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/frianlh/pokedex-api/usecase"
+	"log"
+	"time"
+)
+
+// SetupScheduler is function to start background task based on config
+func SetupScheduler(ctx context.Context, config *configs.Config) {
+	// repository
+	rMonster := repository.NewMonsterRepository(config.PostgresConfig.DbConn)
+	rMonsterImage := repository.NewMonsterImageRepository(config.PostgresConfig.DbConn)
+	rMonsterRevision := repository.NewMonsterRevisionRepository(config.PostgresConfig.DbConn)
+
+	// use case
+	uImageGC := usecase.NewImageGCUseCase(config.TimeoutCtx, rMonster, rMonsterImage, rMonsterRevision)
+	uMonsterTrash := usecase.NewMonsterTrashUseCase(config.TimeoutCtx, rMonster, rMonsterImage, rMonsterRevision)
+
+	// image garbage collector
+	if config.ImageGCConfig.Interval > 0 {
+		req := model.ImageGCReq{
+			GracePeriod: config.ImageGCConfig.GracePeriod,
+			DryRun:      config.ImageGCConfig.DryRun,
+		}
+		go every(ctx, "image garbage collector", config.ImageGCConfig.Interval, func(ctx context.Context) error {
+			res, _, resMessage, err := uImageGC.RunImageGC(ctx, req)
+			if err != nil {
+				return err
+			}
+			log.Printf("%s: %d orphaned image, %d missing image", resMessage, len(res.OrphanedImages), len(res.MissingImages))
+			return nil
+		})
+	}
+
+	// trash retention
+	if config.TrashConfig.PurgeInterval > 0 {
+		go every(ctx, "trash purge", config.TrashConfig.PurgeInterval, func(ctx context.Context) error {
+			res, _, resMessage, err := uMonsterTrash.PurgeExpiredMonster(ctx, config.TrashConfig.RetentionDays)
+			if err != nil {
+				return err
+			}
+			log.Printf("%s: %d purged, %d failed", resMessage, len(res.Purged), len(res.Failed))
+			return nil
+		})
+	}
+}
+
+// every is function to run task on every interval until context is done
+func every(ctx context.Context, name string, interval time.Duration, task func(ctx context.Context) error) {
+	log.Printf("%s scheduled every %s", name, interval)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := task(ctx)
+			if err != nil {
+				log.Printf("%s failed: %s", name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,444 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/backup"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupUseCaseInterface is
+type BackupUseCaseInterface interface {
+	Backup(ctx context.Context, writer io.Writer) (res model.BackupRes, resCode int, resMessage string, err error)
+	Restore(ctx context.Context, reader *backup.Reader, strategy string, dryRun bool) (res model.RestoreRes, resCode int, resMessage string, err error)
+}
+
+type backupUseCase struct {
+	ctxTimeout  time.Duration
+	backupRepo  repository.BackupRepositoryInterface
+	monsterRepo repository.MonsterRepositoryInterface
+}
+
+func NewBackupUseCase(ctxTimeout time.Duration, backupRepo repository.BackupRepositoryInterface, monsterRepo repository.MonsterRepositoryInterface) BackupUseCaseInterface {
+	return &backupUseCase{
+		ctxTimeout:  ctxTimeout,
+		backupRepo:  backupRepo,
+		monsterRepo: monsterRepo,
+	}
+}
+
+// backupData is every monster data of backup archive
+type backupData struct {
+	monsterCategories      []model.BackupMonsterCategory
+	monsterTypes           []model.BackupMonsterType
+	monsters               []model.BackupMonster
+	mappingMonsterAndTypes []model.BackupMappingMonsterAndType
+	monsterImages          []model.BackupMonsterImage
+	monsterRevisions       []model.BackupMonsterRevision
+}
+
+// Backup is use case to write archive of monster category, monster type, monster with its mapping, gallery, revision and image file
+// data is read in one read-only transaction so archive is consistent, image file which is missing on disk is reported and skipped
+func (uBackup *backupUseCase) Backup(ctx context.Context, writer io.Writer) (res model.BackupRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uBackup.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed backup"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find database schema version
+	schemaVersion, dirty, err := uBackup.backupRepo.GetSchemaVersion(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get database schema version", err
+	}
+	if dirty {
+		return res, http.StatusConflict, "database schema is dirty", fmt.Errorf("migration of schema version %d is not completed", schemaVersion)
+	}
+	res.SchemaVersion = schemaVersion
+
+	// create read-only database transaction
+	trx, resCode, err := uBackup.backupRepo.Transaction()
+	if err != nil {
+		return res, resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin(&sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	defer tx.Rollback()
+
+	// find all monster data
+	var data backupData
+	data.monsterCategories, err = uBackup.backupRepo.GetAllBackupMonsterCategory(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster category", err
+	}
+	data.monsterTypes, err = uBackup.backupRepo.GetAllBackupMonsterType(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster type", err
+	}
+	data.monsters, err = uBackup.backupRepo.GetAllBackupMonster(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster", err
+	}
+	data.mappingMonsterAndTypes, err = uBackup.backupRepo.GetAllBackupMappingMonsterAndType(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all mapping monster and type", err
+	}
+	data.monsterImages, err = uBackup.backupRepo.GetAllBackupMonsterImage(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster image", err
+	}
+	data.monsterRevisions, err = uBackup.backupRepo.GetAllBackupMonsterRevision(tx, ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster revision", err
+	}
+
+	// write data
+	archiveWriter := backup.NewWriter(writer, schemaVersion, time.Now())
+	dataFiles := []struct {
+		name string
+		data interface{}
+	}{
+		{name: constants.BackupMonsterCategoryFile, data: data.monsterCategories},
+		{name: constants.BackupMonsterTypeFile, data: data.monsterTypes},
+		{name: constants.BackupMonsterFile, data: data.monsters},
+		{name: constants.BackupMappingMonsterAndTypeFile, data: data.mappingMonsterAndTypes},
+		{name: constants.BackupMonsterImageFile, data: data.monsterImages},
+		{name: constants.BackupMonsterRevisionFile, data: data.monsterRevisions},
+	}
+	for i := 0; i < len(dataFiles); i++ {
+		err = archiveWriter.WriteJSON(dataFiles[i].name, dataFiles[i].data)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to write backup archive", err
+		}
+	}
+
+	// write image file
+	res.MissingImageFiles = []string{}
+	imageNames := data.imageNames()
+	for i := 0; i < len(imageNames); i++ {
+		file, err := uploader.OpenImage(imageNames[i])
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				res.MissingImageFiles = append(res.MissingImageFiles, imageNames[i])
+				continue
+			}
+			return res, http.StatusInternalServerError, "failed to open image", err
+		}
+		err = archiveWriter.Write(constants.BackupImageDirectory+imageNames[i], file)
+		file.Close()
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to write backup archive", err
+		}
+		res.ImageFile++
+	}
+	_, err = archiveWriter.Close()
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to write backup archive", err
+	}
+
+	// mapping response data
+	res.MonsterCategory = len(data.monsterCategories)
+	res.MonsterType = len(data.monsterTypes)
+	res.Monster = len(data.monsters)
+	res.MappingMonsterAndType = len(data.mappingMonsterAndTypes)
+	res.MonsterImage = len(data.monsterImages)
+	res.MonsterRevision = len(data.monsterRevisions)
+
+	return res, http.StatusOK, "backup successfully", nil
+}
+
+// Restore is use case to restore archive of backup, archive is verified before anything is written
+// replace strategy deletes every monster data first, merge strategy upserts data of archive by id and keeps other monster
+// mapping and gallery of restored monster are replaced in both strategy, so restoring the same archive again changes nothing
+// image file is written before database transaction, image file left by failed restore is collected by image gc
+// dry run only verifies archive against database schema
+func (uBackup *backupUseCase) Restore(ctx context.Context, reader *backup.Reader, strategy string, dryRun bool) (res model.RestoreRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uBackup.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed restore"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	res.DryRun = dryRun
+	res.Strategy = strategy
+	res.SchemaVersion = reader.Manifest.SchemaVersion
+	if strategy != constants.RestoreStrategyReplace && strategy != constants.RestoreStrategyMerge {
+		return res, http.StatusBadRequest, "restore strategy not valid", fmt.Errorf("restore strategy must be %s or %s", constants.RestoreStrategyReplace, constants.RestoreStrategyMerge)
+	}
+
+	// verify archive checksum
+	err = reader.Verify()
+	if err != nil {
+		return res, http.StatusBadRequest, "backup archive is invalid", err
+	}
+
+	// archive schema must match database schema
+	schemaVersion, dirty, err := uBackup.backupRepo.GetSchemaVersion(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get database schema version", err
+	}
+	if dirty {
+		return res, http.StatusConflict, "database schema is dirty", fmt.Errorf("migration of schema version %d is not completed", schemaVersion)
+	}
+	if schemaVersion != reader.Manifest.SchemaVersion {
+		return res, http.StatusConflict, "backup archive schema version not match", fmt.Errorf("backup archive schema version is %d, database schema version is %d", reader.Manifest.SchemaVersion, schemaVersion)
+	}
+
+	// read and validate data
+	var data backupData
+	dataFiles := []struct {
+		name string
+		data interface{}
+	}{
+		{name: constants.BackupMonsterCategoryFile, data: &data.monsterCategories},
+		{name: constants.BackupMonsterTypeFile, data: &data.monsterTypes},
+		{name: constants.BackupMonsterFile, data: &data.monsters},
+		{name: constants.BackupMappingMonsterAndTypeFile, data: &data.mappingMonsterAndTypes},
+		{name: constants.BackupMonsterImageFile, data: &data.monsterImages},
+		{name: constants.BackupMonsterRevisionFile, data: &data.monsterRevisions},
+	}
+	for i := 0; i < len(dataFiles); i++ {
+		err = reader.ReadJSON(dataFiles[i].name, dataFiles[i].data)
+		if err != nil {
+			return res, http.StatusBadRequest, "backup archive is invalid", err
+		}
+	}
+	err = data.validate()
+	if err != nil {
+		return res, http.StatusBadRequest, "backup archive is invalid", err
+	}
+
+	imageEntries := reader.Names(constants.BackupImageDirectory)
+	for i := 0; i < len(imageEntries); i++ {
+		if strings.Contains(strings.TrimPrefix(imageEntries[i], constants.BackupImageDirectory), "/") {
+			return res, http.StatusBadRequest, "backup archive is invalid", fmt.Errorf("image entry %q is not valid", imageEntries[i])
+		}
+	}
+	if dryRun {
+		res.MonsterCategory = len(data.monsterCategories)
+		res.MonsterType = len(data.monsterTypes)
+		res.Monster = len(data.monsters)
+		res.MappingMonsterAndType = len(data.mappingMonsterAndTypes)
+		res.MonsterImage = len(data.monsterImages)
+		res.MonsterRevision = len(data.monsterRevisions)
+		return res, http.StatusOK, "restore dry run successfully, backup archive is valid", nil
+	}
+
+	// write image file, image file with same checksum is kept
+	for i := 0; i < len(imageEntries); i++ {
+		imageName := strings.TrimPrefix(imageEntries[i], constants.BackupImageDirectory)
+		file, _ := reader.File(imageEntries[i])
+		checksum, err := uploader.ImageChecksum(imageName)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to check image", err
+		}
+		if checksum == file.SHA256 {
+			res.ImageFileUnchanged++
+			continue
+		}
+		imageReader, err := reader.Open(imageEntries[i])
+		if err != nil {
+			return res, http.StatusBadRequest, "backup archive is invalid", err
+		}
+		err = uploader.WriteImage(imageName, imageReader)
+		imageReader.Close()
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to write image", err
+		}
+		res.ImageFileWritten++
+	}
+
+	// create database transaction
+	trx, resCode, err := uBackup.backupRepo.Transaction()
+	if err != nil {
+		return res, resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	defer tx.Rollback()
+
+	// replace strategy starts from empty monster data
+	if strategy == constants.RestoreStrategyReplace {
+		err = uBackup.backupRepo.DeleteAllMonsterData(tx, ctx)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to delete monster data", err
+		}
+	}
+
+	// restore data
+	err = uBackup.backupRepo.UpsertMonsterCategory(tx, ctx, data.monsterCategories)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore monster category", err
+	}
+	err = uBackup.backupRepo.UpsertMonsterType(tx, ctx, data.monsterTypes)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore monster type", err
+	}
+	err = uBackup.backupRepo.UpsertMonster(tx, ctx, data.monsters)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore monster", err
+	}
+	var monsterIds []string
+	var maxMonsterCode uint16
+	for i := 0; i < len(data.monsters); i++ {
+		monsterIds = append(monsterIds, data.monsters[i].ID)
+		if data.monsters[i].MonsterCode > maxMonsterCode {
+			maxMonsterCode = data.monsters[i].MonsterCode
+		}
+	}
+	err = uBackup.backupRepo.ReplaceMappingMonsterAndType(tx, ctx, monsterIds, data.mappingMonsterAndTypes)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore mapping monster and type", err
+	}
+	err = uBackup.backupRepo.ReplaceMonsterImage(tx, ctx, monsterIds, data.monsterImages)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore monster image", err
+	}
+	err = uBackup.backupRepo.CreateMonsterRevision(tx, ctx, data.monsterRevisions)
+	if err != nil {
+		return res, restoreErrorCode(err), "failed to restore monster revision", err
+	}
+
+	// sync monster code sequence, merge strategy never moves it backward
+	if strategy == constants.RestoreStrategyReplace {
+		err = uBackup.backupRepo.ResetMonsterCodeSequence(tx, ctx)
+	} else if maxMonsterCode > 0 {
+		err = uBackup.monsterRepo.SyncMonsterCodeSequence(tx, ctx, maxMonsterCode)
+	}
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to sync monster code sequence", err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+
+	// mapping response data
+	res.MonsterCategory = len(data.monsterCategories)
+	res.MonsterType = len(data.monsterTypes)
+	res.Monster = len(data.monsters)
+	res.MappingMonsterAndType = len(data.mappingMonsterAndTypes)
+	res.MonsterImage = len(data.monsterImages)
+	res.MonsterRevision = len(data.monsterRevisions)
+
+	return res, http.StatusOK, "restore successfully", nil
+}
+
+// imageNames is function to get sorted unique image file name referenced by monster and monster image
+func (data backupData) imageNames() (res []string) {
+	names := map[string]bool{}
+	for i := 0; i < len(data.monsters); i++ {
+		if data.monsters[i].ImageName != "" {
+			names[data.monsters[i].ImageName] = true
+		}
+	}
+	for i := 0; i < len(data.monsterImages); i++ {
+		if data.monsterImages[i].ImageName != "" {
+			names[data.monsterImages[i].ImageName] = true
+		}
+	}
+	for name := range names {
+		res = append(res, name)
+	}
+	sort.Strings(res)
+
+	return res
+}
+
+// validate is function to validate data of archive is self-contained, every reference points to row of the same archive
+func (data backupData) validate() (err error) {
+	categoryId := map[string]bool{}
+	for i := 0; i < len(data.monsterCategories); i++ {
+		if categoryId[data.monsterCategories[i].ID] {
+			return fmt.Errorf("monster category %s is duplicate", data.monsterCategories[i].ID)
+		}
+		categoryId[data.monsterCategories[i].ID] = true
+	}
+	typeId := map[string]bool{}
+	for i := 0; i < len(data.monsterTypes); i++ {
+		if typeId[data.monsterTypes[i].ID] {
+			return fmt.Errorf("monster type %s is duplicate", data.monsterTypes[i].ID)
+		}
+		typeId[data.monsterTypes[i].ID] = true
+	}
+	monsterId := map[string]bool{}
+	monsterCode := map[uint16]bool{}
+	for i := 0; i < len(data.monsters); i++ {
+		if monsterId[data.monsters[i].ID] {
+			return fmt.Errorf("monster %s is duplicate", data.monsters[i].ID)
+		}
+		if monsterCode[data.monsters[i].MonsterCode] {
+			return fmt.Errorf("monster code %d is duplicate", data.monsters[i].MonsterCode)
+		}
+		if !categoryId[data.monsters[i].MonsterCategoryId] {
+			return fmt.Errorf("monster %s references unknown monster category %s", data.monsters[i].ID, data.monsters[i].MonsterCategoryId)
+		}
+		monsterId[data.monsters[i].ID] = true
+		monsterCode[data.monsters[i].MonsterCode] = true
+	}
+	for i := 0; i < len(data.mappingMonsterAndTypes); i++ {
+		if !monsterId[data.mappingMonsterAndTypes[i].MonsterId] || !typeId[data.mappingMonsterAndTypes[i].MonsterTypeId] {
+			return fmt.Errorf("mapping of monster %s and monster type %s references unknown row", data.mappingMonsterAndTypes[i].MonsterId, data.mappingMonsterAndTypes[i].MonsterTypeId)
+		}
+	}
+	imageId := map[string]bool{}
+	for i := 0; i < len(data.monsterImages); i++ {
+		if imageId[data.monsterImages[i].ID] {
+			return fmt.Errorf("monster image %s is duplicate", data.monsterImages[i].ID)
+		}
+		if !monsterId[data.monsterImages[i].MonsterId] {
+			return fmt.Errorf("monster image %s references unknown monster %s", data.monsterImages[i].ID, data.monsterImages[i].MonsterId)
+		}
+		imageId[data.monsterImages[i].ID] = true
+	}
+	revisionId := map[string]bool{}
+	for i := 0; i < len(data.monsterRevisions); i++ {
+		if revisionId[data.monsterRevisions[i].ID] {
+			return fmt.Errorf("monster revision %s is duplicate", data.monsterRevisions[i].ID)
+		}
+		revisionId[data.monsterRevisions[i].ID] = true
+	}
+
+	return nil
+}
+
+// restoreErrorCode is function to get response code of restore error, unique violation means archive conflicts with existing data
+func restoreErrorCode(err error) int {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return http.StatusConflict
+	}
+
+	return http.StatusInternalServerError
+}
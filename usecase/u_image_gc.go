@@ -0,0 +1,227 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// ImageGCUseCaseInterface is
+type ImageGCUseCaseInterface interface {
+	RunImageGC(ctx context.Context, req model.ImageGCReq) (res model.ImageGCRes, resCode int, resMessage string, err error)
+}
+
+type imageGCUseCase struct {
+	ctxTimeout          time.Duration
+	monsterRepo         repository.MonsterRepositoryInterface
+	monsterImageRepo    repository.MonsterImageRepositoryInterface
+	monsterRevisionRepo repository.MonsterRevisionRepositoryInterface
+}
+
+func NewImageGCUseCase(ctxTimeout time.Duration, monsterRepo repository.MonsterRepositoryInterface, monsterImageRepo repository.MonsterImageRepositoryInterface, monsterRevisionRepo repository.MonsterRevisionRepositoryInterface) ImageGCUseCaseInterface {
+	return &imageGCUseCase{
+		ctxTimeout:          ctxTimeout,
+		monsterRepo:         monsterRepo,
+		monsterImageRepo:    monsterImageRepo,
+		monsterRevisionRepo: monsterRevisionRepo,
+	}
+}
+
+// RunImageGC is use case to report and clean up unreferenced image and monster with missing image
+func (uImageGC *imageGCUseCase) RunImageGC(ctx context.Context, req model.ImageGCReq) (res model.ImageGCRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uImageGC.ctxTimeout)
+	defer cancel()
+
+	res.DryRun = req.DryRun
+
+	// find all monster image, soft deleted monster still reference its image
+	resMonster, err := uImageGC.monsterRepo.GetAllMonsterImage(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster image", err
+	}
+	referencedImage := map[string]bool{}
+	for i := 0; i < len(resMonster); i++ {
+		if resMonster[i].ImageName != "" {
+			referencedImage[resMonster[i].ImageName] = true
+		}
+	}
+
+	// find all monster gallery image
+	resMonsterImage, err := uImageGC.monsterImageRepo.GetAllMonsterImage(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster gallery image", err
+	}
+	for i := 0; i < len(resMonsterImage); i++ {
+		referencedImage[resMonsterImage[i].ImageName] = true
+	}
+
+	// find monster with missing image
+	var missingId []string
+	var foundId []string
+	activeMonster := map[string]model.Monster{}
+	for i := 0; i < len(resMonster); i++ {
+		if resMonster[i].DeletedAt != nil && resMonster[i].DeletedAt.Valid {
+			continue
+		}
+		activeMonster[resMonster[i].ID] = resMonster[i]
+		if resMonster[i].ImageName == "" {
+			continue
+		}
+		isExist, err := uploader.IsImageExist(resMonster[i].ImageName)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to check monster image", err
+		}
+		if isExist {
+			if resMonster[i].IsImageMissing {
+				foundId = append(foundId, resMonster[i].ID)
+			}
+			continue
+		}
+		missingId = append(missingId, resMonster[i].ID)
+		res.MissingImages = append(res.MissingImages, model.MissingImage{
+			MonsterId:   resMonster[i].ID,
+			MonsterCode: resMonster[i].MonsterCode,
+			Name:        resMonster[i].Name,
+			ImageName:   resMonster[i].ImageName,
+			IsFlagged:   !req.DryRun,
+		})
+	}
+
+	// find monster gallery image with missing file, primary image which is already checked with its monster is skipped
+	// gallery image has no flag, so it is reported only
+	for i := 0; i < len(resMonsterImage); i++ {
+		monster, isActive := activeMonster[resMonsterImage[i].MonsterId]
+		if !isActive || resMonsterImage[i].ImageName == monster.ImageName {
+			continue
+		}
+		isExist, err := uploader.IsImageExist(resMonsterImage[i].ImageName)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to check monster gallery image", err
+		}
+		if isExist {
+			continue
+		}
+		res.MissingImages = append(res.MissingImages, model.MissingImage{
+			MonsterId:      monster.ID,
+			MonsterImageId: resMonsterImage[i].ID,
+			MonsterCode:    monster.MonsterCode,
+			Name:           monster.Name,
+			ImageName:      resMonsterImage[i].ImageName,
+		})
+	}
+
+	// find unreferenced image older than grace period
+	images, err := uploader.ListImage()
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to list image", err
+	}
+	graceLimit := time.Now().Add(-req.GracePeriod)
+	for i := 0; i < len(images); i++ {
+		if referencedImage[images[i].Name()] || images[i].ModTime().After(graceLimit) {
+			continue
+		}
+		orphanedImage := model.OrphanedImage{
+			ImageName:  images[i].Name(),
+			Size:       images[i].Size(),
+			ModifiedAt: images[i].ModTime(),
+		}
+		if !req.DryRun {
+			err = uploader.DeleteImage(images[i].Name())
+			if err != nil {
+				return res, http.StatusInternalServerError, "failed to delete orphaned image", err
+			}
+			orphanedImage.IsDeleted = true
+		}
+		res.OrphanedImages = append(res.OrphanedImages, orphanedImage)
+	}
+
+	if req.DryRun {
+		return res, http.StatusOK, "image garbage collection dry run successfully", nil
+	}
+
+	// flag monster with missing image
+	for i := 0; i < len(missingId); i++ {
+		err = uImageGC.flagMonsterImageMissing(ctx, missingId[i], true)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to flag monster with missing image", err
+		}
+	}
+
+	// unflag monster which image is found again
+	for i := 0; i < len(foundId); i++ {
+		err = uImageGC.flagMonsterImageMissing(ctx, foundId[i], false)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to unflag monster with found image", err
+		}
+	}
+
+	return res, http.StatusOK, "image garbage collection successfully", nil
+}
+
+// flagMonsterImageMissing is function to flag or unflag monster with missing image, the change is recorded as revision
+// monster which is deleted since the scan is skipped
+func (uImageGC *imageGCUseCase) flagMonsterImageMissing(ctx context.Context, monsterId string, isImageMissing bool) (err error) {
+	// create database transaction
+	trx, _, err := uImageGC.monsterRepo.Transaction()
+	if err != nil {
+		return err
+	}
+	tx := trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster version, row is locked until transaction end
+	err = uImageGC.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	// find monster snapshot before the change
+	resSnapshot, err := uImageGC.monsterRepo.GetMonsterSnapshot(tx, ctx, monsterId)
+	if err != nil {
+		return err
+	}
+
+	// update monster missing image flag
+	err = uImageGC.monsterRepo.UpdateMonster(tx, ctx, map[string]interface{}{
+		"value": map[string]interface{}{
+			"is_image_missing": isImageMissing,
+		},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"id = ?": monsterId,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// record monster revision
+	err = createMonsterRevision(tx, ctx, uImageGC.monsterRepo, uImageGC.monsterRevisionRepo, constants.RevisionActionImageCheck, &resSnapshot, monsterId)
+	if err != nil {
+		return err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return err
+	}
+	isCommitted = true
+
+	return nil
+}
@@ -2,16 +2,27 @@ package usecase
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/diff"
+	"github.com/frianlh/pokedex-api/libs/exporter"
+	"github.com/frianlh/pokedex-api/libs/filter"
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/libs/search"
+	"github.com/frianlh/pokedex-api/libs/similarity"
 	"github.com/frianlh/pokedex-api/libs/uploader"
 	"github.com/frianlh/pokedex-api/model"
 	"github.com/frianlh/pokedex-api/repository"
-	"github.com/lib/pq"
+	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"io"
 	"net/http"
-	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 )
 
 // MonsterUseCaseInterface is
@@ -19,22 +30,114 @@ type MonsterUseCaseInterface interface {
 	CreateMonster(ctx context.Context, req model.CreateMonsterReq) (resCode int, resMessage string, err error)
 	GetMonsterById(ctx context.Context, reqId string) (res model.GetDetailMonsterRes, resCode int, resMessage string, err error)
 	GetListMonster(ctx context.Context, queryReq model.MonsterQueryReq) (res []model.GetListMonsterRes, resCode int, resMessage string, err error)
-	UpdateMonster(ctx context.Context, reqId string, req model.UpdateMonsterReq) (resCode int, resMessage string, err error)
+	SimilarMonster(ctx context.Context, reqId, exclude string, limit int) (res []model.SimilarMonsterRes, resCode int, resMessage string, err error)
+	GetMonsterFacet(ctx context.Context, queryReq model.MonsterQueryReq, facets []string) (res model.MonsterFacetRes, resCode int, resMessage string, err error)
+	ExportMonster(queryReq model.MonsterQueryReq, format string) (export func(ctx context.Context, writer io.Writer) error, resCode int, resMessage string, err error)
+	SuggestMonster(ctx context.Context, prefix string, limit int) (res []model.MonsterSuggestionRes, resCode int, resMessage string, err error)
+	GetMonsterFingerprint(ctx context.Context, reqId string) (res model.Fingerprint, resCode int, resMessage string, err error)
+	UpdateMonster(ctx context.Context, reqId string, version int, req model.UpdateMonsterReq) (resCode int, resMessage string, err error)
+	PatchMonster(ctx context.Context, reqId string, version int, req model.PatchMonsterReq) (resCode int, resMessage string, err error)
 	UpdateMonsterCaptured(ctx context.Context, reqId string, req model.UpdateMonsterCapturedReq) (resCode int, resMessage string, err error)
-	DeleteMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error)
+	DeleteMonster(ctx context.Context, reqId string, version int) (resCode int, resMessage string, err error)
+	GetMonsterHistory(ctx context.Context, reqId string, page, perPage int) (res []model.MonsterRevisionRes, count int64, resCode int, resMessage string, err error)
+	RevertMonster(ctx context.Context, reqId, revisionId string, version int) (resCode int, resMessage string, err error)
+	BulkMonster(ctx context.Context, req []model.BulkMonsterOperation, continueOnError bool) (res []model.BulkMonsterResultRes, resCode int, resMessage string, err error)
 }
 
+// MonsterFilterFields is whitelisted field of filter expression of list monster
+var MonsterFilterFields = map[string]filter.Field{
+	"name": {Kind: filter.KindText, Column: "monsters.name"},
+	"category": {Kind: filter.KindText, Match: `EXISTS (SELECT 1 FROM monster_categories mc
+		WHERE mc.id = monsters.monster_category_id AND lower(mc.name) = lower(?))`},
+	"type": {Kind: filter.KindText, Match: `EXISTS (SELECT 1 FROM mapping_monster_and_types map
+		INNER JOIN monster_types mt ON mt.id = map.monster_type_id
+		WHERE map.monster_id = monsters.id AND lower(mt.name) = lower(?))`},
+	"code":    {Kind: filter.KindNumber, Column: "monsters.monster_code"},
+	"hp":      {Kind: filter.KindNumber, Column: "monsters.hp"},
+	"attack":  {Kind: filter.KindNumber, Column: "monsters.attack"},
+	"defends": {Kind: filter.KindNumber, Column: "monsters.defends"},
+	"speed":   {Kind: filter.KindNumber, Column: "monsters.speed"},
+	"weight":  {Kind: filter.KindNumber, Column: "monsters.weight"},
+	"length":  {Kind: filter.KindNumber, Column: "CAST(monsters.length AS real)", Placeholder: "CAST(? AS real)"},
+	"caught":  {Kind: filter.KindBool, Column: "monsters.is_caught"},
+}
+
+// MonsterSortFields is whitelisted sort field of list monster with its SQL expression, relevance is only valid with search query
+var MonsterSortFields = map[string]string{
+	"monster_code":            "monsters.monster_code",
+	"name":                    "monsters.name",
+	"category":                "(SELECT mc.name FROM monster_categories mc WHERE mc.id = monsters.monster_category_id)",
+	"length":                  "monsters.length",
+	"weight":                  "monsters.weight",
+	"hp":                      "monsters.hp",
+	"attack":                  "monsters.attack",
+	"defends":                 "monsters.defends",
+	"speed":                   "monsters.speed",
+	"stat_total":              "(monsters.hp + monsters.attack + monsters.defends + monsters.speed)",
+	"is_caught":               "monsters.is_caught",
+	"created_at":              "monsters.created_at",
+	"updated_at":              "monsters.updated_at",
+	constants.SortByRelevance: "",
+}
+
+// monsterField is whitelisted sparse field or include of list monster, columns are selected and preload is loaded only
+// when the field is asked for, keys are JSON key of list monster response which the field fills
+type monsterField struct {
+	columns []string
+	preload string
+	keys    []string
+}
+
+// monsterListFields is whitelisted sparse field of list monster, id is always selected since preload and order need it
+var monsterListFields = map[string]monsterField{
+	"id":           {keys: []string{"id"}},
+	"monster_code": {columns: []string{"monsters.monster_code"}, keys: []string{"monster_code"}},
+	"name":         {columns: []string{"monsters.name"}, keys: []string{"name"}},
+	"description":  {columns: []string{"monsters.description"}, keys: []string{"description"}},
+	"length":       {columns: []string{"monsters.length"}, keys: []string{"length"}},
+	"weight":       {columns: []string{"monsters.weight"}, keys: []string{"weight"}},
+	"hp":           {columns: []string{"monsters.hp"}, keys: []string{"hp"}},
+	"attack":       {columns: []string{"monsters.attack"}, keys: []string{"attack"}},
+	"defends":      {columns: []string{"monsters.defends"}, keys: []string{"defends"}},
+	"speed":        {columns: []string{"monsters.speed"}, keys: []string{"speed"}},
+	"is_caught":    {columns: []string{"monsters.is_caught"}, keys: []string{"is_caught"}},
+	"image": {
+		columns: []string{"monsters.image_name"},
+		preload: "PrimaryMonsterImage",
+		keys:    []string{"image_name", "image_url", "blur_hash", "dominant_color", "primary_image"},
+	},
+	"created_at": {columns: []string{"monsters.created_at"}, keys: []string{"created_at"}},
+	"updated_at": {columns: []string{"monsters.updated_at"}, keys: []string{"updated_at"}},
+}
+
+// monsterListIncludes is whitelisted relationship of list monster
+var monsterListIncludes = map[string]monsterField{
+	"category": {columns: []string{"monsters.monster_category_id"}, preload: "MonsterCategory", keys: []string{"monster_category"}},
+	"types":    {preload: "MonsterTypes", keys: []string{"monster_types"}},
+}
+
+var (
+	monsterListDefaultFields   = []string{"id", "monster_code", "name", "is_caught", "image"}
+	monsterListDefaultIncludes = []string{"category", "types"}
+)
+
 type monsterUseCase struct {
-	ctxTimeout  time.Duration
-	baseURL     string
-	monsterRepo repository.MonsterRepositoryInterface
+	ctxTimeout          time.Duration
+	imageURL            uploader.ImageURL
+	monsterRepo         repository.MonsterRepositoryInterface
+	monsterImageRepo    repository.MonsterImageRepositoryInterface
+	monsterRevisionRepo repository.MonsterRevisionRepositoryInterface
+	bulkTx              *gorm.DB
+	bulkReplacedImages  *[]string
 }
 
-func NewMonsterUseCase(ctxTimeout time.Duration, baseURL string, monsterRepo repository.MonsterRepositoryInterface) MonsterUseCaseInterface {
+func NewMonsterUseCase(ctxTimeout time.Duration, imageURL uploader.ImageURL, monsterRepo repository.MonsterRepositoryInterface, monsterImageRepo repository.MonsterImageRepositoryInterface, monsterRevisionRepo repository.MonsterRevisionRepositoryInterface) MonsterUseCaseInterface {
 	return &monsterUseCase{
-		ctxTimeout:  ctxTimeout,
-		baseURL:     baseURL,
-		monsterRepo: monsterRepo,
+		ctxTimeout:          ctxTimeout,
+		imageURL:            imageURL,
+		monsterRepo:         monsterRepo,
+		monsterImageRepo:    monsterImageRepo,
+		monsterRevisionRepo: monsterRevisionRepo,
 	}
 }
 
@@ -55,16 +158,10 @@ func (uMonster *monsterUseCase) CreateMonster(ctx context.Context, req model.Cre
 		}
 	}()
 
-	// get last monster code
-	lastMonsterCode, err := uMonster.monsterRepo.GetLastMonsterCode()
-	if err != nil {
-		return http.StatusInternalServerError, "failed to get last monster code", err
-	}
-
-	// mapping req create monster
+	// mapping req create monster, monster code is generated by database sequence if not picked
 	reqMonster := model.Monster{
 		Name:              req.Name,
-		MonsterCode:       lastMonsterCode + 1,
+		MonsterCode:       req.MonsterCode,
 		MonsterCategoryId: req.MonsterCategoryId,
 		Description:       req.Description,
 		Length:            req.Length,
@@ -73,7 +170,9 @@ func (uMonster *monsterUseCase) CreateMonster(ctx context.Context, req model.Cre
 		Attack:            req.Attack,
 		Defends:           req.Defends,
 		Speed:             req.Speed,
+		IsCaught:          req.IsCaught,
 		ImageName:         req.ImageName,
+		ExternalId:        req.ExternalId,
 	}
 
 	// create database transaction
@@ -81,17 +180,38 @@ func (uMonster *monsterUseCase) CreateMonster(ctx context.Context, req model.Cre
 	if err != nil {
 		return resCode, "failed to create database transaction", err
 	}
-	tx = trx.Begin()
+	tx = uMonster.beginTransaction(trx)
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			uMonster.rollbackTransaction(tx)
+		}
+	}()
 
 	// create monster
 	monsterId, err := uMonster.monsterRepo.CreateMonster(tx, ctx, reqMonster)
 	if err != nil {
-		if err.(*pq.Error).Code == "23503" {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constants.MonsterCodeConstraint {
+			return http.StatusConflict, "monster code already used", err
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == constants.MonsterExternalIdConstraint {
+			return http.StatusConflict, "monster external id already used", err
+		}
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
 			return http.StatusBadRequest, "invalid monster category", err
 		}
 		return http.StatusInternalServerError, "failed to create monster", err
 	}
 
+	// sync monster code sequence with picked monster code
+	if req.MonsterCode != 0 {
+		err = uMonster.monsterRepo.SyncMonsterCodeSequence(tx, ctx, req.MonsterCode)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to sync monster code sequence", err
+		}
+	}
+
 	// mapping req create mapping monster and monster type
 	var reqMonsterAndType []model.MappingMonsterAndTypes
 	if req.MonsterTypes != nil {
@@ -106,18 +226,41 @@ func (uMonster *monsterUseCase) CreateMonster(ctx context.Context, req model.Cre
 		// create mapping monster and monster type
 		err = uMonster.monsterRepo.CreateMappingMonsterAndType(tx, ctx, reqMonsterAndType)
 		if err != nil {
-			if err.(*pq.Error).Code == "23503" {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
 				return http.StatusBadRequest, "invalid monster or monster type data", err
 			}
 			return http.StatusInternalServerError, "failed to create monster type", err
 		}
 	}
 
+	// create primary image of monster gallery
+	if req.ImageName != "" {
+		_, err = uMonster.monsterImageRepo.CreateMonsterImage(tx, ctx, model.MonsterImage{
+			MonsterId:     monsterId,
+			ImageName:     req.ImageName,
+			IsPrimary:     true,
+			VariantTags:   []string{},
+			BlurHash:      req.BlurHash,
+			DominantColor: req.DominantColor,
+		})
+		if err != nil {
+			return http.StatusInternalServerError, "failed to create monster image", err
+		}
+	}
+
+	// record monster revision
+	err = createMonsterRevision(tx, ctx, uMonster.monsterRepo, uMonster.monsterRevisionRepo, constants.RevisionActionCreate, nil, monsterId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to record monster revision", err
+	}
+
 	// commit database transaction
-	err = tx.Commit().Error
+	err = uMonster.commitTransaction(tx)
 	if err != nil {
 		return http.StatusInternalServerError, "failed to commit database transaction", err
 	}
+	isCommitted = true
 
 	// mapping response data
 	resCode = http.StatusCreated
@@ -137,11 +280,12 @@ func (uMonster *monsterUseCase) GetMonsterById(ctx context.Context, reqId string
 		"preloadParams": map[string]interface{}{
 			"MonsterCategory": true,
 			"MonsterTypes":    true,
+			"MonsterImages":   true,
 		},
 	}
 
 	// find monster by id
-	resMonster, err := uMonster.monsterRepo.GetMonsterById(ctx, reqId, queryGetParams)
+	resMonster, err := uMonster.monsterRepo.GetMonsterById(nil, ctx, reqId, queryGetParams)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return res, http.StatusBadRequest, "monster not found", err
@@ -164,7 +308,17 @@ func (uMonster *monsterUseCase) GetMonsterById(ctx context.Context, reqId string
 	res.Speed = resMonster.Speed
 	res.IsCaught = resMonster.IsCaught
 	res.ImageName = resMonster.ImageName
-	res.ImageURL = fmt.Sprintf("%s/api/v1/monster/images/%s", uMonster.baseURL, resMonster.ImageName)
+	res.ImageURL = uMonster.imageURL.Generate(resMonster.ImageName, false)
+	res.IsImageMissing = resMonster.IsImageMissing
+	res.Version = resMonster.Version
+	res.Images = []model.MonsterImageRes{}
+	for i := 0; i < len(resMonster.MonsterImages); i++ {
+		monsterImage := monsterImageRes(uMonster.imageURL, resMonster.MonsterImages[i])
+		if monsterImage.IsPrimary {
+			res.ImageURL = monsterImage.ImageURL
+		}
+		res.Images = append(res.Images, monsterImage)
+	}
 
 	return res, http.StatusOK, "get monster successfully", nil
 }
@@ -175,33 +329,32 @@ func (uMonster *monsterUseCase) GetListMonster(ctx context.Context, queryReq mod
 	defer cancel()
 
 	// query get params
-	queryGetParams := map[string]interface{}{
-		"selectParams": []string{`monsters.id, monsters.monster_code, monsters.name, monsters.monster_category_id, monsters.image_name, monsters.created_at`},
-		"whereParams": map[string]interface{}{
-			"default": map[string]interface{}{},
-			"in":      map[string]interface{}{},
-		},
-		"preloadParams": map[string]interface{}{
-			"MonsterCategory": true,
-			"MonsterTypes":    true,
-		},
-		"joinParams": map[string]interface{}{},
+	queryReq = monsterQueryNormalization(queryReq)
+	queryGetParams, resCode, resMessage, err := monsterListParams(queryReq)
+	if err != nil {
+		return nil, resCode, resMessage, err
 	}
-	if queryReq.Name != "" {
-		queryGetParams["whereParams"].(map[string]interface{})["default"].(map[string]interface{})["lower(monsters.name) LIKE lower(?)"] = "%" + queryReq.Name + "%"
+	columns, preloadParams, isField, err := monsterListSelect(queryReq)
+	if err != nil {
+		return nil, http.StatusBadRequest, "fields not valid", err
 	}
-	if queryReq.MonsterTypeId != nil {
-		queryGetParams["selectParams"] = []string{`DISTINCT monsters.id, monsters.monster_code, monsters.name, monsters.monster_category_id, monsters.image_name, monsters.created_at`}
-		queryGetParams["joinParams"].(map[string]interface{})["INNER JOIN mapping_monster_and_types map ON map.monster_id = monsters.id"] = true
-		queryGetParams["whereParams"].(map[string]interface{})["in"].(map[string]interface{})["map.monster_type_id IN (?)"] = queryReq.MonsterTypeId
+	queryGetParams["preloadParams"] = preloadParams
+	queryGetParams["joinParams"] = map[string]interface{}{}
+	orderParams, err := monsterOrder(queryReq, clause.Expr{SQL: "search_rank"})
+	if err != nil {
+		return nil, http.StatusBadRequest, "sort not valid", err
 	}
-	if queryReq.IsCaught != "" {
-		isCaughtBool, err := strconv.ParseBool(queryReq.IsCaught)
-		if err != nil {
-			return nil, http.StatusBadRequest, "is_caught format not valid", err
-		}
-		queryGetParams["whereParams"].(map[string]interface{})["default"].(map[string]interface{})["monsters.is_caught = ?"] = isCaughtBool
+	queryGetParams["orderParams"] = orderParams
+	selectParams := strings.Join(columns, ", ")
+	if queryReq.Q != "" {
+		// name and description are highlighted by matched term of search query
+		queryGetParams["joinParams"].(map[string]interface{})[fmt.Sprintf("CROSS JOIN websearch_to_tsquery('%s', ?) search_query", constants.SearchConfig)] = queryReq.Q
+		selectParams += fmt.Sprintf(`, ts_rank_cd(monsters.search_vector, search_query) AS search_rank,
+			ts_headline('%[1]s', monsters.name, search_query, 'StartSel=%[2]s, StopSel=%[3]s, HighlightAll=true') AS name_highlight,
+			ts_headline('%[1]s', monsters.description, search_query, 'StartSel=%[2]s, StopSel=%[3]s, MaxWords=35, MinWords=15, MaxFragments=2') AS description_highlight`,
+			constants.SearchConfig, search.HighlightStart, search.HighlightStop)
 	}
+	queryGetParams["selectParams"] = []string{selectParams}
 
 	// find list monster
 	resMonster, err := uMonster.monsterRepo.GetListMonster(ctx, queryReq, queryGetParams)
@@ -219,7 +372,49 @@ func (uMonster *monsterUseCase) GetListMonster(ctx context.Context, queryReq mod
 			MonsterTypes:    resMonster[i].MonsterTypes,
 			IsCaught:        resMonster[i].IsCaught,
 			ImageName:       resMonster[i].ImageName,
-			ImageURL:        fmt.Sprintf("%s/api/v1/monster/images/%s", uMonster.baseURL, resMonster[i].ImageName),
+			ImageURL:        uMonster.imageURL.Generate(resMonster[i].ImageName, false),
+		}
+		if len(resMonster[i].MonsterImages) > 0 {
+			primaryImage := monsterImageRes(uMonster.imageURL, resMonster[i].MonsterImages[0])
+			monster.PrimaryImage = &primaryImage
+			monster.ImageURL = primaryImage.ImageURL
+			monster.BlurHash = primaryImage.BlurHash
+			monster.DominantColor = primaryImage.DominantColor
+		}
+		if isField["description"] {
+			monster.Description = &resMonster[i].Description
+		}
+		if isField["length"] {
+			monster.Length = &resMonster[i].Length
+		}
+		if isField["weight"] {
+			monster.Weight = &resMonster[i].Weight
+		}
+		if isField["hp"] {
+			monster.HP = &resMonster[i].HP
+		}
+		if isField["attack"] {
+			monster.Attack = &resMonster[i].Attack
+		}
+		if isField["defends"] {
+			monster.Defends = &resMonster[i].Defends
+		}
+		if isField["speed"] {
+			monster.Speed = &resMonster[i].Speed
+		}
+		if isField["created_at"] {
+			monster.CreatedAt = &resMonster[i].CreatedAt
+		}
+		if isField["updated_at"] {
+			monster.UpdatedAt = &resMonster[i].UpdatedAt
+		}
+		if queryReq.Q != "" {
+			searchRank := resMonster[i].SearchRank
+			monster.SearchRank = &searchRank
+			monster.Highlight = &model.MonsterHighlight{
+				Name:        search.Highlight(resMonster[i].NameHighlight),
+				Description: search.Highlight(resMonster[i].DescriptionHighlight),
+			}
 		}
 		res = append(res, monster)
 	}
@@ -227,8 +422,322 @@ func (uMonster *monsterUseCase) GetListMonster(ctx context.Context, queryReq mod
 	return res, http.StatusOK, "get all monster successfully", nil
 }
 
-// UpdateMonster is use case to update monster
-func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string, req model.UpdateMonsterReq) (resCode int, resMessage string, err error) {
+// GetMonsterFacet is use case to count monster per facet value, count is computed with the same condition as list monster
+// so it matches the monster of current search and filter
+func (uMonster *monsterUseCase) GetMonsterFacet(ctx context.Context, queryReq model.MonsterQueryReq, facets []string) (res model.MonsterFacetRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
+	defer cancel()
+
+	// query get params
+	queryReq = monsterQueryNormalization(queryReq)
+	queryGetParams, resCode, resMessage, err := monsterListParams(queryReq)
+	if err != nil {
+		return res, resCode, resMessage, err
+	}
+
+	// count monster per facet value
+	for i := 0; i < len(facets); i++ {
+		resFacet, err := uMonster.monsterRepo.GetListMonsterFacet(ctx, queryReq, queryGetParams, facets[i])
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to get monster facet", err
+		}
+
+		// mapping response data
+		switch facets[i] {
+		case constants.MonsterFacetType:
+			res.Type = resFacet
+		case constants.MonsterFacetCategory:
+			res.Category = resFacet
+		case constants.MonsterFacetCaught:
+			res.Caught = &model.MonsterCaughtFacetRes{}
+			for j := 0; j < len(resFacet); j++ {
+				if resFacet[j].Value == "true" {
+					res.Caught.Caught = resFacet[j].Count
+				} else {
+					res.Caught.Uncaught = resFacet[j].Count
+				}
+			}
+		}
+	}
+
+	return res, http.StatusOK, "get monster facet successfully", nil
+}
+
+// ExportMonster is use case to build export of list monster into export file of given format, query is built with the same
+// condition and order as list monster before the first row is written, so invalid request is still answered with error status
+// export streams list monster from database cursor and is bounded by export timeout since large table takes longer to stream,
+// building the export does not touch the database so only export takes context, which is the context of the stream
+func (uMonster *monsterUseCase) ExportMonster(queryReq model.MonsterQueryReq, format string) (export func(ctx context.Context, writer io.Writer) error, resCode int, resMessage string, err error) {
+	// query get params
+	queryReq = monsterQueryNormalization(queryReq)
+	queryGetParams, resCode, resMessage, err := monsterListParams(queryReq)
+	if err != nil {
+		return nil, resCode, resMessage, err
+	}
+	orderParams, err := monsterOrder(queryReq, clause.Expr{
+		SQL:  fmt.Sprintf("ts_rank_cd(monsters.search_vector, websearch_to_tsquery('%s', ?))", constants.SearchConfig),
+		Vars: []interface{}{queryReq.Q},
+	})
+	if err != nil {
+		return nil, http.StatusBadRequest, "sort not valid", err
+	}
+	queryGetParams["orderParams"] = orderParams
+	_, err = exporter.ContentType(format)
+	if err != nil {
+		return nil, http.StatusBadRequest, "export format not valid", err
+	}
+
+	export = func(ctx context.Context, writer io.Writer) (err error) {
+		ctx, cancel := context.WithTimeout(ctx, constants.ExportTimeout)
+		defer cancel()
+
+		// create export writer
+		exportWriter, err := exporter.NewWriter(format, writer, constants.ExportTypeSeparator)
+		if err != nil {
+			return err
+		}
+
+		// stream list monster into export writer
+		err = uMonster.monsterRepo.StreamListMonster(ctx, queryReq, queryGetParams, func(res model.MonsterExport) error {
+			return exportWriter.Write(exporter.Row{
+				ID:          res.ID,
+				MonsterCode: res.MonsterCode,
+				Name:        res.Name,
+				Category:    res.CategoryName,
+				Types:       res.TypeNames,
+				Description: res.Description,
+				Length:      res.Length,
+				Weight:      res.Weight,
+				HP:          res.HP,
+				Attack:      res.Attack,
+				Defends:     res.Defends,
+				Speed:       res.Speed,
+				IsCaught:    res.IsCaught,
+				Image:       res.ImageName,
+				CreatedAt:   res.CreatedAt,
+				UpdatedAt:   res.UpdatedAt,
+			})
+		})
+		if err != nil {
+			return err
+		}
+
+		return exportWriter.Close()
+	}
+
+	return export, http.StatusOK, "export monster successfully", nil
+}
+
+// SuggestMonster is use case to get monster name and id for autocomplete
+func (uMonster *monsterUseCase) SuggestMonster(ctx context.Context, prefix string, limit int) (res []model.MonsterSuggestionRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
+	defer cancel()
+
+	if prefix == "" || utf8.RuneCountInString(prefix) > constants.SuggestMaxPrefixLength {
+		return nil, http.StatusBadRequest, "prefix not valid", fmt.Errorf("prefix must be 1 to %d characters", constants.SuggestMaxPrefixLength)
+	}
+	if limit < 1 || limit > constants.SuggestMaxLimit {
+		return nil, http.StatusBadRequest, "limit not valid", fmt.Errorf("limit must be between 1 and %d", constants.SuggestMaxLimit)
+	}
+
+	// find list monster suggestion
+	res, err = uMonster.monsterRepo.GetListMonsterSuggestion(ctx, prefix, limit)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed to get monster suggestion", err
+	}
+	if res == nil {
+		res = []model.MonsterSuggestionRes{}
+	}
+
+	return res, http.StatusOK, "get monster suggestion successfully", nil
+}
+
+// SimilarMonster is use case to rank other active monster by similarity of stat, type, and category to monster by id,
+// score is calculated by the database so only the top of the ranking is read, monster matching exclude expression is left out
+func (uMonster *monsterUseCase) SimilarMonster(ctx context.Context, reqId, exclude string, limit int) (res []model.SimilarMonsterRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
+	defer cancel()
+
+	if limit < 1 || limit > constants.SimilarMaxLimit {
+		return nil, http.StatusBadRequest, "limit not valid", fmt.Errorf("limit must be between 1 and %d", constants.SimilarMaxLimit)
+	}
+
+	// find monster by id
+	resMonster, err := uMonster.monsterRepo.GetMonsterById(nil, ctx, reqId, map[string]interface{}{
+		"preloadParams": map[string]interface{}{
+			"MonsterTypes": true,
+		},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, http.StatusBadRequest, "monster not found", err
+		}
+		return nil, http.StatusInternalServerError, "failed to get monster by id", err
+	}
+
+	// find stat range to normalize stat
+	resStatRange, err := uMonster.monsterRepo.GetMonsterStatRange(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed to get monster stat range", err
+	}
+	statRange := similarity.Stat{
+		HP:      resStatRange.HP,
+		Attack:  resStatRange.Attack,
+		Defends: resStatRange.Defends,
+		Speed:   resStatRange.Speed,
+	}
+	target := similarMonsterProfile(resMonster)
+
+	// query get params
+	scoreSQL, scoreArgs := similarity.ScoreSQL(target, statRange, similarity.Columns{
+		HP:              "monsters.hp",
+		Attack:          "monsters.attack",
+		Defends:         "monsters.defends",
+		Speed:           "monsters.speed",
+		CategoryId:      "monsters.monster_category_id",
+		SharedTypeCount: "(SELECT COUNT(*) FROM mapping_monster_and_types map WHERE map.monster_id = monsters.id AND map.monster_type_id IN ?)",
+		TypeCount:       "(SELECT COUNT(*) FROM mapping_monster_and_types map WHERE map.monster_id = monsters.id)",
+	})
+	queryGetParams := map[string]interface{}{
+		"selectParams": []string{`monsters.id, monsters.monster_code, monsters.name, monsters.monster_category_id, monsters.hp,
+			monsters.attack, monsters.defends, monsters.speed, monsters.image_name`},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"monsters.id <> ?": resMonster.ID,
+			},
+		},
+		"preloadParams": map[string]interface{}{
+			"MonsterCategory":     true,
+			"MonsterTypes":        true,
+			"PrimaryMonsterImage": true,
+		},
+		"orderParams": clause.Expr{SQL: scoreSQL + " DESC, monsters.id ASC", Vars: scoreArgs},
+		"limitParams": limit,
+	}
+	if exclude != "" {
+		sql, args, err := filter.Compile(exclude, MonsterFilterFields)
+		if err != nil {
+			return nil, http.StatusBadRequest, "exclude expression not valid", err
+		}
+		queryGetParams["filterParams"] = gorm.Expr("NOT ("+sql+")", args...)
+	}
+
+	// find list similar monster
+	resSimilar, err := uMonster.monsterRepo.GetListMonster(ctx, model.MonsterQueryReq{}, queryGetParams)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed to get similar monster", err
+	}
+
+	// mapping response data
+	res = []model.SimilarMonsterRes{}
+	for i := 0; i < len(resSimilar); i++ {
+		monster := model.SimilarMonsterRes{
+			ID:              resSimilar[i].ID,
+			MonsterCode:     resSimilar[i].MonsterCode,
+			Name:            resSimilar[i].Name,
+			MonsterCategory: resSimilar[i].MonsterCategory,
+			MonsterTypes:    resSimilar[i].MonsterTypes,
+			HP:              resSimilar[i].HP,
+			Attack:          resSimilar[i].Attack,
+			Defends:         resSimilar[i].Defends,
+			Speed:           resSimilar[i].Speed,
+			ImageURL:        uMonster.imageURL.Generate(resSimilar[i].ImageName, false),
+			Score:           similarity.Score(target, similarMonsterProfile(resSimilar[i]), statRange),
+		}
+		if len(resSimilar[i].MonsterImages) > 0 {
+			monster.ImageURL = monsterImageRes(uMonster.imageURL, resSimilar[i].MonsterImages[0]).ImageURL
+		}
+		res = append(res, monster)
+	}
+
+	return res, http.StatusOK, "get similar monster successfully", nil
+}
+
+// GetMonsterFingerprint is use case to get fingerprint of monster by id, or of all monster if id is empty, for conditional request
+func (uMonster *monsterUseCase) GetMonsterFingerprint(ctx context.Context, reqId string) (res model.Fingerprint, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
+	defer cancel()
+
+	// find monster fingerprint
+	res, err = uMonster.monsterRepo.GetMonsterFingerprint(ctx, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, http.StatusNotFound, "monster not found", err
+		}
+		return res, http.StatusInternalServerError, "failed to get monster fingerprint", err
+	}
+
+	return res, http.StatusOK, "get monster fingerprint successfully", nil
+}
+
+// UpdateMonster is use case to replace monster with its full representation
+func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string, version int, req model.UpdateMonsterReq) (resCode int, resMessage string, err error) {
+	// mapping req update monster, every field is replaced
+	value := map[string]interface{}{
+		"name":                req.Name,
+		"monster_category_id": req.MonsterCategoryId,
+		"description":         *req.Description,
+		"length":              *req.Length,
+		"weight":              *req.Weight,
+		"hp":                  *req.HP,
+		"attack":              *req.Attack,
+		"defends":             *req.Defends,
+		"speed":               *req.Speed,
+		"is_caught":           *req.IsCaught,
+	}
+
+	return uMonster.updateMonster(ctx, constants.RevisionActionUpdate, reqId, version, value, req.MonsterTypes, model.MonsterImage{
+		ImageName:     req.ImageName,
+		BlurHash:      req.BlurHash,
+		DominantColor: req.DominantColor,
+	})
+}
+
+// PatchMonster is use case to partially update monster, absent field is left untouched
+func (uMonster *monsterUseCase) PatchMonster(ctx context.Context, reqId string, version int, req model.PatchMonsterReq) (resCode int, resMessage string, err error) {
+	// mapping req patch monster, null description clear the description
+	value := map[string]interface{}{}
+	if req.Name.Set {
+		value["name"] = req.Name.Value
+	}
+	if req.MonsterCategoryId.Set {
+		value["monster_category_id"] = req.MonsterCategoryId.Value
+	}
+	if req.Description.Set {
+		value["description"] = req.Description.Value
+	}
+	if req.Length.Set {
+		value["length"] = req.Length.Value
+	}
+	if req.Weight.Set {
+		value["weight"] = req.Weight.Value
+	}
+	if req.HP.Set {
+		value["hp"] = req.HP.Value
+	}
+	if req.Attack.Set {
+		value["attack"] = req.Attack.Value
+	}
+	if req.Defends.Set {
+		value["defends"] = req.Defends.Value
+	}
+	if req.Speed.Set {
+		value["speed"] = req.Speed.Value
+	}
+	if req.IsCaught.Set {
+		value["is_caught"] = req.IsCaught.Value
+	}
+	var monsterTypes []string
+	if req.MonsterTypes.Set {
+		monsterTypes = req.MonsterTypes.Value
+	}
+
+	return uMonster.updateMonster(ctx, constants.RevisionActionUpdate, reqId, version, value, monsterTypes, model.MonsterImage{})
+}
+
+// updateMonster is use case to update monster column with given value, monster type is replaced if not nil and primary image is replaced if image name is not empty
+// version is checked and incremented inside the transaction, zero version skip the check, the change is recorded as revision with given action
+func (uMonster *monsterUseCase) updateMonster(ctx context.Context, action, reqId string, version int, value map[string]interface{}, monsterTypes []string, reqImage model.MonsterImage) (resCode int, resMessage string, err error) {
 	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
 	defer cancel()
 
@@ -246,14 +755,15 @@ func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string,
 
 	// query get params
 	queryGetParams := map[string]interface{}{
-		"selectParams": []string{`id, name, monster_category_id, description, length, weight, hp, attack, defends, speed, is_caught, image_name`},
+		"selectParams": []string{`id, name, monster_category_id, description, length, weight, hp, attack, defends, speed, is_caught, image_name, version`},
 		"preloadParams": map[string]interface{}{
-			"MonsterTypes": true,
+			"MonsterTypes":        true,
+			"PrimaryMonsterImage": true,
 		},
 	}
 
-	// find monster by id
-	resMonster, err := uMonster.monsterRepo.GetMonsterById(ctx, reqId, queryGetParams)
+	// find monster by id, monster is read through bulk transaction so change of earlier operation is seen
+	resMonster, err := uMonster.monsterRepo.GetMonsterById(uMonster.bulkTx, ctx, reqId, queryGetParams)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return http.StatusBadRequest, "monster not found", err
@@ -261,53 +771,22 @@ func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string,
 		return http.StatusInternalServerError, "failed to get monster by id", err
 	}
 
+	// check monster version before doing any change
+	if version != 0 && resMonster.Version != version {
+		return http.StatusPreconditionFailed, "monster has been modified", errors.New("monster version does not match")
+	}
+
 	// query update params
 	queryUpdateParams := map[string]interface{}{
-		"value": map[string]interface{}{},
+		"value": value,
 		"whereParams": map[string]interface{}{
 			"default": map[string]interface{}{
 				"id = ?": reqId,
 			},
 		},
 	}
-
-	// mapping req update monster
-	if req.Name != "" {
-		queryUpdateParams["value"].(map[string]interface{})["name"] = req.Name
-	}
-	if req.MonsterCategoryId != "" {
-		queryUpdateParams["value"].(map[string]interface{})["monster_category_id"] = req.MonsterCategoryId
-	}
-	if req.Description != "" {
-		queryUpdateParams["value"].(map[string]interface{})["description"] = req.Description
-	}
-	if req.Length != resMonster.Length {
-		queryUpdateParams["value"].(map[string]interface{})["length"] = req.Length
-	}
-	if req.Weight != resMonster.Weight {
-		queryUpdateParams["value"].(map[string]interface{})["weight"] = req.Weight
-	}
-	if req.HP != resMonster.HP {
-		queryUpdateParams["value"].(map[string]interface{})["hp"] = req.HP
-	}
-	if req.Attack != resMonster.Attack {
-		queryUpdateParams["value"].(map[string]interface{})["attack"] = req.Attack
-	}
-	if req.Defends != resMonster.Defends {
-		queryUpdateParams["value"].(map[string]interface{})["defends"] = req.Defends
-	}
-	if req.Speed != resMonster.Speed {
-		queryUpdateParams["value"].(map[string]interface{})["speed"] = req.Speed
-	}
-	if req.IsCaught != resMonster.IsCaught {
-		queryUpdateParams["value"].(map[string]interface{})["is_caught"] = req.IsCaught
-	}
-	if req.ImageName != "" {
-		queryUpdateParams["value"].(map[string]interface{})["image_name"] = req.ImageName
-		err = uploader.DeleteImage(resMonster.ImageName)
-		if err != nil {
-			return http.StatusInternalServerError, "failed to update monster image", err
-		}
+	if reqImage.ImageName != "" {
+		queryUpdateParams["value"].(map[string]interface{})["image_name"] = reqImage.ImageName
 	}
 
 	// create database transaction
@@ -315,24 +794,48 @@ func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string,
 	if err != nil {
 		return resCode, "failed to create database transaction", err
 	}
-	tx = trx.Begin()
+	tx = uMonster.beginTransaction(trx)
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			uMonster.rollbackTransaction(tx)
+		}
+	}()
 
-	// update monster
-	err = uMonster.monsterRepo.UpdateMonster(tx, ctx, queryUpdateParams)
+	// update monster version, row is locked until transaction end
+	err = uMonster.monsterRepo.UpdateMonsterVersion(tx, ctx, reqId, version)
 	if err != nil {
-		if err.(*pq.Error).Code == "23503" {
-			return http.StatusBadRequest, "invalid monster category", err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusPreconditionFailed, "monster has been modified", err
+		}
+		return http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// find monster snapshot before the change
+	resSnapshot, err := uMonster.monsterRepo.GetMonsterSnapshot(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get monster snapshot", err
+	}
+
+	// update monster
+	if len(value) > 0 {
+		err = uMonster.monsterRepo.UpdateMonster(tx, ctx, queryUpdateParams)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return http.StatusBadRequest, "invalid monster category", err
+			}
+			return http.StatusInternalServerError, "failed to update monster", err
 		}
-		return http.StatusInternalServerError, "failed to update monster", err
 	}
 
 	// mapping req update mapping monster and monster type
 	var reqMonsterAndType []model.MappingMonsterAndTypes
-	if req.MonsterTypes != nil {
-		for i := 0; i < len(req.MonsterTypes); i++ {
+	if monsterTypes != nil {
+		for i := 0; i < len(monsterTypes); i++ {
 			monsterAndType := model.MappingMonsterAndTypes{
 				MonsterId:     reqId,
-				MonsterTypeId: req.MonsterTypes[i],
+				MonsterTypeId: monsterTypes[i],
 			}
 			reqMonsterAndType = append(reqMonsterAndType, monsterAndType)
 		}
@@ -346,20 +849,68 @@ func (uMonster *monsterUseCase) UpdateMonster(ctx context.Context, reqId string,
 		}
 
 		// create mapping monster and monster type
-		err = uMonster.monsterRepo.CreateMappingMonsterAndType(tx, ctx, reqMonsterAndType)
-		if err != nil {
-			if err.(*pq.Error).Code == "23503" {
-				return http.StatusBadRequest, "invalid monster or monster type data", err
+		if len(reqMonsterAndType) > 0 {
+			err = uMonster.monsterRepo.CreateMappingMonsterAndType(tx, ctx, reqMonsterAndType)
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+					return http.StatusBadRequest, "invalid monster or monster type data", err
+				}
+				return http.StatusInternalServerError, "failed to update monster type", err
 			}
-			return http.StatusInternalServerError, "failed to update monster type", err
 		}
 	}
 
+	// sync primary image of monster gallery
+	if reqImage.ImageName != "" {
+		if len(resMonster.MonsterImages) > 0 {
+			err = uMonster.monsterImageRepo.UpdateMonsterImage(tx, ctx, map[string]interface{}{
+				"value": map[string]interface{}{
+					"image_name":     reqImage.ImageName,
+					"blur_hash":      reqImage.BlurHash,
+					"dominant_color": reqImage.DominantColor,
+				},
+				"whereParams": map[string]interface{}{
+					"default": map[string]interface{}{
+						"id = ?": resMonster.MonsterImages[0].ID,
+					},
+				},
+			})
+		} else {
+			_, err = uMonster.monsterImageRepo.CreateMonsterImage(tx, ctx, model.MonsterImage{
+				MonsterId:     reqId,
+				ImageName:     reqImage.ImageName,
+				IsPrimary:     true,
+				VariantTags:   []string{},
+				BlurHash:      reqImage.BlurHash,
+				DominantColor: reqImage.DominantColor,
+			})
+		}
+		if err != nil {
+			return http.StatusInternalServerError, "failed to update monster image", err
+		}
+	}
+
+	// record monster revision
+	err = createMonsterRevision(tx, ctx, uMonster.monsterRepo, uMonster.monsterRevisionRepo, action, &resSnapshot, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to record monster revision", err
+	}
+
 	// commit database transaction
-	err = tx.Commit().Error
+	err = uMonster.commitTransaction(tx)
 	if err != nil {
 		return http.StatusInternalServerError, "failed to commit database transaction", err
 	}
+	isCommitted = true
+
+	// delete replaced image after the change is committed, so failed update never lose the image
+	if reqImage.ImageName != "" && resMonster.ImageName != "" && resMonster.ImageName != reqImage.ImageName {
+		err = uMonster.deleteReplacedImage(resMonster.ImageName)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to delete replaced monster image", err
+		}
+	}
 
 	// mapping response data
 	resCode = http.StatusOK
@@ -391,8 +942,8 @@ func (uMonster *monsterUseCase) UpdateMonsterCaptured(ctx context.Context, reqId
 		"selectParams": []string{`id, is_caught`},
 	}
 
-	// find monster by id
-	resMonster, err := uMonster.monsterRepo.GetMonsterById(ctx, reqId, queryGetParams)
+	// find monster by id, monster is read through bulk transaction so change of earlier operation is seen
+	resMonster, err := uMonster.monsterRepo.GetMonsterById(uMonster.bulkTx, ctx, reqId, queryGetParams)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return http.StatusBadRequest, "monster not found", err
@@ -420,7 +971,19 @@ func (uMonster *monsterUseCase) UpdateMonsterCaptured(ctx context.Context, reqId
 	if err != nil {
 		return resCode, "failed to create database transaction", err
 	}
-	tx = trx.Begin()
+	tx = uMonster.beginTransaction(trx)
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			uMonster.rollbackTransaction(tx)
+		}
+	}()
+
+	// find monster snapshot before the change
+	resSnapshot, err := uMonster.monsterRepo.GetMonsterSnapshot(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get monster snapshot", err
+	}
 
 	// update monster
 	err = uMonster.monsterRepo.UpdateMonster(tx, ctx, queryUpdateParams)
@@ -428,11 +991,25 @@ func (uMonster *monsterUseCase) UpdateMonsterCaptured(ctx context.Context, reqId
 		return http.StatusInternalServerError, "failed to update monster captured mark", err
 	}
 
+	// update monster version and record monster revision
+	if len(queryUpdateParams["value"].(map[string]interface{})) > 0 {
+		err = uMonster.monsterRepo.UpdateMonsterVersion(tx, ctx, reqId, 0)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to update monster version", err
+		}
+
+		err = createMonsterRevision(tx, ctx, uMonster.monsterRepo, uMonster.monsterRevisionRepo, constants.RevisionActionCapture, &resSnapshot, reqId)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to record monster revision", err
+		}
+	}
+
 	// commit database transaction
-	err = tx.Commit().Error
+	err = uMonster.commitTransaction(tx)
 	if err != nil {
 		return http.StatusInternalServerError, "failed to commit database transaction", err
 	}
+	isCommitted = true
 
 	// mapping response data
 	resCode = http.StatusOK
@@ -443,7 +1020,7 @@ func (uMonster *monsterUseCase) UpdateMonsterCaptured(ctx context.Context, reqId
 }
 
 // DeleteMonster is use case to delete monster
-func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error) {
+func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string, version int) (resCode int, resMessage string, err error) {
 	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
 	defer cancel()
 
@@ -461,14 +1038,11 @@ func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string)
 
 	// query get params
 	queryGetParams := map[string]interface{}{
-		"selectParams": []string{`id, image_name`},
-		"preloadParams": map[string]interface{}{
-			"MonsterTypes": true,
-		},
+		"selectParams": []string{`id, version`},
 	}
 
-	// find monster by id
-	resMonster, err := uMonster.monsterRepo.GetMonsterById(ctx, reqId, queryGetParams)
+	// find monster by id, monster is read through bulk transaction so change of earlier operation is seen
+	resMonster, err := uMonster.monsterRepo.GetMonsterById(uMonster.bulkTx, ctx, reqId, queryGetParams)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return http.StatusBadRequest, "monster not found", err
@@ -476,6 +1050,11 @@ func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string)
 		return http.StatusInternalServerError, "failed to get monster by id", err
 	}
 
+	// check monster version before doing any change
+	if version != 0 && resMonster.Version != version {
+		return http.StatusPreconditionFailed, "monster has been modified", errors.New("monster version does not match")
+	}
+
 	// query delete params
 	queryDeleteParams := map[string]interface{}{
 		"whereParams": map[string]interface{}{
@@ -490,35 +1069,47 @@ func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string)
 	if err != nil {
 		return resCode, "failed to create database transaction", err
 	}
-	tx = trx.Begin()
+	tx = uMonster.beginTransaction(trx)
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			uMonster.rollbackTransaction(tx)
+		}
+	}()
 
-	// soft delete monster
-	err = uMonster.monsterRepo.SoftDeleterMonster(tx, ctx, queryDeleteParams)
+	// update monster version, row is locked until transaction end
+	err = uMonster.monsterRepo.UpdateMonsterVersion(tx, ctx, reqId, version)
 	if err != nil {
-		return resCode, "failed to delete monster", err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusPreconditionFailed, "monster has been modified", err
+		}
+		return http.StatusInternalServerError, "failed to update monster version", err
 	}
 
-	// delete image
-	if resMonster.ImageName != "" {
-		err = uploader.DeleteImage(resMonster.ImageName)
-		if err != nil {
-			return http.StatusInternalServerError, "failed to delete monster image", err
-		}
+	// find monster snapshot before the change
+	resSnapshot, err := uMonster.monsterRepo.GetMonsterSnapshot(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get monster snapshot", err
 	}
 
-	// delete mapping monster and monster type if exist
-	if resMonster.MonsterTypes != nil {
-		err = uMonster.monsterRepo.DeleteMappingMonsterAndType(tx, ctx, reqId)
-		if err != nil {
-			return http.StatusInternalServerError, "failed to update monster type", err
-		}
+	// soft delete monster, image and monster type are kept so monster can be restored from trash
+	err = uMonster.monsterRepo.SoftDeleterMonster(tx, ctx, queryDeleteParams)
+	if err != nil {
+		return resCode, "failed to delete monster", err
+	}
+
+	// record monster revision
+	err = createMonsterRevision(tx, ctx, uMonster.monsterRepo, uMonster.monsterRevisionRepo, constants.RevisionActionDelete, &resSnapshot, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to record monster revision", err
 	}
 
 	// commit database transaction
-	err = tx.Commit().Error
+	err = uMonster.commitTransaction(tx)
 	if err != nil {
 		return http.StatusInternalServerError, "failed to commit database transaction", err
 	}
+	isCommitted = true
 
 	// mapping response data
 	resCode = http.StatusOK
@@ -527,3 +1118,485 @@ func (uMonster *monsterUseCase) DeleteMonster(ctx context.Context, reqId string)
 
 	return resCode, resMessage, err
 }
+
+// GetMonsterHistory is use case to get list revision of monster, newest revision first
+func (uMonster *monsterUseCase) GetMonsterHistory(ctx context.Context, reqId string, page, perPage int) (res []model.MonsterRevisionRes, count int64, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonster.ctxTimeout)
+	defer cancel()
+
+	// find list monster revision
+	resRevision, count, err := uMonster.monsterRevisionRepo.GetListMonsterRevision(ctx, reqId, page, perPage)
+	if err != nil {
+		return nil, 0, http.StatusInternalServerError, "failed to get monster history", err
+	}
+
+	// mapping response data
+	res = []model.MonsterRevisionRes{}
+	for i := 0; i < len(resRevision); i++ {
+		revision := model.MonsterRevisionRes{
+			ID:        resRevision[i].ID,
+			MonsterId: resRevision[i].MonsterId,
+			Version:   resRevision[i].Version,
+			Action:    resRevision[i].Action,
+			ActorId:   resRevision[i].ActorId,
+			ActorName: resRevision[i].ActorName,
+			CreatedAt: resRevision[i].CreatedAt,
+		}
+		err = json.Unmarshal([]byte(resRevision[i].Diff), &revision.Diff)
+		if err != nil {
+			return nil, 0, http.StatusInternalServerError, "failed to read monster revision", err
+		}
+		err = json.Unmarshal([]byte(resRevision[i].Snapshot), &revision.Snapshot)
+		if err != nil {
+			return nil, 0, http.StatusInternalServerError, "failed to read monster revision", err
+		}
+		res = append(res, revision)
+	}
+
+	return res, count, http.StatusOK, "get monster history successfully", nil
+}
+
+// RevertMonster is use case to restore monster field and monster type to its state at given revision, the revert is recorded as new revision
+// monster code and image are not reverted, image file of old revision may already be replaced
+func (uMonster *monsterUseCase) RevertMonster(ctx context.Context, reqId, revisionId string, version int) (resCode int, resMessage string, err error) {
+	// find monster revision by id
+	resRevision, err := uMonster.monsterRevisionRepo.GetMonsterRevisionById(ctx, reqId, revisionId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "monster revision not found", err
+		}
+		return http.StatusInternalServerError, "failed to get monster revision", err
+	}
+	var snapshot model.MonsterSnapshot
+	err = json.Unmarshal([]byte(resRevision.Snapshot), &snapshot)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to read monster revision", err
+	}
+	if snapshot.IsDeleted {
+		return http.StatusBadRequest, "cannot revert monster to deleted revision", errors.New("revision is deleted state of monster")
+	}
+
+	// mapping req update monster from revision snapshot
+	value := map[string]interface{}{
+		"name":                snapshot.Name,
+		"monster_category_id": snapshot.MonsterCategoryId,
+		"description":         snapshot.Description,
+		"length":              snapshot.Length,
+		"weight":              snapshot.Weight,
+		"hp":                  snapshot.HP,
+		"attack":              snapshot.Attack,
+		"defends":             snapshot.Defends,
+		"speed":               snapshot.Speed,
+		"is_caught":           snapshot.IsCaught,
+	}
+	monsterTypes := []string(snapshot.MonsterTypes)
+	if monsterTypes == nil {
+		monsterTypes = []string{}
+	}
+
+	resCode, resMessage, err = uMonster.updateMonster(ctx, constants.RevisionActionRevert, reqId, version, value, monsterTypes, model.MonsterImage{})
+	if err != nil {
+		return resCode, resMessage, err
+	}
+
+	return http.StatusOK, "revert monster successfully", nil
+}
+
+// BulkMonster is use case to apply list of create, update and delete monster operation by reusing single monster use case
+// all operation is applied in one transaction which is rolled back on first failure, or each operation is applied on its own if continue on error
+func (uMonster *monsterUseCase) BulkMonster(ctx context.Context, req []model.BulkMonsterOperation, continueOnError bool) (res []model.BulkMonsterResultRes, resCode int, resMessage string, err error) {
+	res = []model.BulkMonsterResultRes{}
+
+	// apply each operation with its own transaction, failed operation does not stop the rest
+	if continueOnError {
+		failed := 0
+		for i := 0; i < len(req); i++ {
+			result := uMonster.applyBulkOperation(ctx, req[i])
+			if result.Error != "" {
+				failed++
+			}
+			res = append(res, result)
+		}
+		if failed > 0 {
+			return res, http.StatusMultiStatus, fmt.Sprintf("%d of %d operation failed", failed, len(req)), nil
+		}
+
+		return res, http.StatusOK, "bulk monster successfully", nil
+	}
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed bulk monster"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// create database transaction
+	trx, resCode, err := uMonster.monsterRepo.Transaction()
+	if err != nil {
+		return res, resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+
+	// bind copy of use case to bulk transaction, every operation joins it
+	bulkUseCase := *uMonster
+	bulkUseCase.bulkTx = tx
+	bulkUseCase.bulkReplacedImages = &[]string{}
+
+	// apply operation until the first failure, the rest is not applied
+	failedIndex := -1
+	for i := 0; i < len(req); i++ {
+		if failedIndex >= 0 {
+			res = append(res, model.BulkMonsterResultRes{
+				Index:   req[i].Index,
+				Op:      req[i].Op,
+				ID:      req[i].ID,
+				Code:    http.StatusFailedDependency,
+				Message: "operation is not applied",
+				Error:   "previous operation failed",
+			})
+			continue
+		}
+		result := bulkUseCase.applyBulkOperation(ctx, req[i])
+		if result.Error != "" {
+			failedIndex = i
+		}
+		res = append(res, result)
+	}
+
+	// rollback database transaction, operation before the failure is undone
+	if failedIndex >= 0 {
+		tx.Rollback()
+		for i := 0; i < failedIndex; i++ {
+			res[i].Code = http.StatusFailedDependency
+			res[i].Message = "operation is rolled back"
+		}
+		return res, res[failedIndex].Code, "bulk monster failed, no operation is applied", fmt.Errorf("operation %d: %s", res[failedIndex].Index, res[failedIndex].Error)
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+
+	// delete image replaced by committed operation
+	for _, imageName := range *bulkUseCase.bulkReplacedImages {
+		_ = uploader.DeleteImage(imageName)
+	}
+
+	return res, http.StatusOK, "bulk monster successfully", nil
+}
+
+// applyBulkOperation is function to apply single bulk operation with monster use case
+func (uMonster *monsterUseCase) applyBulkOperation(ctx context.Context, op model.BulkMonsterOperation) (res model.BulkMonsterResultRes) {
+	res = model.BulkMonsterResultRes{
+		Index: op.Index,
+		Op:    op.Op,
+		ID:    op.ID,
+	}
+
+	var err error
+	switch {
+	case op.InvalidReason != "":
+		res.Code, res.Message, err = http.StatusBadRequest, "data input is invalid", errors.New(op.InvalidReason)
+	case (op.Op == constants.BulkOperationUpdate || op.Op == constants.BulkOperationDelete) && op.Version < 1:
+		res.Code, res.Message, err = http.StatusPreconditionRequired, "monster version is required", errors.New("version is required on update and delete")
+	case op.Op == constants.BulkOperationCreate:
+		res.Code, res.Message, err = uMonster.CreateMonster(ctx, op.CreateReq)
+	case op.Op == constants.BulkOperationUpdate:
+		res.Code, res.Message, err = uMonster.UpdateMonster(ctx, op.ID, op.Version, op.UpdateReq)
+	case op.Op == constants.BulkOperationDelete:
+		res.Code, res.Message, err = uMonster.DeleteMonster(ctx, op.ID, op.Version)
+	default:
+		res.Code, res.Message, err = http.StatusBadRequest, "operation not valid", fmt.Errorf("operation %q is not supported", op.Op)
+	}
+	if err != nil {
+		res.Error = err.Error()
+	}
+
+	return res
+}
+
+// beginTransaction is function to begin database transaction, use case which is bound to bulk transaction joins it instead
+func (uMonster *monsterUseCase) beginTransaction(trx *gorm.DB) *gorm.DB {
+	if uMonster.bulkTx != nil {
+		return uMonster.bulkTx
+	}
+
+	return trx.Begin()
+}
+
+// commitTransaction is function to commit database transaction, bulk transaction is committed by bulk use case
+func (uMonster *monsterUseCase) commitTransaction(tx *gorm.DB) error {
+	if uMonster.bulkTx != nil {
+		return nil
+	}
+
+	return tx.Commit().Error
+}
+
+// rollbackTransaction is function to rollback database transaction which is not committed, bulk transaction is rolled back
+// by bulk use case
+func (uMonster *monsterUseCase) rollbackTransaction(tx *gorm.DB) {
+	if uMonster.bulkTx != nil {
+		return
+	}
+
+	tx.Rollback()
+}
+
+// deleteReplacedImage is function to delete image file which is replaced by committed change, image replaced inside bulk
+// transaction is kept until bulk transaction is committed
+func (uMonster *monsterUseCase) deleteReplacedImage(imageName string) error {
+	if uMonster.bulkTx != nil {
+		*uMonster.bulkReplacedImages = append(*uMonster.bulkReplacedImages, imageName)
+		return nil
+	}
+
+	return uploader.DeleteImage(imageName)
+}
+
+// monsterOrder is function to build order of list monster from whitelisted sort field, relevance is expression of search rank
+// list is sorted by relevance when search query is given and by created date otherwise, monster id is the last key so order is stable
+func monsterOrder(queryReq model.MonsterQueryReq, relevance clause.Expr) (res clause.Expr, err error) {
+	sorts := queryReq.Sort
+	if len(sorts) == 0 && queryReq.Q != "" {
+		sorts = []form.Sort{{Field: constants.SortByRelevance, Desc: true}}
+	} else if len(sorts) == 0 {
+		sorts = []form.Sort{{Field: "created_at"}}
+	}
+
+	var orders []string
+	for i := 0; i < len(sorts); i++ {
+		column, ok := MonsterSortFields[sorts[i].Field]
+		if !ok {
+			return res, fmt.Errorf("sort field %q is not valid", sorts[i].Field)
+		}
+		if sorts[i].Field == constants.SortByRelevance {
+			if queryReq.Q == "" {
+				return res, errors.New("sort by relevance requires q")
+			}
+			column = relevance.SQL
+			res.Vars = append(res.Vars, relevance.Vars...)
+		}
+		direction := "ASC"
+		if sorts[i].Desc {
+			direction = "DESC"
+		}
+		orders = append(orders, column+" "+direction)
+	}
+	res.SQL = strings.Join(append(orders, "monsters.id ASC"), ", ")
+	res.WithoutParentheses = true
+
+	return res, nil
+}
+
+// monsterQueryNormalization is function to default monster type matching to any-of, and to remove duplicate monster type
+// so all-of matching counts every type once
+func monsterQueryNormalization(queryReq model.MonsterQueryReq) (res model.MonsterQueryReq) {
+	if queryReq.MonsterTypeMatch == "" {
+		queryReq.MonsterTypeMatch = constants.MonsterTypeMatchAny
+	}
+	var monsterTypeId []string
+	isExist := map[string]bool{}
+	for i := 0; i < len(queryReq.MonsterTypeId); i++ {
+		if !isExist[queryReq.MonsterTypeId[i]] {
+			isExist[queryReq.MonsterTypeId[i]] = true
+			monsterTypeId = append(monsterTypeId, queryReq.MonsterTypeId[i])
+		}
+	}
+	queryReq.MonsterTypeId = monsterTypeId
+
+	return queryReq
+}
+
+// monsterListSelect is function to get selected column and preload of list monster from whitelisted sparse field and include,
+// include defaults to category and types only when neither field nor include is asked for
+func monsterListSelect(queryReq model.MonsterQueryReq) (columns []string, preloadParams map[string]interface{}, isField map[string]bool, err error) {
+	fields, includes := monsterListFieldsOf(queryReq)
+	columns = []string{"monsters.id"}
+	preloadParams = map[string]interface{}{}
+	isField = map[string]bool{}
+	isSelected := map[string]bool{"monsters.id": true}
+	selectField := func(field monsterField) {
+		for i := 0; i < len(field.columns); i++ {
+			if !isSelected[field.columns[i]] {
+				isSelected[field.columns[i]] = true
+				columns = append(columns, field.columns[i])
+			}
+		}
+		if field.preload != "" {
+			preloadParams[field.preload] = true
+		}
+	}
+
+	for i := 0; i < len(fields); i++ {
+		field, ok := monsterListFields[fields[i]]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("field %q is not valid", fields[i])
+		}
+		isField[fields[i]] = true
+		selectField(field)
+	}
+	for i := 0; i < len(includes); i++ {
+		if includes[i] == "evolutions" {
+			return nil, nil, nil, errors.New("include evolutions is not supported, evolution of monster is not recorded")
+		}
+		include, ok := monsterListIncludes[includes[i]]
+		if !ok {
+			return nil, nil, nil, fmt.Errorf("include %q is not valid", includes[i])
+		}
+		selectField(include)
+	}
+
+	return columns, preloadParams, isField, nil
+}
+
+// monsterListFieldsOf is function to get sparse field and include of list monster with its default
+func monsterListFieldsOf(queryReq model.MonsterQueryReq) (fields, includes []string) {
+	fields, includes = queryReq.Fields, queryReq.Include
+	if len(fields) == 0 {
+		fields = monsterListDefaultFields
+	}
+	if len(queryReq.Fields) == 0 && len(includes) == 0 {
+		includes = monsterListDefaultIncludes
+	}
+
+	return fields, includes
+}
+
+// MonsterListKeys is function to get JSON key of list monster response asked for by sparse field and include, nil means
+// every key since neither is asked for, search rank and highlight are kept since they are only filled by search query
+func MonsterListKeys(queryReq model.MonsterQueryReq) (keys map[string]bool) {
+	if len(queryReq.Fields) == 0 && len(queryReq.Include) == 0 {
+		return nil
+	}
+	fields, includes := monsterListFieldsOf(queryReq)
+	keys = map[string]bool{"id": true, "search_rank": true, "highlight": true}
+	for i := 0; i < len(fields); i++ {
+		for _, key := range monsterListFields[fields[i]].keys {
+			keys[key] = true
+		}
+	}
+	for i := 0; i < len(includes); i++ {
+		for _, key := range monsterListIncludes[includes[i]].keys {
+			keys[key] = true
+		}
+	}
+
+	return keys
+}
+
+// monsterListParams is function to get where params of list monster, list monster and its facet share the same condition
+func monsterListParams(queryReq model.MonsterQueryReq) (queryGetParams map[string]interface{}, resCode int, resMessage string, err error) {
+	queryGetParams = map[string]interface{}{
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{},
+			"in":      map[string]interface{}{},
+		},
+	}
+	if queryReq.Name != "" {
+		condition, args := monsterNameCondition(queryReq.Name)
+		queryGetParams["whereParams"].(map[string]interface{})["default"].(map[string]interface{})[condition] = args
+	}
+	if queryReq.Q != "" {
+		// name and description are searched by weighted search vector
+		queryGetParams["whereParams"].(map[string]interface{})["default"].(map[string]interface{})[fmt.Sprintf("monsters.search_vector @@ websearch_to_tsquery('%s', ?)", constants.SearchConfig)] = queryReq.Q
+	}
+	if queryReq.IsCaught != nil {
+		queryGetParams["whereParams"].(map[string]interface{})["default"].(map[string]interface{})["monsters.is_caught = ?"] = *queryReq.IsCaught
+	}
+	if queryReq.Filter != "" {
+		sql, args, err := filter.Compile(queryReq.Filter, MonsterFilterFields)
+		if err != nil {
+			return nil, http.StatusBadRequest, "filter expression not valid", err
+		}
+		queryGetParams["filterParams"] = gorm.Expr(sql, args...)
+	}
+
+	return queryGetParams, http.StatusOK, "", nil
+}
+
+// similarMonsterProfile is function to get similarity profile of monster, monster types must be preloaded
+func similarMonsterProfile(monster model.Monster) similarity.Profile {
+	profile := similarity.Profile{
+		Stat: similarity.Stat{
+			HP:      float64(monster.HP),
+			Attack:  float64(monster.Attack),
+			Defends: float64(monster.Defends),
+			Speed:   float64(monster.Speed),
+		},
+		CategoryId: monster.MonsterCategoryId,
+	}
+	for i := 0; i < len(monster.MonsterTypes); i++ {
+		profile.TypeIds = append(profile.TypeIds, monster.MonsterTypes[i].ID)
+	}
+
+	return profile
+}
+
+// monsterNameCondition is function to get condition of name filter, name matches as substring or as similar name by trigram so typo is tolerated
+func monsterNameCondition(name string) (condition string, args map[string]interface{}) {
+	condition = `(lower(monsters.name) LIKE lower(@like) OR (lower(monsters.name) % lower(@name) AND similarity(lower(monsters.name), lower(@name)) >= @threshold))`
+	args = map[string]interface{}{
+		"like":      "%" + search.EscapeLike(name) + "%",
+		"name":      name,
+		"threshold": constants.SearchSimilarityThreshold,
+	}
+
+	return condition, args
+}
+
+// createMonsterRevision is function to record revision of monster with field level diff between snapshot before and after the action, nil before means monster is just created
+func createMonsterRevision(tx *gorm.DB, ctx context.Context, monsterRepo repository.MonsterRepositoryInterface, monsterRevisionRepo repository.MonsterRevisionRepositoryInterface, action string, before *model.MonsterSnapshot, monsterId string) (err error) {
+	// find monster snapshot after the action
+	after, err := monsterRepo.GetMonsterSnapshot(tx, ctx, monsterId)
+	if err != nil {
+		return err
+	}
+
+	// compare monster snapshot
+	changes, err := diff.Compare(before, after)
+	if err != nil {
+		return err
+	}
+	diffJSON, err := json.Marshal(changes)
+	if err != nil {
+		return err
+	}
+	snapshotJSON, err := json.Marshal(after)
+	if err != nil {
+		return err
+	}
+
+	// create monster revision
+	err = monsterRevisionRepo.CreateMonsterRevision(tx, ctx, model.MonsterRevision{
+		MonsterId: monsterId,
+		Version:   after.Version,
+		Action:    action,
+		ActorId:   actorId(ctx),
+		Diff:      string(diffJSON),
+		Snapshot:  string(snapshotJSON),
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// actorId is function to get authenticated user id from request context, nil if request is not authenticated
+func actorId(ctx context.Context) *string {
+	id, ok := ctx.Value(constants.ActorIdKey).(string)
+	if !ok || id == "" {
+		return nil
+	}
+
+	return &id
+}
@@ -11,6 +11,7 @@ import (
 // MCategoryUseCaseInterface is
 type MCategoryUseCaseInterface interface {
 	GetAllMonsterCategory(ctx context.Context) (res []model.MonsterCategory, resCode int, resMessage string, err error)
+	GetMonsterCategoryFingerprint(ctx context.Context) (res model.Fingerprint, resCode int, resMessage string, err error)
 }
 
 type mCategoryUseCase struct {
@@ -38,3 +39,17 @@ func (uMCategory *mCategoryUseCase) GetAllMonsterCategory(ctx context.Context) (
 
 	return res, http.StatusOK, "get all monster category successfully", nil
 }
+
+// GetMonsterCategoryFingerprint is use case to get fingerprint of all monster category for conditional request
+func (uMCategory *mCategoryUseCase) GetMonsterCategoryFingerprint(ctx context.Context) (res model.Fingerprint, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMCategory.ctxTimeout)
+	defer cancel()
+
+	// find monster category fingerprint
+	res, err = uMCategory.mCategoryRepo.GetMonsterCategoryFingerprint(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get monster category fingerprint", err
+	}
+
+	return res, http.StatusOK, "get monster category fingerprint successfully", nil
+}
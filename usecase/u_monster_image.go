@@ -0,0 +1,682 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// MonsterImageUseCaseInterface is
+type MonsterImageUseCaseInterface interface {
+	CreateMonsterImage(ctx context.Context, monsterId string, req model.CreateMonsterImageReq) (res model.MonsterImageRes, resCode int, resMessage string, err error)
+	ReorderMonsterImage(ctx context.Context, monsterId string, req model.ReorderMonsterImageReq) (resCode int, resMessage string, err error)
+	SetPrimaryMonsterImage(ctx context.Context, monsterId, reqId string) (resCode int, resMessage string, err error)
+	UpdateMonsterImageVisibility(ctx context.Context, monsterId, reqId string, req model.UpdateMonsterImageVisibilityReq) (resCode int, resMessage string, err error)
+	DeleteMonsterImage(ctx context.Context, monsterId, reqId string) (resCode int, resMessage string, err error)
+	GetMonsterImageFile(ctx context.Context, imageName, expires, signature string) (res model.MonsterImage, resCode int, resMessage string, err error)
+	BackfillImagePlaceholder(ctx context.Context, batchSize int) (res model.ImagePlaceholderBackfillRes, resCode int, resMessage string, err error)
+}
+
+type monsterImageUseCase struct {
+	ctxTimeout          time.Duration
+	imageURL            uploader.ImageURL
+	monsterRepo         repository.MonsterRepositoryInterface
+	monsterImageRepo    repository.MonsterImageRepositoryInterface
+	monsterRevisionRepo repository.MonsterRevisionRepositoryInterface
+}
+
+func NewMonsterImageUseCase(ctxTimeout time.Duration, imageURL uploader.ImageURL, monsterRepo repository.MonsterRepositoryInterface, monsterImageRepo repository.MonsterImageRepositoryInterface, monsterRevisionRepo repository.MonsterRevisionRepositoryInterface) MonsterImageUseCaseInterface {
+	return &monsterImageUseCase{
+		ctxTimeout:          ctxTimeout,
+		imageURL:            imageURL,
+		monsterRepo:         monsterRepo,
+		monsterImageRepo:    monsterImageRepo,
+		monsterRevisionRepo: monsterRevisionRepo,
+	}
+}
+
+// CreateMonsterImage is use case to add image into monster gallery
+func (uMonsterImage *monsterImageUseCase) CreateMonsterImage(ctx context.Context, monsterId string, req model.CreateMonsterImageReq) (res model.MonsterImageRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed create monster image"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find monster by id
+	_, err = uMonsterImage.monsterRepo.GetMonsterById(nil, ctx, monsterId, map[string]interface{}{
+		"selectParams": []string{`id`},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, http.StatusBadRequest, "monster not found", err
+		}
+		return res, http.StatusInternalServerError, "failed to get monster by id", err
+	}
+
+	// find monster gallery
+	resMonsterImage, err := uMonsterImage.monsterImageRepo.GetListMonsterImage(nil, ctx, monsterId)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get list monster image", err
+	}
+	sortOrder := 0
+	for i := 0; i < len(resMonsterImage); i++ {
+		if resMonsterImage[i].SortOrder >= sortOrder {
+			sortOrder = resMonsterImage[i].SortOrder + 1
+		}
+	}
+
+	// mapping req create monster image, first image of gallery always become primary image
+	reqMonsterImage := model.MonsterImage{
+		MonsterId:     monsterId,
+		ImageName:     req.ImageName,
+		Caption:       req.Caption,
+		SortOrder:     sortOrder,
+		IsPrimary:     req.IsPrimary || len(resMonsterImage) == 0,
+		IsPrivate:     req.IsPrivate,
+		VariantTags:   req.VariantTags,
+		BlurHash:      req.BlurHash,
+		DominantColor: req.DominantColor,
+	}
+	if reqMonsterImage.VariantTags == nil {
+		reqMonsterImage.VariantTags = []string{}
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterImage.monsterRepo.Transaction()
+	if err != nil {
+		return res, resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster version, row is locked until transaction end
+	err = uMonsterImage.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// unset current primary image
+	if reqMonsterImage.IsPrimary {
+		err = uMonsterImage.unsetPrimaryMonsterImage(tx, ctx, monsterId)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to update primary monster image", err
+		}
+	}
+
+	// create monster image
+	monsterImageId, err := uMonsterImage.monsterImageRepo.CreateMonsterImage(tx, ctx, reqMonsterImage)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to create monster image", err
+	}
+
+	// sync monster image with primary image
+	if reqMonsterImage.IsPrimary {
+		err = uMonsterImage.syncMonsterImageName(tx, ctx, monsterId, reqMonsterImage.ImageName)
+		if err != nil {
+			return res, http.StatusInternalServerError, "failed to update monster image", err
+		}
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// mapping response data
+	reqMonsterImage.ID = monsterImageId
+	res = monsterImageRes(uMonsterImage.imageURL, reqMonsterImage)
+	resCode = http.StatusCreated
+	resMessage = "create monster image successfully"
+	err = nil
+
+	return res, resCode, resMessage, err
+}
+
+// ReorderMonsterImage is use case to reorder monster gallery based on given image id order
+func (uMonsterImage *monsterImageUseCase) ReorderMonsterImage(ctx context.Context, monsterId string, req model.ReorderMonsterImageReq) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed reorder monster image"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find monster by id
+	_, err = uMonsterImage.monsterRepo.GetMonsterById(nil, ctx, monsterId, map[string]interface{}{
+		"selectParams": []string{`id`},
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusBadRequest, "monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to get monster by id", err
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterImage.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster version, row is locked until transaction end so gallery cannot change while it is reordered
+	err = uMonsterImage.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusBadRequest, "monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// find monster gallery
+	resMonsterImage, err := uMonsterImage.monsterImageRepo.GetListMonsterImage(tx, ctx, monsterId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get list monster image", err
+	}
+
+	// image id must contain every image of the gallery exactly once
+	existImage := map[string]bool{}
+	for i := 0; i < len(resMonsterImage); i++ {
+		existImage[resMonsterImage[i].ID] = true
+	}
+	if len(req.ImageIds) != len(existImage) {
+		return http.StatusBadRequest, "image order is invalid", errors.New("image_ids must contain every image of the monster")
+	}
+	for i := 0; i < len(req.ImageIds); i++ {
+		if !existImage[req.ImageIds[i]] {
+			return http.StatusBadRequest, "image order is invalid", fmt.Errorf("image %s is not found or duplicated", req.ImageIds[i])
+		}
+		delete(existImage, req.ImageIds[i])
+	}
+
+	// update sort order
+	for i := 0; i < len(req.ImageIds); i++ {
+		err = uMonsterImage.monsterImageRepo.UpdateMonsterImage(tx, ctx, map[string]interface{}{
+			"value": map[string]interface{}{
+				"sort_order": i,
+			},
+			"whereParams": map[string]interface{}{
+				"default": map[string]interface{}{
+					"id = ?": req.ImageIds[i],
+				},
+			},
+		})
+		if err != nil {
+			return http.StatusInternalServerError, "failed to reorder monster image", err
+		}
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "reorder monster image successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// SetPrimaryMonsterImage is use case to set primary image of monster gallery
+func (uMonsterImage *monsterImageUseCase) SetPrimaryMonsterImage(ctx context.Context, monsterId, reqId string) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed set primary monster image"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find monster image by id
+	resMonsterImage, err := uMonsterImage.monsterImageRepo.GetMonsterImageById(ctx, monsterId, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusBadRequest, "monster image not found", err
+		}
+		return http.StatusInternalServerError, "failed to get monster image by id", err
+	}
+	if resMonsterImage.IsPrimary {
+		return http.StatusOK, "set primary monster image successfully", nil
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterImage.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster version, row is locked until transaction end
+	err = uMonsterImage.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// swap primary image
+	err = uMonsterImage.unsetPrimaryMonsterImage(tx, ctx, monsterId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update primary monster image", err
+	}
+	err = uMonsterImage.setPrimaryMonsterImage(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update primary monster image", err
+	}
+
+	// sync monster image with primary image
+	err = uMonsterImage.syncMonsterImageName(tx, ctx, monsterId, resMonsterImage.ImageName)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update monster image", err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "set primary monster image successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// UpdateMonsterImageVisibility is use case to publish or hold back image of monster gallery
+func (uMonsterImage *monsterImageUseCase) UpdateMonsterImageVisibility(ctx context.Context, monsterId, reqId string, req model.UpdateMonsterImageVisibilityReq) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed update monster image visibility"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find monster image by id
+	_, err = uMonsterImage.monsterImageRepo.GetMonsterImageById(ctx, monsterId, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusBadRequest, "monster image not found", err
+		}
+		return http.StatusInternalServerError, "failed to get monster image by id", err
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterImage.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster image visibility
+	err = uMonsterImage.monsterImageRepo.UpdateMonsterImage(tx, ctx, map[string]interface{}{
+		"value": map[string]interface{}{
+			"is_private": req.IsPrivate,
+		},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"id = ?": reqId,
+			},
+		},
+	})
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update monster image visibility", err
+	}
+
+	// update monster version
+	err = uMonsterImage.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "update monster image visibility successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// DeleteMonsterImage is use case to delete image from monster gallery
+func (uMonsterImage *monsterImageUseCase) DeleteMonsterImage(ctx context.Context, monsterId, reqId string) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed delete monster image"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find monster gallery
+	resMonsterImage, err := uMonsterImage.monsterImageRepo.GetListMonsterImage(nil, ctx, monsterId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get list monster image", err
+	}
+	var deletedImage *model.MonsterImage
+	var nextPrimaryImage *model.MonsterImage
+	for i := 0; i < len(resMonsterImage); i++ {
+		if resMonsterImage[i].ID == reqId {
+			deletedImage = &resMonsterImage[i]
+		} else if nextPrimaryImage == nil {
+			nextPrimaryImage = &resMonsterImage[i]
+		}
+	}
+	if deletedImage == nil {
+		return http.StatusBadRequest, "monster image not found", gorm.ErrRecordNotFound
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterImage.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// update monster version, row is locked until transaction end
+	err = uMonsterImage.monsterRepo.UpdateMonsterVersion(tx, ctx, monsterId, 0)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to update monster version", err
+	}
+
+	// delete monster image
+	err = uMonsterImage.monsterImageRepo.DeleteMonsterImage(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to delete monster image", err
+	}
+
+	// promote next image as primary image
+	if deletedImage.IsPrimary {
+		nextImageName := ""
+		if nextPrimaryImage != nil {
+			err = uMonsterImage.setPrimaryMonsterImage(tx, ctx, nextPrimaryImage.ID)
+			if err != nil {
+				return http.StatusInternalServerError, "failed to update primary monster image", err
+			}
+			nextImageName = nextPrimaryImage.ImageName
+		}
+		err = uMonsterImage.syncMonsterImageName(tx, ctx, monsterId, nextImageName)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to update monster image", err
+		}
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// delete image after the row is gone, so rollback never lose the image
+	err = uploader.DeleteImage(deletedImage.ImageName)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to delete monster image file", err
+	}
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "delete monster image successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// GetMonsterImageFile is use case to get image to be served, private image require valid signed url
+func (uMonsterImage *monsterImageUseCase) GetMonsterImageFile(ctx context.Context, imageName, expires, signature string) (res model.MonsterImage, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+	defer cancel()
+
+	// find monster image by name
+	res, err = uMonsterImage.monsterImageRepo.GetMonsterImageByName(ctx, imageName)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, http.StatusNotFound, "image not found", err
+		}
+		return res, http.StatusInternalServerError, "failed to get monster image by name", err
+	}
+
+	// signed url validation
+	if res.IsPrivate {
+		err = uMonsterImage.imageURL.Verify(imageName, expires, signature)
+		if err != nil {
+			return res, http.StatusForbidden, "image url is not valid", err
+		}
+	}
+
+	return res, http.StatusOK, "get monster image successfully", nil
+}
+
+// BackfillImagePlaceholder is use case to compute BlurHash and dominant color of existing monster image in batch
+func (uMonsterImage *monsterImageUseCase) BackfillImagePlaceholder(ctx context.Context, batchSize int) (res model.ImagePlaceholderBackfillRes, resCode int, resMessage string, err error) {
+	res.Failed = []model.ImagePlaceholderFailure{}
+	lastId := ""
+	for {
+		// each batch has its own timeout, backfill may take longer than single request
+		batchCtx, cancel := context.WithTimeout(ctx, uMonsterImage.ctxTimeout)
+
+		// find batch of monster image without placeholder
+		resMonsterImage, err := uMonsterImage.monsterImageRepo.GetListMonsterImageWithoutPlaceholder(batchCtx, lastId, batchSize)
+		if err != nil {
+			cancel()
+			return res, http.StatusInternalServerError, "failed to get list monster image", err
+		}
+		if len(resMonsterImage) == 0 {
+			cancel()
+			break
+		}
+
+		for i := 0; i < len(resMonsterImage); i++ {
+			res.Total++
+
+			// compute image placeholder, failed image is reported and skipped
+			blurHash, dominantColor, err := uploader.ImagePlaceholder(resMonsterImage[i].ImageName)
+			if err != nil {
+				res.Failed = append(res.Failed, model.ImagePlaceholderFailure{
+					ID:        resMonsterImage[i].ID,
+					ImageName: resMonsterImage[i].ImageName,
+					Reason:    err.Error(),
+				})
+				continue
+			}
+
+			// update monster image
+			err = uMonsterImage.monsterImageRepo.UpdateMonsterImage(nil, batchCtx, map[string]interface{}{
+				"value": map[string]interface{}{
+					"blur_hash":      blurHash,
+					"dominant_color": dominantColor,
+				},
+				"whereParams": map[string]interface{}{
+					"default": map[string]interface{}{
+						"id = ?": resMonsterImage[i].ID,
+					},
+				},
+			})
+			if err != nil {
+				cancel()
+				return res, http.StatusInternalServerError, "failed to update monster image", err
+			}
+
+			// update monster version, soft deleted monster has no version to update
+			err = uMonsterImage.monsterRepo.UpdateMonsterVersion(nil, batchCtx, resMonsterImage[i].MonsterId, 0)
+			if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+				cancel()
+				return res, http.StatusInternalServerError, "failed to update monster version", err
+			}
+			res.Updated++
+		}
+		cancel()
+
+		lastId = resMonsterImage[len(resMonsterImage)-1].ID
+	}
+
+	return res, http.StatusOK, "backfill image placeholder successfully", nil
+}
+
+// unsetPrimaryMonsterImage is
+func (uMonsterImage *monsterImageUseCase) unsetPrimaryMonsterImage(tx *gorm.DB, ctx context.Context, monsterId string) (err error) {
+	return uMonsterImage.monsterImageRepo.UpdateMonsterImage(tx, ctx, map[string]interface{}{
+		"value": map[string]interface{}{
+			"is_primary": false,
+		},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"monster_id = ?": monsterId,
+				"is_primary = ?": true,
+			},
+		},
+	})
+}
+
+// setPrimaryMonsterImage is
+func (uMonsterImage *monsterImageUseCase) setPrimaryMonsterImage(tx *gorm.DB, ctx context.Context, reqId string) (err error) {
+	return uMonsterImage.monsterImageRepo.UpdateMonsterImage(tx, ctx, map[string]interface{}{
+		"value": map[string]interface{}{
+			"is_primary": true,
+		},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"id = ?": reqId,
+			},
+		},
+	})
+}
+
+// syncMonsterImageName is function to sync monster image with primary image of gallery, the change is recorded as revision
+// so monster version must already be updated inside the transaction
+func (uMonsterImage *monsterImageUseCase) syncMonsterImageName(tx *gorm.DB, ctx context.Context, monsterId, imageName string) (err error) {
+	// find monster snapshot before the change
+	resSnapshot, err := uMonsterImage.monsterRepo.GetMonsterSnapshot(tx, ctx, monsterId)
+	if err != nil {
+		return err
+	}
+	if resSnapshot.ImageName == imageName {
+		return nil
+	}
+
+	// update monster image
+	err = uMonsterImage.monsterRepo.UpdateMonster(tx, ctx, map[string]interface{}{
+		"value": map[string]interface{}{
+			"image_name": imageName,
+		},
+		"whereParams": map[string]interface{}{
+			"default": map[string]interface{}{
+				"id = ?": monsterId,
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// record monster revision
+	return createMonsterRevision(tx, ctx, uMonsterImage.monsterRepo, uMonsterImage.monsterRevisionRepo, constants.RevisionActionImage, &resSnapshot, monsterId)
+}
+
+// monsterImageRes is
+func monsterImageRes(imageURL uploader.ImageURL, monsterImage model.MonsterImage) model.MonsterImageRes {
+	variantTags := []string(monsterImage.VariantTags)
+	if variantTags == nil {
+		variantTags = []string{}
+	}
+	return model.MonsterImageRes{
+		ID:            monsterImage.ID,
+		ImageName:     monsterImage.ImageName,
+		ImageURL:      imageURL.Generate(monsterImage.ImageName, monsterImage.IsPrivate),
+		Caption:       monsterImage.Caption,
+		SortOrder:     monsterImage.SortOrder,
+		IsPrimary:     monsterImage.IsPrimary,
+		IsPrivate:     monsterImage.IsPrivate,
+		VariantTags:   variantTags,
+		BlurHash:      monsterImage.BlurHash,
+		DominantColor: monsterImage.DominantColor,
+	}
+}
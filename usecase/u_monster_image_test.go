@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestSetPrimaryMonsterImage(t *testing.T) {
+	db := testDbConn(t)
+	monsterRepo := repository.NewMonsterRepository(db)
+	monsterImageRepo := repository.NewMonsterImageRepository(db)
+	monsterRevisionRepo := repository.NewMonsterRevisionRepository(db)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	uMonsterImage := NewMonsterImageUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	monster, _ := testMonster(t, db, uMonster, fmt.Sprintf("Primary Image Test %d", time.Now().UnixNano()))
+
+	// add two image into monster gallery, first image become primary image
+	imageNames := []string{
+		fmt.Sprintf("primary-image-test-a-%d.png", time.Now().UnixNano()),
+		fmt.Sprintf("primary-image-test-b-%d.png", time.Now().UnixNano()),
+	}
+	var imageIds []string
+	for i := 0; i < len(imageNames); i++ {
+		res, resCode, _, err := uMonsterImage.CreateMonsterImage(context.Background(), monster.ID, model.CreateMonsterImageReq{ImageName: imageNames[i]})
+		if err != nil || resCode != http.StatusCreated {
+			t.Fatalf("failed to create monster image: %d %v", resCode, err)
+		}
+		imageIds = append(imageIds, res.ID)
+	}
+
+	// set second image as primary image
+	resCode, _, err := uMonsterImage.SetPrimaryMonsterImage(context.Background(), monster.ID, imageIds[1])
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusOK)
+
+	// test monster image is synced and every version has its revision
+	var resMonster model.Monster
+	err = db.Where(`id = ?`, monster.ID).First(&resMonster).Error
+	assert.NoError(t, err)
+	assert.Equal(t, resMonster.ImageName, imageNames[1])
+	var latestRevision model.MonsterRevision
+	err = db.Where(`monster_id = ?`, monster.ID).Order(`version DESC`).First(&latestRevision).Error
+	assert.NoError(t, err)
+	assert.Equal(t, latestRevision.Action, constants.RevisionActionImage)
+	assert.Equal(t, latestRevision.Version, resMonster.Version)
+}
+
+func TestReorderMonsterImage(t *testing.T) {
+	db := testDbConn(t)
+	monsterRepo := repository.NewMonsterRepository(db)
+	monsterImageRepo := repository.NewMonsterImageRepository(db)
+	monsterRevisionRepo := repository.NewMonsterRevisionRepository(db)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	uMonsterImage := NewMonsterImageUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	monster, _ := testMonster(t, db, uMonster, fmt.Sprintf("Reorder Image Test %d", time.Now().UnixNano()))
+
+	// add two image into monster gallery
+	var imageIds []string
+	for i := 0; i < 2; i++ {
+		res, resCode, _, err := uMonsterImage.CreateMonsterImage(context.Background(), monster.ID, model.CreateMonsterImageReq{
+			ImageName: fmt.Sprintf("reorder-image-test-%d-%d.png", i, time.Now().UnixNano()),
+		})
+		if err != nil || resCode != http.StatusCreated {
+			t.Fatalf("failed to create monster image: %d %v", resCode, err)
+		}
+		imageIds = append(imageIds, res.ID)
+	}
+
+	// reorder without every image of the gallery
+	resCode, _, err := uMonsterImage.ReorderMonsterImage(context.Background(), monster.ID, model.ReorderMonsterImageReq{ImageIds: imageIds[:1]})
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusBadRequest)
+
+	// reorder with every image of the gallery
+	resCode, _, err = uMonsterImage.ReorderMonsterImage(context.Background(), monster.ID, model.ReorderMonsterImageReq{ImageIds: []string{imageIds[1], imageIds[0]}})
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusOK)
+	var resMonsterImage model.MonsterImage
+	err = db.Where(`id = ?`, imageIds[1]).First(&resMonsterImage).Error
+	assert.NoError(t, err)
+	assert.Equal(t, resMonsterImage.SortOrder, 0)
+
+	// reorder gallery of soft deleted monster
+	err = db.Where(`id = ?`, monster.ID).Delete(&model.Monster{}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	resCode, resMessage, err := uMonsterImage.ReorderMonsterImage(context.Background(), monster.ID, model.ReorderMonsterImageReq{ImageIds: imageIds})
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusBadRequest)
+	assert.Equal(t, resMessage, "monster not found")
+}
@@ -0,0 +1,274 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/importer"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/libs/validator"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// MonsterImportUseCaseInterface is
+type MonsterImportUseCaseInterface interface {
+	ImportMonster(ctx context.Context, rows []importer.Row, archive *importer.Archive, dryRun bool) (res model.ImportMonsterRes, resCode int, resMessage string, err error)
+}
+
+type monsterImportUseCase struct {
+	ctxTimeout     time.Duration
+	imageFormat    map[string]bool
+	monsterUseCase MonsterUseCaseInterface
+	monsterRepo    repository.MonsterRepositoryInterface
+	mCategoryRepo  repository.MCategoryRepositoryInterface
+	mTypeRepo      repository.MTypeRepositoryInterface
+}
+
+func NewMonsterImportUseCase(ctxTimeout time.Duration, monsterUseCase MonsterUseCaseInterface, monsterRepo repository.MonsterRepositoryInterface, mCategoryRepo repository.MCategoryRepositoryInterface, mTypeRepo repository.MTypeRepositoryInterface) MonsterImportUseCaseInterface {
+	return &monsterImportUseCase{
+		ctxTimeout: ctxTimeout,
+		imageFormat: map[string]bool{
+			".png":  true,
+			".jpeg": true,
+			".jpg":  true,
+		},
+		monsterUseCase: monsterUseCase,
+		monsterRepo:    monsterRepo,
+		mCategoryRepo:  mCategoryRepo,
+		mTypeRepo:      mTypeRepo,
+	}
+}
+
+// ImportMonster is use case to validate and import monster of import file, every row must be valid before any monster is imported
+// dry run only report invalid row, including duplicate name and monster code against import file and existing monster
+func (uMonsterImport *monsterImportUseCase) ImportMonster(ctx context.Context, rows []importer.Row, archive *importer.Archive, dryRun bool) (res model.ImportMonsterRes, resCode int, resMessage string, err error) {
+	res.DryRun = dryRun
+	res.Total = len(rows)
+	res.Rows = []model.ImportMonsterRowRes{}
+	if len(rows) == 0 || len(rows) > constants.BulkMaxOperation {
+		return res, http.StatusBadRequest, "data input is invalid", fmt.Errorf("import file must have 1 to %d monster", constants.BulkMaxOperation)
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, uMonsterImport.ctxTimeout)
+	defer cancel()
+
+	// find all monster category and monster type, both are referenced by name
+	resMCategory, err := uMonsterImport.mCategoryRepo.GetAllMonsterCategory(lookupCtx, []string{`id, name`})
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster category", err
+	}
+	categoryId := map[string]string{}
+	for i := 0; i < len(resMCategory); i++ {
+		categoryId[strings.ToLower(resMCategory[i].Name)] = resMCategory[i].ID
+	}
+	resMType, err := uMonsterImport.mTypeRepo.GetAllMonsterType(lookupCtx, []string{`id, name`})
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get all monster type", err
+	}
+	typeId := map[string]string{}
+	for i := 0; i < len(resMType); i++ {
+		typeId[strings.ToLower(resMType[i].Name)] = resMType[i].ID
+	}
+
+	// find existing monster with same name or monster code, monster code of soft deleted monster is still used
+	var names []string
+	var monsterCodes []uint16
+	for i := 0; i < len(rows); i++ {
+		names = append(names, strings.ToLower(rows[i].Name))
+		if rows[i].MonsterCode != 0 {
+			monsterCodes = append(monsterCodes, rows[i].MonsterCode)
+		}
+	}
+	resMonster, err := uMonsterImport.monsterRepo.GetListMonsterByNameOrCode(lookupCtx, names, monsterCodes)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get existing monster", err
+	}
+	existingName := map[string]bool{}
+	existingCode := map[uint16]bool{}
+	for i := 0; i < len(resMonster); i++ {
+		if resMonster[i].DeletedAt == nil || !resMonster[i].DeletedAt.Valid {
+			existingName[strings.ToLower(resMonster[i].Name)] = true
+		}
+		existingCode[resMonster[i].MonsterCode] = true
+	}
+
+	// validate every row and map it into create monster operation
+	var reqBulk []model.BulkMonsterOperation
+	fileName := map[string]int{}
+	fileCode := map[uint16]int{}
+	for i := 0; i < len(rows); i++ {
+		rowErrors := append([]string{}, rows[i].Errors...)
+		reqMonster := model.CreateMonsterReq{
+			MonsterCode: rows[i].MonsterCode,
+			Name:        rows[i].Name,
+			Description: rows[i].Description,
+			Length:      rows[i].Length,
+			Weight:      rows[i].Weight,
+			HP:          rows[i].HP,
+			Attack:      rows[i].Attack,
+			Defends:     rows[i].Defends,
+			Speed:       rows[i].Speed,
+			IsCaught:    rows[i].IsCaught,
+		}
+
+		// monster category and monster type
+		if rows[i].Category != "" {
+			reqMonster.MonsterCategoryId = categoryId[strings.ToLower(rows[i].Category)]
+			if reqMonster.MonsterCategoryId == "" {
+				rowErrors = append(rowErrors, fmt.Sprintf("monster category %q is not found", rows[i].Category))
+			}
+		}
+		if len(rows[i].Types) == 0 {
+			rowErrors = append(rowErrors, "monster type is required")
+		}
+		for j := 0; j < len(rows[i].Types); j++ {
+			monsterTypeId := typeId[strings.ToLower(rows[i].Types[j])]
+			if monsterTypeId == "" {
+				rowErrors = append(rowErrors, fmt.Sprintf("monster type %q is not found", rows[i].Types[j]))
+				continue
+			}
+			reqMonster.MonsterTypes = append(reqMonster.MonsterTypes, monsterTypeId)
+		}
+
+		// struct validation
+		if len(rows[i].Errors) == 0 {
+			err = validator.ValidateStruct(&reqMonster)
+			if err != nil {
+				rowErrors = append(rowErrors, err.Error())
+			}
+		}
+
+		// duplicate name and monster code
+		name := strings.ToLower(rows[i].Name)
+		if name != "" {
+			if line, ok := fileName[name]; ok {
+				rowErrors = append(rowErrors, fmt.Sprintf("name is duplicate of line %d", line))
+			} else {
+				fileName[name] = rows[i].Line
+			}
+			if existingName[name] {
+				rowErrors = append(rowErrors, "name already used by existing monster")
+			}
+		}
+		if rows[i].MonsterCode != 0 {
+			if line, ok := fileCode[rows[i].MonsterCode]; ok {
+				rowErrors = append(rowErrors, fmt.Sprintf("monster code is duplicate of line %d", line))
+			} else {
+				fileCode[rows[i].MonsterCode] = rows[i].Line
+			}
+			if existingCode[rows[i].MonsterCode] {
+				rowErrors = append(rowErrors, "monster code already used")
+			}
+		}
+
+		// image of image archive
+		if rows[i].Image != "" {
+			err = uMonsterImport.imageValidation(rows[i].Image, archive)
+			if err != nil {
+				rowErrors = append(rowErrors, err.Error())
+			}
+		}
+
+		if len(rowErrors) > 0 {
+			res.Invalid++
+			res.Rows = append(res.Rows, model.ImportMonsterRowRes{
+				Line:        rows[i].Line,
+				MonsterCode: rows[i].MonsterCode,
+				Name:        rows[i].Name,
+				Errors:      rowErrors,
+			})
+			continue
+		}
+		res.Valid++
+		reqBulk = append(reqBulk, model.BulkMonsterOperation{
+			Op:        constants.BulkOperationCreate,
+			Index:     i,
+			CreateReq: reqMonster,
+		})
+	}
+
+	if dryRun {
+		return res, http.StatusOK, "import monster dry run successfully", nil
+	}
+	if res.Invalid > 0 {
+		return res, http.StatusUnprocessableEntity, "import monster is invalid, no monster is imported", fmt.Errorf("%d of %d row is invalid", res.Invalid, res.Total)
+	}
+
+	// save image of row, saved image is deleted again if import fails
+	var savedImages []string
+	deleteSavedImages := func() {
+		for i := 0; i < len(savedImages); i++ {
+			_ = uploader.DeleteImage(savedImages[i])
+		}
+	}
+	for i := 0; i < len(reqBulk); i++ {
+		row := rows[reqBulk[i].Index]
+		if row.Image == "" {
+			continue
+		}
+		data, err := archive.Read(row.Image)
+		if err != nil {
+			deleteSavedImages()
+			return res, http.StatusBadRequest, "failed to read image archive", err
+		}
+		imageName, err := uploader.SaveImageData(data, strings.ToLower(filepath.Ext(row.Image)))
+		if err != nil {
+			deleteSavedImages()
+			return res, http.StatusInternalServerError, "failed to save image", err
+		}
+		savedImages = append(savedImages, imageName)
+		reqBulk[i].CreateReq.ImageName = imageName
+		reqBulk[i].CreateReq.BlurHash, reqBulk[i].CreateReq.DominantColor, err = uploader.ImagePlaceholder(imageName)
+		if err != nil {
+			deleteSavedImages()
+			return res, http.StatusBadRequest, "image cannot be decoded", fmt.Errorf("line %d: %w", row.Line, err)
+		}
+	}
+
+	// create monster in one transaction with bulk monster
+	resBulk, resCode, resMessage, err := uMonsterImport.monsterUseCase.BulkMonster(ctx, reqBulk, false)
+	if err != nil {
+		deleteSavedImages()
+		for i := 0; i < len(resBulk); i++ {
+			if resBulk[i].Code == http.StatusFailedDependency || resBulk[i].Error == "" {
+				continue
+			}
+			row := rows[resBulk[i].Index]
+			res.Rows = append(res.Rows, model.ImportMonsterRowRes{
+				Line:        row.Line,
+				MonsterCode: row.MonsterCode,
+				Name:        row.Name,
+				Errors:      []string{fmt.Sprintf("%s: %s", resBulk[i].Message, resBulk[i].Error)},
+			})
+		}
+		return res, resCode, "import monster failed, no monster is imported", err
+	}
+	res.Imported = len(reqBulk)
+
+	return res, http.StatusCreated, "import monster successfully", nil
+}
+
+// imageValidation is function to validate image of row exist in image archive and can be decoded
+func (uMonsterImport *monsterImportUseCase) imageValidation(imageName string, archive *importer.Archive) (err error) {
+	if !uMonsterImport.imageFormat[strings.ToLower(filepath.Ext(imageName))] {
+		return fmt.Errorf("image %q format must be .png, .jpg, or .jpeg", imageName)
+	}
+	if !archive.Has(imageName) {
+		return fmt.Errorf("image %q is not found in image archive", imageName)
+	}
+	data, err := archive.Read(imageName)
+	if err != nil {
+		return err
+	}
+	err = uploader.ValidateImage(data)
+	if err != nil {
+		return fmt.Errorf("image %q cannot be decoded", imageName)
+	}
+
+	return nil
+}
@@ -0,0 +1,70 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/importer"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestImportMonster_IsCaught(t *testing.T) {
+	db := testDbConn(t)
+	monsterRepo := repository.NewMonsterRepository(db)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+	uMonsterImport := NewMonsterImportUseCase(10*time.Second, uMonster, monsterRepo, repository.NewMonsterCategory(db), repository.NewMTypeRepository(db))
+	name := fmt.Sprintf("Import Test %d", time.Now().UnixNano())
+
+	// prepare monster category and monster type
+	monsterCategory := model.MonsterCategory{Name: name}
+	err := db.Create(&monsterCategory).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	monsterType := model.MonsterType{Name: name}
+	err = db.Create(&monsterType).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		var monsterIds []string
+		db.Unscoped().Model(&model.Monster{}).Where(`name LIKE ?`, name+"%").Pluck(`id`, &monsterIds)
+		if len(monsterIds) > 0 {
+			db.Where(`monster_id IN (?)`, monsterIds).Delete(&model.MappingMonsterAndTypes{})
+			db.Where(`monster_id IN (?)`, monsterIds).Delete(&model.MonsterRevision{})
+			db.Unscoped().Where(`id IN (?)`, monsterIds).Delete(&model.Monster{})
+		}
+		db.Unscoped().Delete(&monsterType)
+		db.Unscoped().Delete(&monsterCategory)
+	})
+
+	// import caught and uncaught monster
+	rows, err := importer.Parse(importer.FormatCSV, strings.NewReader(fmt.Sprintf(
+		"name,category,types,description,length,weight,hp,attack,defends,speed,is_caught\n"+
+			"%[1]s Caught,%[1]s,%[1]s,Caught,1,1,1,1,1,1,true\n"+
+			"%[1]s Uncaught,%[1]s,%[1]s,Uncaught,1,1,1,1,1,1,false\n", name)), constants.ImportTypeSeparator)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, resCode, _, err := uMonsterImport.ImportMonster(context.Background(), rows, nil, false)
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusCreated)
+	assert.Equal(t, res.Imported, 2)
+
+	// test captured mark of import file is kept
+	isCaught := func(monsterName string) bool {
+		var monster model.Monster
+		err := db.Where(`name = ?`, monsterName).First(&monster).Error
+		assert.NoError(t, err)
+		return monster.IsCaught
+	}
+	assert.True(t, isCaught(name+" Caught"))
+	assert.False(t, isCaught(name+" Uncaught"))
+}
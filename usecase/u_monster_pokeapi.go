@@ -0,0 +1,421 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/pokeapi"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+)
+
+// MonsterPokeAPIUseCaseInterface is
+type MonsterPokeAPIUseCaseInterface interface {
+	ImportPokeAPI(ctx context.Context, pokemons []pokeapi.Pokemon, dryRun bool) (res model.PokeAPIImportRes, resCode int, resMessage string, err error)
+}
+
+type monsterPokeAPIUseCase struct {
+	ctxTimeout     time.Duration
+	monsterUseCase MonsterUseCaseInterface
+	monsterRepo    repository.MonsterRepositoryInterface
+	mCategoryRepo  repository.MCategoryRepositoryInterface
+	mTypeRepo      repository.MTypeRepositoryInterface
+}
+
+func NewMonsterPokeAPIUseCase(ctxTimeout time.Duration, monsterUseCase MonsterUseCaseInterface, monsterRepo repository.MonsterRepositoryInterface, mCategoryRepo repository.MCategoryRepositoryInterface, mTypeRepo repository.MTypeRepositoryInterface) MonsterPokeAPIUseCaseInterface {
+	return &monsterPokeAPIUseCase{
+		ctxTimeout:     ctxTimeout,
+		monsterUseCase: monsterUseCase,
+		monsterRepo:    monsterRepo,
+		mCategoryRepo:  mCategoryRepo,
+		mTypeRepo:      mTypeRepo,
+	}
+}
+
+// ImportPokeAPI is use case to import pokemon of PokeAPI dataset as monster, type as monster type and genus as monster category
+// every row is keyed by its external id so re-import only updates what changed, existing row with the same name is linked instead of duplicated
+// height is converted into length in meter and weight into weight in whole kilogram, soft deleted monster is skipped
+func (uMonsterPokeAPI *monsterPokeAPIUseCase) ImportPokeAPI(ctx context.Context, pokemons []pokeapi.Pokemon, dryRun bool) (res model.PokeAPIImportRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterPokeAPI.ctxTimeout)
+	defer cancel()
+
+	res.DryRun = dryRun
+	res.Total = len(pokemons)
+	res.Failed = []model.PokeAPIImportFailure{}
+	if len(pokemons) == 0 {
+		return res, http.StatusBadRequest, "data input is invalid", errors.New("dataset has no pokemon")
+	}
+
+	// sync monster type and monster category
+	typeId, err := uMonsterPokeAPI.syncMonsterType(ctx, pokemons, dryRun, &res)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to sync monster type", err
+	}
+	categoryId, err := uMonsterPokeAPI.syncMonsterCategory(ctx, pokemons, dryRun, &res)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to sync monster category", err
+	}
+
+	// find existing monster by external id or name, and monster code which is already used
+	var externalIds, names []string
+	var monsterCodes []uint16
+	for i := 0; i < len(pokemons); i++ {
+		externalIds = append(externalIds, pokeapi.PokemonExternalId(pokemons[i].ID))
+		names = append(names, strings.ToLower(pokemons[i].Name))
+		if pokemons[i].ID <= math.MaxUint16 {
+			monsterCodes = append(monsterCodes, uint16(pokemons[i].ID))
+		}
+	}
+	resMonster, err := uMonsterPokeAPI.monsterRepo.GetListMonsterByExternalIdOrName(ctx, externalIds, names)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get existing monster", err
+	}
+	monsterByExternalId := map[string]model.Monster{}
+	monsterByName := map[string]model.Monster{}
+	for i := 0; i < len(resMonster); i++ {
+		if resMonster[i].ExternalId != nil {
+			monsterByExternalId[*resMonster[i].ExternalId] = resMonster[i]
+		} else if resMonster[i].DeletedAt == nil || !resMonster[i].DeletedAt.Valid {
+			monsterByName[strings.ToLower(resMonster[i].Name)] = resMonster[i]
+		}
+	}
+	resMonsterCode, err := uMonsterPokeAPI.monsterRepo.GetListMonsterByNameOrCode(ctx, nil, monsterCodes)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get existing monster", err
+	}
+	usedCode := map[uint16]bool{}
+	for i := 0; i < len(resMonsterCode); i++ {
+		usedCode[resMonsterCode[i].MonsterCode] = true
+	}
+
+	// map pokemon into create or update monster operation
+	var reqBulk []model.BulkMonsterOperation
+	for i := 0; i < len(pokemons); i++ {
+		pokemon := pokemons[i]
+		externalId := pokeapi.PokemonExternalId(pokemon.ID)
+		if pokemon.Genus == "" {
+			res.Failed = append(res.Failed, model.PokeAPIImportFailure{
+				ExternalId: externalId,
+				Name:       pokemon.Name,
+				Code:       http.StatusBadRequest,
+				Message:    "data input is invalid",
+				Error:      "pokemon has no english genus",
+			})
+			continue
+		}
+
+		// desired monster of pokemon
+		description := pokemon.Description
+		length := pokeapi.LengthMeter(pokemon.Height)
+		weight := pokeapi.WeightKilogram(pokemon.Weight)
+		hp, attack, defends, speed := pokeAPIStat(pokemon.HP), pokeAPIStat(pokemon.Attack), pokeAPIStat(pokemon.Defense), pokeAPIStat(pokemon.Speed)
+		monsterCategoryId := categoryId[pokeapi.GenusExternalId(pokemon.Genus)]
+		var monsterTypes []string
+		for j := 0; j < len(pokemon.Types); j++ {
+			monsterTypes = append(monsterTypes, typeId[pokemon.Types[j].ID])
+		}
+
+		// new monster keeps pokemon id as monster code when it is free
+		resExisting, ok := monsterByExternalId[externalId]
+		isLinked := false
+		if !ok {
+			resExisting, ok = monsterByName[strings.ToLower(pokemon.Name)]
+			isLinked = ok
+			delete(monsterByName, strings.ToLower(pokemon.Name))
+		}
+		if !ok {
+			reqMonster := model.CreateMonsterReq{
+				Name:              pokemon.Name,
+				MonsterCategoryId: monsterCategoryId,
+				MonsterTypes:      monsterTypes,
+				Description:       description,
+				Length:            length,
+				Weight:            weight,
+				HP:                hp,
+				Attack:            attack,
+				Defends:           defends,
+				Speed:             speed,
+				ExternalId:        &externalId,
+			}
+			if pokemon.ID <= math.MaxUint16 && !usedCode[uint16(pokemon.ID)] {
+				reqMonster.MonsterCode = uint16(pokemon.ID)
+				usedCode[uint16(pokemon.ID)] = true
+			}
+			reqBulk = append(reqBulk, model.BulkMonsterOperation{
+				Op:        constants.BulkOperationCreate,
+				Index:     i,
+				CreateReq: reqMonster,
+			})
+			continue
+		}
+		if resExisting.DeletedAt != nil && resExisting.DeletedAt.Valid {
+			res.MonsterSkipped++
+			continue
+		}
+
+		// existing monster with same name is linked to pokemon by its external id
+		if isLinked {
+			res.MonsterLinked++
+			if !dryRun {
+				err = uMonsterPokeAPI.monsterRepo.UpdateMonster(nil, ctx, map[string]interface{}{
+					"value": map[string]interface{}{
+						"external_id": externalId,
+					},
+					"whereParams": map[string]interface{}{
+						"default": map[string]interface{}{
+							"id = ?": resExisting.ID,
+						},
+					},
+				})
+				if err != nil {
+					return res, http.StatusInternalServerError, "failed to link monster", err
+				}
+			}
+		}
+
+		// existing monster is only updated when it differs
+		var existingTypes []string
+		for j := 0; j < len(resExisting.MonsterTypes); j++ {
+			existingTypes = append(existingTypes, resExisting.MonsterTypes[j].ID)
+		}
+		if resExisting.Name == pokemon.Name && resExisting.MonsterCategoryId == monsterCategoryId && resExisting.Description == description &&
+			resExisting.Length == length && resExisting.Weight == weight && resExisting.HP == hp && resExisting.Attack == attack &&
+			resExisting.Defends == defends && resExisting.Speed == speed && isSameSet(existingTypes, monsterTypes) {
+			res.MonsterUnchanged++
+			continue
+		}
+		isCaught := resExisting.IsCaught
+		reqBulk = append(reqBulk, model.BulkMonsterOperation{
+			Op:      constants.BulkOperationUpdate,
+			Index:   i,
+			ID:      resExisting.ID,
+			Version: resExisting.Version,
+			UpdateReq: model.UpdateMonsterReq{
+				Name:              pokemon.Name,
+				MonsterCategoryId: monsterCategoryId,
+				MonsterTypes:      monsterTypes,
+				Description:       &description,
+				Length:            &length,
+				Weight:            &weight,
+				HP:                &hp,
+				Attack:            &attack,
+				Defends:           &defends,
+				Speed:             &speed,
+				IsCaught:          &isCaught,
+			},
+		})
+	}
+
+	if dryRun {
+		for i := 0; i < len(reqBulk); i++ {
+			if reqBulk[i].Op == constants.BulkOperationCreate {
+				res.MonsterCreated++
+			} else {
+				res.MonsterUpdated++
+			}
+		}
+		return res, http.StatusOK, "import pokeapi dry run successfully", nil
+	}
+
+	// create and update monster with bulk monster, every monster has its own transaction
+	for start := 0; start < len(reqBulk); start += constants.BulkMaxOperation {
+		end := start + constants.BulkMaxOperation
+		if end > len(reqBulk) {
+			end = len(reqBulk)
+		}
+		resBulk, resCode, resMessage, err := uMonsterPokeAPI.monsterUseCase.BulkMonster(ctx, reqBulk[start:end], true)
+		if err != nil {
+			return res, resCode, resMessage, err
+		}
+		for i := 0; i < len(resBulk); i++ {
+			if resBulk[i].Code >= http.StatusOK && resBulk[i].Code < http.StatusMultipleChoices {
+				if resBulk[i].Op == constants.BulkOperationCreate {
+					res.MonsterCreated++
+				} else {
+					res.MonsterUpdated++
+				}
+				continue
+			}
+			pokemon := pokemons[resBulk[i].Index]
+			res.Failed = append(res.Failed, model.PokeAPIImportFailure{
+				ExternalId: pokeapi.PokemonExternalId(pokemon.ID),
+				Name:       pokemon.Name,
+				Code:       resBulk[i].Code,
+				Message:    resBulk[i].Message,
+				Error:      resBulk[i].Error,
+			})
+		}
+	}
+
+	if len(res.Failed) > 0 {
+		return res, http.StatusMultiStatus, fmt.Sprintf("%d of %d pokemon failed", len(res.Failed), res.Total), nil
+	}
+
+	return res, http.StatusOK, "import pokeapi successfully", nil
+}
+
+// syncMonsterType is function to find or create monster type of every PokeAPI type, type is named by its uppercase identifier
+// it returns monster type id by PokeAPI type id, monster type which is not created yet on dry run has empty id
+func (uMonsterPokeAPI *monsterPokeAPIUseCase) syncMonsterType(ctx context.Context, pokemons []pokeapi.Pokemon, dryRun bool, res *model.PokeAPIImportRes) (typeId map[int]string, err error) {
+	resMType, err := uMonsterPokeAPI.mTypeRepo.GetAllMonsterType(ctx, []string{`id, name, external_id`})
+	if err != nil {
+		return nil, err
+	}
+	byExternalId := map[string]model.MonsterType{}
+	byName := map[string]model.MonsterType{}
+	for i := 0; i < len(resMType); i++ {
+		if resMType[i].ExternalId != nil {
+			byExternalId[*resMType[i].ExternalId] = resMType[i]
+		} else {
+			byName[strings.ToLower(resMType[i].Name)] = resMType[i]
+		}
+	}
+
+	typeId = map[int]string{}
+	for _, pokeAPIType := range uniquePokeAPIType(pokemons) {
+		externalId := pokeapi.TypeExternalId(pokeAPIType.ID)
+		if resType, ok := byExternalId[externalId]; ok {
+			typeId[pokeAPIType.ID] = resType.ID
+			continue
+		}
+
+		// link monster type with the same name, or create it
+		if resType, ok := byName[strings.ToLower(pokeAPIType.Identifier)]; ok {
+			res.TypeLinked++
+			typeId[pokeAPIType.ID] = resType.ID
+			if !dryRun {
+				err = uMonsterPokeAPI.mTypeRepo.UpdateMonsterType(nil, ctx, resType.ID, map[string]interface{}{"external_id": externalId})
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		res.TypeCreated++
+		if !dryRun {
+			typeId[pokeAPIType.ID], err = uMonsterPokeAPI.mTypeRepo.CreateMonsterType(nil, ctx, model.MonsterType{
+				Name:       strings.ToUpper(pokeAPIType.Identifier),
+				ExternalId: &externalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return typeId, nil
+}
+
+// syncMonsterCategory is function to find or create monster category of every genus of PokeAPI species
+// it returns monster category id by genus external id, monster category which is not created yet on dry run has empty id
+func (uMonsterPokeAPI *monsterPokeAPIUseCase) syncMonsterCategory(ctx context.Context, pokemons []pokeapi.Pokemon, dryRun bool, res *model.PokeAPIImportRes) (categoryId map[string]string, err error) {
+	resMCategory, err := uMonsterPokeAPI.mCategoryRepo.GetAllMonsterCategory(ctx, []string{`id, name, external_id`})
+	if err != nil {
+		return nil, err
+	}
+	byExternalId := map[string]model.MonsterCategory{}
+	byName := map[string]model.MonsterCategory{}
+	for i := 0; i < len(resMCategory); i++ {
+		if resMCategory[i].ExternalId != nil {
+			byExternalId[*resMCategory[i].ExternalId] = resMCategory[i]
+		} else {
+			byName[strings.ToLower(resMCategory[i].Name)] = resMCategory[i]
+		}
+	}
+
+	categoryId = map[string]string{}
+	for i := 0; i < len(pokemons); i++ {
+		if pokemons[i].Genus == "" {
+			continue
+		}
+		externalId := pokeapi.GenusExternalId(pokemons[i].Genus)
+		if _, ok := categoryId[externalId]; ok {
+			continue
+		}
+		if resCategory, ok := byExternalId[externalId]; ok {
+			categoryId[externalId] = resCategory.ID
+			continue
+		}
+
+		// link monster category with the same name, or create it
+		if resCategory, ok := byName[strings.ToLower(pokemons[i].Genus)]; ok {
+			res.CategoryLinked++
+			categoryId[externalId] = resCategory.ID
+			if !dryRun {
+				err = uMonsterPokeAPI.mCategoryRepo.UpdateMonsterCategory(nil, ctx, resCategory.ID, map[string]interface{}{"external_id": externalId})
+				if err != nil {
+					return nil, err
+				}
+			}
+			continue
+		}
+		res.CategoryCreated++
+		categoryId[externalId] = ""
+		if !dryRun {
+			categoryId[externalId], err = uMonsterPokeAPI.mCategoryRepo.CreateMonsterCategory(nil, ctx, model.MonsterCategory{
+				Name:       pokemons[i].Genus,
+				ExternalId: &externalId,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return categoryId, nil
+}
+
+// uniquePokeAPIType is function to get every type of pokemon once, ordered by id
+func uniquePokeAPIType(pokemons []pokeapi.Pokemon) (res []pokeapi.Type) {
+	seen := map[int]bool{}
+	for i := 0; i < len(pokemons); i++ {
+		for j := 0; j < len(pokemons[i].Types); j++ {
+			if !seen[pokemons[i].Types[j].ID] {
+				seen[pokemons[i].Types[j].ID] = true
+				res = append(res, pokemons[i].Types[j])
+			}
+		}
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].ID < res[j].ID
+	})
+
+	return res
+}
+
+// pokeAPIStat is function to convert base stat of PokeAPI into monster stat
+func pokeAPIStat(stat int) uint16 {
+	if stat < 0 {
+		return 0
+	}
+	if stat > math.MaxUint16 {
+		return math.MaxUint16
+	}
+
+	return uint16(stat)
+}
+
+// isSameSet is function to compare two list of id regardless of order
+func isSameSet(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := map[string]int{}
+	for i := 0; i < len(a); i++ {
+		seen[a[i]]++
+	}
+	for i := 0; i < len(b); i++ {
+		seen[b[i]]--
+		if seen[b[i]] < 0 {
+			return false
+		}
+	}
+
+	return true
+}
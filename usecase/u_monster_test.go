@@ -0,0 +1,396 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"net/http"
+	"os"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDbConn is database connection for test which need migrated database, test is skipped if POSTGRES_TEST_DSN is not set
+func testDbConn(t *testing.T) *gorm.DB {
+	dsn := os.Getenv("POSTGRES_TEST_DSN")
+	if dsn == "" {
+		t.Skip("POSTGRES_TEST_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// testMonster is function to create monster with its monster category and two monster type for test which need migrated
+// database, every created data is removed when test ends
+func testMonster(t *testing.T, db *gorm.DB, uMonster MonsterUseCaseInterface, name string) (monster model.Monster, monsterTypes []model.MonsterType) {
+	// prepare monster category and monster type
+	monsterCategory := model.MonsterCategory{Name: name}
+	err := db.Create(&monsterCategory).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	monsterTypes = []model.MonsterType{{Name: name + " A"}, {Name: name + " B"}}
+	err = db.Create(&monsterTypes).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if monster.ID != "" {
+			db.Where(`monster_id = ?`, monster.ID).Delete(&model.MappingMonsterAndTypes{})
+			db.Where(`monster_id = ?`, monster.ID).Delete(&model.MonsterImage{})
+			db.Where(`monster_id = ?`, monster.ID).Delete(&model.MonsterRevision{})
+			db.Unscoped().Where(`id = ?`, monster.ID).Delete(&model.Monster{})
+		}
+		db.Unscoped().Delete(&monsterTypes)
+		db.Unscoped().Delete(&monsterCategory)
+	})
+
+	// create monster
+	resCode, _, err := uMonster.CreateMonster(context.Background(), model.CreateMonsterReq{
+		Name:              name,
+		MonsterCategoryId: monsterCategory.ID,
+		MonsterTypes:      []string{monsterTypes[0].ID, monsterTypes[1].ID},
+		Description:       name,
+		Length:            1,
+		Weight:            1,
+		HP:                1,
+		Attack:            1,
+		Defends:           1,
+		Speed:             1,
+	})
+	if err != nil || resCode != http.StatusCreated {
+		t.Fatalf("failed to create monster: %d %v", resCode, err)
+	}
+	err = db.Unscoped().Where(`name = ?`, name).First(&monster).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return monster, monsterTypes
+}
+
+func TestCreateMonster_Concurrent(t *testing.T) {
+	db := testDbConn(t)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, repository.NewMonsterRepository(db), repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+
+	// prepare monster category
+	monsterCategory := model.MonsterCategory{Name: "Concurrency Test"}
+	err := db.Create(&monsterCategory).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	namePrefix := fmt.Sprintf("Concurrency Test %d", time.Now().UnixNano())
+	defer func() {
+		db.Unscoped().Where(`name LIKE ?`, namePrefix+"%").Delete(&model.Monster{})
+		db.Unscoped().Delete(&monsterCategory)
+	}()
+
+	// fire parallel create monster
+	total := 20
+	var wg sync.WaitGroup
+	resCodes := make([]int, total)
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			resCodes[i], _, _ = uMonster.CreateMonster(context.Background(), model.CreateMonsterReq{
+				Name:              fmt.Sprintf("%s %d", namePrefix, i),
+				MonsterCategoryId: monsterCategory.ID,
+				Description:       "Concurrency Test",
+				Length:            1,
+				Weight:            1,
+				HP:                1,
+				Attack:            1,
+				Defends:           1,
+				Speed:             1,
+			})
+		}(i)
+	}
+	wg.Wait()
+
+	// test every monster get unique monster code
+	for i := 0; i < total; i++ {
+		assert.Equal(t, resCodes[i], http.StatusCreated)
+	}
+	var monsterCodes []uint16
+	err = db.Model(&model.Monster{}).Where(`name LIKE ?`, namePrefix+"%").Pluck(`monster_code`, &monsterCodes).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.Len(t, monsterCodes, total)
+	uniqueCodes := map[uint16]bool{}
+	for i := 0; i < len(monsterCodes); i++ {
+		uniqueCodes[monsterCodes[i]] = true
+	}
+	assert.Len(t, uniqueCodes, total)
+
+	// test picked monster code which already used
+	resCode, _, err := uMonster.CreateMonster(context.Background(), model.CreateMonsterReq{
+		MonsterCode:       monsterCodes[0],
+		Name:              fmt.Sprintf("%s conflict", namePrefix),
+		MonsterCategoryId: monsterCategory.ID,
+		Description:       "Concurrency Test",
+		Length:            1,
+		Weight:            1,
+		HP:                1,
+		Attack:            1,
+		Defends:           1,
+		Speed:             1,
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusConflict)
+}
+
+// testBulkUpdateReq is function to build bulk update request which rename monster and keep the rest of it
+func testBulkUpdateReq(monster model.Monster, monsterTypes []model.MonsterType, name string) model.UpdateMonsterReq {
+	description, length, stat, isCaught := monster.Description, monster.Length, uint16(1), true
+
+	return model.UpdateMonsterReq{
+		Name:              name,
+		MonsterCategoryId: monster.MonsterCategoryId,
+		MonsterTypes:      []string{monsterTypes[0].ID},
+		Description:       &description,
+		Length:            &length,
+		Weight:            &stat,
+		HP:                &stat,
+		Attack:            &stat,
+		Defends:           &stat,
+		Speed:             &stat,
+		IsCaught:          &isCaught,
+	}
+}
+
+func TestBulkMonster_AllOrNothing(t *testing.T) {
+	db := testDbConn(t)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, repository.NewMonsterRepository(db), repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+	name := fmt.Sprintf("Bulk Test %d", time.Now().UnixNano())
+	monster, monsterTypes := testMonster(t, db, uMonster, name)
+
+	// update is applied first, then delete of unknown monster fails and the rest is not applied
+	req := []model.BulkMonsterOperation{
+		{Index: 0, Op: constants.BulkOperationUpdate, ID: monster.ID, Version: monster.Version, UpdateReq: testBulkUpdateReq(monster, monsterTypes, name+" updated")},
+		{Index: 1, Op: constants.BulkOperationDelete, ID: uuid.NewString(), Version: 1},
+		{Index: 2, Op: constants.BulkOperationDelete, ID: monster.ID, Version: monster.Version + 1},
+	}
+	res, resCode, _, err := uMonster.BulkMonster(context.Background(), req, false)
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusBadRequest)
+	assert.Len(t, res, 3)
+	assert.Equal(t, res[0].Code, http.StatusFailedDependency)
+	assert.Equal(t, res[0].Message, "operation is rolled back")
+	assert.Equal(t, res[1].Code, http.StatusBadRequest)
+	assert.Equal(t, res[2].Code, http.StatusFailedDependency)
+	assert.Equal(t, res[2].Message, "operation is not applied")
+
+	// test update before the failure is rolled back
+	var resMonster model.Monster
+	err = db.Unscoped().Where(`id = ?`, monster.ID).First(&resMonster).Error
+	assert.NoError(t, err)
+	assert.Equal(t, resMonster.Name, name)
+	assert.Equal(t, resMonster.Version, monster.Version)
+	var revisionCount int64
+	err = db.Model(&model.MonsterRevision{}).Where(`monster_id = ?`, monster.ID).Count(&revisionCount).Error
+	assert.NoError(t, err)
+	assert.Equal(t, revisionCount, int64(1))
+}
+
+func TestBulkMonster_SameMonster(t *testing.T) {
+	db := testDbConn(t)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, repository.NewMonsterRepository(db), repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+	name := fmt.Sprintf("Bulk Test %d", time.Now().UnixNano())
+	monster, monsterTypes := testMonster(t, db, uMonster, name)
+
+	// second operation uses version of the first one, which is only visible inside bulk transaction
+	req := []model.BulkMonsterOperation{
+		{Index: 0, Op: constants.BulkOperationUpdate, ID: monster.ID, Version: monster.Version, UpdateReq: testBulkUpdateReq(monster, monsterTypes, name+" first")},
+		{Index: 1, Op: constants.BulkOperationUpdate, ID: monster.ID, Version: monster.Version + 1, UpdateReq: testBulkUpdateReq(monster, monsterTypes, name+" second")},
+		{Index: 2, Op: constants.BulkOperationDelete, ID: monster.ID, Version: monster.Version + 2},
+	}
+	res, resCode, _, err := uMonster.BulkMonster(context.Background(), req, false)
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusOK)
+	assert.Len(t, res, 3)
+	for i := 0; i < len(res); i++ {
+		assert.Equal(t, res[i].Code, http.StatusOK)
+	}
+
+	// test every operation is applied in order
+	var resMonster model.Monster
+	err = db.Unscoped().Where(`id = ?`, monster.ID).First(&resMonster).Error
+	assert.NoError(t, err)
+	assert.Equal(t, resMonster.Name, name+" second")
+	assert.Equal(t, resMonster.Version, monster.Version+3)
+	assert.True(t, resMonster.DeletedAt != nil && resMonster.DeletedAt.Valid)
+}
+
+func TestBulkMonster_ContinueOnError(t *testing.T) {
+	db := testDbConn(t)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, repository.NewMonsterRepository(db), repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+	name := fmt.Sprintf("Bulk Test %d", time.Now().UnixNano())
+	monsterUpdated, monsterTypes := testMonster(t, db, uMonster, name+" A")
+	monsterDeleted, _ := testMonster(t, db, uMonster, name+" B")
+
+	// failed operation does not stop the rest
+	req := []model.BulkMonsterOperation{
+		{Index: 0, Op: constants.BulkOperationUpdate, ID: monsterUpdated.ID, Version: monsterUpdated.Version, UpdateReq: testBulkUpdateReq(monsterUpdated, monsterTypes, name+" A updated")},
+		{Index: 1, Op: constants.BulkOperationDelete, ID: uuid.NewString(), Version: 1},
+		{Index: 2, Op: constants.BulkOperationDelete, ID: monsterDeleted.ID, Version: monsterDeleted.Version},
+		{Index: 3, Op: constants.BulkOperationDelete, InvalidReason: "monster id must be uuid"},
+	}
+	res, resCode, _, err := uMonster.BulkMonster(context.Background(), req, true)
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusMultiStatus)
+	assert.Len(t, res, 4)
+	for i := 0; i < len(res); i++ {
+		assert.Equal(t, res[i].Index, i)
+	}
+	assert.Equal(t, res[0].Code, http.StatusOK)
+	assert.Equal(t, res[1].Code, http.StatusBadRequest)
+	assert.Equal(t, res[2].Code, http.StatusOK)
+	assert.Equal(t, res[3].Code, http.StatusBadRequest)
+
+	// test successful operation is applied
+	var resMonster model.Monster
+	err = db.Unscoped().Where(`id = ?`, monsterUpdated.ID).First(&resMonster).Error
+	assert.NoError(t, err)
+	assert.Equal(t, resMonster.Name, name+" A updated")
+	assert.Equal(t, resMonster.Version, monsterUpdated.Version+1)
+	var deletedCount int64
+	err = db.Unscoped().Model(&model.Monster{}).Where(`id = ? AND deleted_at IS NOT NULL`, monsterDeleted.ID).Count(&deletedCount).Error
+	assert.NoError(t, err)
+	assert.Equal(t, deletedCount, int64(1))
+}
+
+func TestBulkMonster_StaleVersion(t *testing.T) {
+	db := testDbConn(t)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, repository.NewMonsterRepository(db), repository.NewMonsterImageRepository(db), repository.NewMonsterRevisionRepository(db))
+	name := fmt.Sprintf("Bulk Test %d", time.Now().UnixNano())
+	monster, monsterTypes := testMonster(t, db, uMonster, name)
+
+	// argument
+	type args struct {
+		version int
+	}
+
+	// test case
+	tests := []struct {
+		name     string
+		args     args
+		wantCode int
+	}{
+		// failed scenario: test with version which is already changed
+		{
+			name:     "Failed_With_Stale_Version",
+			args:     args{version: monster.Version + 1},
+			wantCode: http.StatusPreconditionFailed,
+		},
+		// failed scenario: test without version
+		{
+			name:     "Failed_Without_Version",
+			args:     args{version: 0},
+			wantCode: http.StatusPreconditionRequired,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := []model.BulkMonsterOperation{
+				{Index: 0, Op: constants.BulkOperationUpdate, ID: monster.ID, Version: tt.args.version, UpdateReq: testBulkUpdateReq(monster, monsterTypes, name+" stale")},
+			}
+			res, resCode, _, err := uMonster.BulkMonster(context.Background(), req, false)
+			assert.NotNil(t, err)
+			assert.Equal(t, resCode, tt.wantCode)
+			assert.Equal(t, res[0].Code, tt.wantCode)
+
+			// test monster is left untouched
+			var resMonster model.Monster
+			err = db.Unscoped().Where(`id = ?`, monster.ID).First(&resMonster).Error
+			assert.NoError(t, err)
+			assert.Equal(t, resMonster.Name, name)
+			assert.Equal(t, resMonster.Version, monster.Version)
+		})
+	}
+}
+
+func TestMonsterListSelect(t *testing.T) {
+	// argument
+	type args struct {
+		queryReq model.MonsterQueryReq
+	}
+
+	// test case
+	tests := []struct {
+		name        string
+		args        args
+		wantColumns []string
+		wantPreload map[string]interface{}
+		wantKeys    map[string]bool
+		wantErr     bool
+	}{
+		// success scenario: test without field and include
+		{
+			name:        "Success_Without_Field_And_Include",
+			args:        args{queryReq: model.MonsterQueryReq{}},
+			wantColumns: []string{"monsters.id", "monsters.monster_code", "monsters.name", "monsters.is_caught", "monsters.image_name", "monsters.monster_category_id"},
+			wantPreload: map[string]interface{}{"PrimaryMonsterImage": true, "MonsterCategory": true, "MonsterTypes": true},
+			wantKeys:    nil,
+		},
+		// success scenario: test with field only
+		{
+			name:        "Success_With_Field_Only",
+			args:        args{queryReq: model.MonsterQueryReq{Fields: []string{"name", "hp"}}},
+			wantColumns: []string{"monsters.id", "monsters.name", "monsters.hp"},
+			wantPreload: map[string]interface{}{},
+			wantKeys:    map[string]bool{"id": true, "name": true, "hp": true, "search_rank": true, "highlight": true},
+		},
+		// success scenario: test with include only
+		{
+			name:        "Success_With_Include_Only",
+			args:        args{queryReq: model.MonsterQueryReq{Include: []string{"types"}}},
+			wantColumns: []string{"monsters.id", "monsters.monster_code", "monsters.name", "monsters.is_caught", "monsters.image_name"},
+			wantPreload: map[string]interface{}{"PrimaryMonsterImage": true, "MonsterTypes": true},
+			wantKeys: map[string]bool{"id": true, "monster_code": true, "name": true, "is_caught": true, "image_name": true, "image_url": true,
+				"blur_hash": true, "dominant_color": true, "primary_image": true, "monster_types": true, "search_rank": true, "highlight": true},
+		},
+		// failed scenario: test with unknown field
+		{
+			name:    "Failed_With_Unknown_Field",
+			args:    args{queryReq: model.MonsterQueryReq{Fields: []string{"password"}}},
+			wantErr: true,
+		},
+		// failed scenario: test with evolutions include
+		{
+			name:    "Failed_With_Evolutions_Include",
+			args:    args{queryReq: model.MonsterQueryReq{Include: []string{"evolutions"}}},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotColumns, gotPreload, _, err := monsterListSelect(tt.args.queryReq)
+			assert.Equal(t, err != nil, tt.wantErr)
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, gotColumns, tt.wantColumns)
+			assert.Equal(t, gotPreload, tt.wantPreload)
+			assert.Equal(t, MonsterListKeys(tt.args.queryReq), tt.wantKeys)
+		})
+	}
+}
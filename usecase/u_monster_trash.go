@@ -0,0 +1,338 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"gorm.io/gorm"
+	"net/http"
+	"time"
+)
+
+// MonsterTrashUseCaseInterface is
+type MonsterTrashUseCaseInterface interface {
+	GetListDeletedMonster(ctx context.Context, page, perPage int) (res []model.DeletedMonsterRes, count int64, resCode int, resMessage string, err error)
+	RestoreMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error)
+	PurgeMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error)
+	PurgeExpiredMonster(ctx context.Context, retentionDays int) (res model.MonsterPurgeRes, resCode int, resMessage string, err error)
+}
+
+type monsterTrashUseCase struct {
+	ctxTimeout          time.Duration
+	monsterRepo         repository.MonsterRepositoryInterface
+	monsterImageRepo    repository.MonsterImageRepositoryInterface
+	monsterRevisionRepo repository.MonsterRevisionRepositoryInterface
+}
+
+func NewMonsterTrashUseCase(ctxTimeout time.Duration, monsterRepo repository.MonsterRepositoryInterface, monsterImageRepo repository.MonsterImageRepositoryInterface, monsterRevisionRepo repository.MonsterRevisionRepositoryInterface) MonsterTrashUseCaseInterface {
+	return &monsterTrashUseCase{
+		ctxTimeout:          ctxTimeout,
+		monsterRepo:         monsterRepo,
+		monsterImageRepo:    monsterImageRepo,
+		monsterRevisionRepo: monsterRevisionRepo,
+	}
+}
+
+// GetListDeletedMonster is use case to get list soft deleted monster
+func (uMonsterTrash *monsterTrashUseCase) GetListDeletedMonster(ctx context.Context, page, perPage int) (res []model.DeletedMonsterRes, count int64, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterTrash.ctxTimeout)
+	defer cancel()
+
+	// find list soft deleted monster
+	resMonster, count, err := uMonsterTrash.monsterRepo.GetListDeletedMonster(ctx, page, perPage)
+	if err != nil {
+		return nil, 0, http.StatusInternalServerError, "failed to get list deleted monster", err
+	}
+
+	// mapping response data
+	res = []model.DeletedMonsterRes{}
+	for i := 0; i < len(resMonster); i++ {
+		deletedMonster := model.DeletedMonsterRes{
+			ID:              resMonster[i].ID,
+			MonsterCode:     resMonster[i].MonsterCode,
+			Name:            resMonster[i].Name,
+			MonsterCategory: resMonster[i].MonsterCategory,
+			MonsterTypes:    resMonster[i].MonsterTypes,
+			ImageName:       resMonster[i].ImageName,
+			Version:         resMonster[i].Version,
+		}
+		if resMonster[i].DeletedAt != nil {
+			deletedMonster.DeletedAt = resMonster[i].DeletedAt.Time
+		}
+		res = append(res, deletedMonster)
+	}
+
+	return res, count, http.StatusOK, "get list deleted monster successfully", nil
+}
+
+// RestoreMonster is use case to restore soft deleted monster with its monster type and image, the restore is recorded as revision
+// monster type of monster which is deleted before type mapping was kept is restored from its latest revision
+func (uMonsterTrash *monsterTrashUseCase) RestoreMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterTrash.ctxTimeout)
+	defer cancel()
+
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed restore monster"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find soft deleted monster by id
+	resMonster, err := uMonsterTrash.monsterRepo.GetDeletedMonsterById(ctx, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "deleted monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to get deleted monster by id", err
+	}
+
+	// check monster image, missing image is flagged instead of blocking the restore
+	isImageMissing := false
+	if resMonster.ImageName != "" {
+		isExist, err := uploader.IsImageExist(resMonster.ImageName)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to check monster image", err
+		}
+		isImageMissing = !isExist
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterTrash.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// find monster snapshot before the change
+	resSnapshot, err := uMonsterTrash.monsterRepo.GetMonsterSnapshot(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get monster snapshot", err
+	}
+
+	// restore monster
+	err = uMonsterTrash.monsterRepo.RestoreMonster(tx, ctx, reqId, isImageMissing)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "deleted monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to restore monster", err
+	}
+
+	// restore mapping monster and monster type from latest revision if it is gone
+	if len(resSnapshot.MonsterTypes) == 0 {
+		monsterTypes, err := uMonsterTrash.revisionMonsterTypes(ctx, reqId)
+		if err != nil {
+			return http.StatusInternalServerError, "failed to get monster revision", err
+		}
+		var reqMonsterAndType []model.MappingMonsterAndTypes
+		for i := 0; i < len(monsterTypes); i++ {
+			reqMonsterAndType = append(reqMonsterAndType, model.MappingMonsterAndTypes{
+				MonsterId:     reqId,
+				MonsterTypeId: monsterTypes[i],
+			})
+		}
+		if len(reqMonsterAndType) > 0 {
+			err = uMonsterTrash.monsterRepo.CreateMappingMonsterAndType(tx, ctx, reqMonsterAndType)
+			if err != nil {
+				return http.StatusInternalServerError, "failed to restore monster type", err
+			}
+		}
+	}
+
+	// record monster revision
+	err = createMonsterRevision(tx, ctx, uMonsterTrash.monsterRepo, uMonsterTrash.monsterRevisionRepo, constants.RevisionActionRestore, &resSnapshot, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to record monster revision", err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "restore monster successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// PurgeMonster is use case to permanently delete soft deleted monster with its monster type, gallery, revision and image file
+func (uMonsterTrash *monsterTrashUseCase) PurgeMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMonsterTrash.ctxTimeout)
+	defer cancel()
+
+	return uMonsterTrash.purgeMonster(ctx, reqId)
+}
+
+// PurgeExpiredMonster is use case to permanently delete monster which is soft deleted longer than retention days
+func (uMonsterTrash *monsterTrashUseCase) PurgeExpiredMonster(ctx context.Context, retentionDays int) (res model.MonsterPurgeRes, resCode int, resMessage string, err error) {
+	res.RetentionDays = retentionDays
+	res.DeletedBefore = time.Now().AddDate(0, 0, -retentionDays)
+	res.Purged = []string{}
+	res.Failed = []model.MonsterPurgeFailure{}
+
+	// find expired monster
+	listCtx, cancel := context.WithTimeout(ctx, uMonsterTrash.ctxTimeout)
+	resMonsterId, err := uMonsterTrash.monsterRepo.GetListDeletedMonsterId(listCtx, res.DeletedBefore)
+	cancel()
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get list deleted monster", err
+	}
+	res.Total = len(resMonsterId)
+
+	// purge each monster with its own timeout, failed monster is reported and retried on next run
+	for i := 0; i < len(resMonsterId); i++ {
+		purgeCtx, cancel := context.WithTimeout(ctx, uMonsterTrash.ctxTimeout)
+		_, resMessage, err := uMonsterTrash.purgeMonster(purgeCtx, resMonsterId[i])
+		cancel()
+		if err != nil {
+			res.Failed = append(res.Failed, model.MonsterPurgeFailure{
+				ID:     resMonsterId[i],
+				Reason: fmt.Sprintf("%s: %s", resMessage, err),
+			})
+			continue
+		}
+		res.Purged = append(res.Purged, resMonsterId[i])
+	}
+
+	return res, http.StatusOK, "purge expired monster successfully", nil
+}
+
+// purgeMonster is use case to permanently delete soft deleted monster, image file is deleted after commit
+// image file which cannot be deleted is left to image garbage collector
+func (uMonsterTrash *monsterTrashUseCase) purgeMonster(ctx context.Context, reqId string) (resCode int, resMessage string, err error) {
+	var tx = &gorm.DB{}
+	defer func() {
+		if rec := recover(); rec != nil {
+			// mapping response data
+			resCode = http.StatusInternalServerError
+			resMessage = "failed purge monster"
+			err = fmt.Errorf("%v", rec)
+
+			tx.Rollback()
+		}
+	}()
+
+	// find soft deleted monster by id
+	resMonster, err := uMonsterTrash.monsterRepo.GetDeletedMonsterById(ctx, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "deleted monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to get deleted monster by id", err
+	}
+
+	// find monster gallery
+	resMonsterImage, err := uMonsterTrash.monsterImageRepo.GetListMonsterImage(nil, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to get list monster image", err
+	}
+	imageNames := map[string]bool{}
+	if resMonster.ImageName != "" {
+		imageNames[resMonster.ImageName] = true
+	}
+	for i := 0; i < len(resMonsterImage); i++ {
+		imageNames[resMonsterImage[i].ImageName] = true
+	}
+
+	// create database transaction
+	trx, resCode, err := uMonsterTrash.monsterRepo.Transaction()
+	if err != nil {
+		return resCode, "failed to create database transaction", err
+	}
+	tx = trx.Begin()
+	isCommitted := false
+	defer func() {
+		if !isCommitted {
+			tx.Rollback()
+		}
+	}()
+
+	// delete mapping monster and monster type
+	err = uMonsterTrash.monsterRepo.DeleteMappingMonsterAndType(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to delete monster type", err
+	}
+
+	// delete monster gallery
+	err = uMonsterTrash.monsterImageRepo.DeleteMonsterImageByMonsterId(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to delete monster image", err
+	}
+
+	// delete monster revision
+	err = uMonsterTrash.monsterRevisionRepo.DeleteMonsterRevision(tx, ctx, reqId)
+	if err != nil {
+		return http.StatusInternalServerError, "failed to delete monster revision", err
+	}
+
+	// hard delete monster
+	err = uMonsterTrash.monsterRepo.HardDeleteMonster(tx, ctx, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "deleted monster not found", err
+		}
+		return http.StatusInternalServerError, "failed to purge monster", err
+	}
+
+	// commit database transaction
+	err = tx.Commit().Error
+	if err != nil {
+		return http.StatusInternalServerError, "failed to commit database transaction", err
+	}
+	isCommitted = true
+
+	// delete image file
+	for imageName := range imageNames {
+		_ = uploader.DeleteImage(imageName)
+	}
+
+	// mapping response data
+	resCode = http.StatusOK
+	resMessage = "purge monster successfully"
+	err = nil
+
+	return resCode, resMessage, err
+}
+
+// revisionMonsterTypes is function to get monster type of monster from its latest revision which is not delete action
+func (uMonsterTrash *monsterTrashUseCase) revisionMonsterTypes(ctx context.Context, reqId string) (monsterTypes []string, err error) {
+	// find latest monster revision
+	resRevision, err := uMonsterTrash.monsterRevisionRepo.GetLatestMonsterRevision(ctx, reqId, constants.RevisionActionDelete)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// read monster type from revision snapshot
+	var snapshot model.MonsterSnapshot
+	err = json.Unmarshal([]byte(resRevision.Snapshot), &snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	return snapshot.MonsterTypes, nil
+}
@@ -0,0 +1,110 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/uploader"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRestoreMonster(t *testing.T) {
+	db := testDbConn(t)
+	monsterRepo := repository.NewMonsterRepository(db)
+	monsterImageRepo := repository.NewMonsterImageRepository(db)
+	monsterRevisionRepo := repository.NewMonsterRevisionRepository(db)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	uMonsterTrash := NewMonsterTrashUseCase(10*time.Second, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	monster, monsterTypes := testMonster(t, db, uMonster, fmt.Sprintf("Restore Test %d", time.Now().UnixNano()))
+
+	// restore monster which is not deleted
+	resCode, _, err := uMonsterTrash.RestoreMonster(context.Background(), monster.ID)
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusNotFound)
+
+	// delete monster, then drop its monster type so it must be rebuilt from latest revision
+	_, _, err = uMonster.DeleteMonster(context.Background(), monster.ID, monster.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Where(`monster_id = ?`, monster.ID).Delete(&model.MappingMonsterAndTypes{}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// restore monster
+	resCode, _, err = uMonsterTrash.RestoreMonster(context.Background(), monster.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusOK)
+
+	// test monster is back with its monster type and newer version
+	var restored model.Monster
+	err = db.Unscoped().Where(`id = ? AND deleted_at IS NULL`, monster.ID).First(&restored).Error
+	assert.NoError(t, err)
+	assert.Equal(t, restored.Version, monster.Version+2)
+	var restoredTypeIds []string
+	err = db.Model(&model.MappingMonsterAndTypes{}).Where(`monster_id = ?`, monster.ID).Order(`monster_type_id`).Pluck(`monster_type_id`, &restoredTypeIds).Error
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, restoredTypeIds, []string{monsterTypes[0].ID, monsterTypes[1].ID})
+
+	// test restore is recorded as the latest revision
+	var latestRevision model.MonsterRevision
+	err = db.Where(`monster_id = ?`, monster.ID).Order(`version DESC`).First(&latestRevision).Error
+	assert.NoError(t, err)
+	assert.Equal(t, latestRevision.Action, constants.RevisionActionRestore)
+}
+
+func TestPurgeMonster(t *testing.T) {
+	db := testDbConn(t)
+	monsterRepo := repository.NewMonsterRepository(db)
+	monsterImageRepo := repository.NewMonsterImageRepository(db)
+	monsterRevisionRepo := repository.NewMonsterRevisionRepository(db)
+	uMonster := NewMonsterUseCase(10*time.Second, uploader.ImageURL{}, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	uMonsterTrash := NewMonsterTrashUseCase(10*time.Second, monsterRepo, monsterImageRepo, monsterRevisionRepo)
+	monster, _ := testMonster(t, db, uMonster, fmt.Sprintf("Purge Test %d", time.Now().UnixNano()))
+
+	// prepare monster gallery
+	err := db.Create(&model.MonsterImage{
+		MonsterId:   monster.ID,
+		ImageName:   fmt.Sprintf("purge-test-%d.png", time.Now().UnixNano()),
+		VariantTags: []string{},
+	}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// purge monster which is not deleted
+	resCode, _, err := uMonsterTrash.PurgeMonster(context.Background(), monster.ID)
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusNotFound)
+
+	// delete monster, then purge it
+	_, _, err = uMonster.DeleteMonster(context.Background(), monster.ID, monster.Version)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resCode, _, err = uMonsterTrash.PurgeMonster(context.Background(), monster.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, resCode, http.StatusOK)
+
+	// test monster, monster type, gallery, and revision are gone
+	count := func(value interface{}, query string) (total int64) {
+		err := db.Unscoped().Model(value).Where(query, monster.ID).Count(&total).Error
+		assert.NoError(t, err)
+		return total
+	}
+	assert.Equal(t, count(&model.Monster{}, `id = ?`), int64(0))
+	assert.Equal(t, count(&model.MappingMonsterAndTypes{}, `monster_id = ?`), int64(0))
+	assert.Equal(t, count(&model.MonsterImage{}, `monster_id = ?`), int64(0))
+	assert.Equal(t, count(&model.MonsterRevision{}, `monster_id = ?`), int64(0))
+
+	// purge monster which is already purged
+	resCode, _, err = uMonsterTrash.PurgeMonster(context.Background(), monster.ID)
+	assert.NotNil(t, err)
+	assert.Equal(t, resCode, http.StatusNotFound)
+}
@@ -11,6 +11,7 @@ import (
 // MTypeUseCaseInterface is
 type MTypeUseCaseInterface interface {
 	GetAllMonsterType(ctx context.Context) (res []model.MonsterType, resCode int, resMessage string, err error)
+	GetMonsterTypeFingerprint(ctx context.Context) (res model.Fingerprint, resCode int, resMessage string, err error)
 }
 
 type mTypeUseCase struct {
@@ -38,3 +39,17 @@ func (uMType *mTypeUseCase) GetAllMonsterType(ctx context.Context) (res []model.
 
 	return res, http.StatusOK, "get all monster type successfully", nil
 }
+
+// GetMonsterTypeFingerprint is use case to get fingerprint of all monster type for conditional request
+func (uMType *mTypeUseCase) GetMonsterTypeFingerprint(ctx context.Context) (res model.Fingerprint, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uMType.ctxTimeout)
+	defer cancel()
+
+	// find monster type fingerprint
+	res, err = uMType.mTypeRepo.GetMonsterTypeFingerprint(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get monster type fingerprint", err
+	}
+
+	return res, http.StatusOK, "get monster type fingerprint successfully", nil
+}
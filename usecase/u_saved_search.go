@@ -0,0 +1,361 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/frianlh/pokedex-api/libs/constants"
+	"github.com/frianlh/pokedex-api/libs/filter"
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/frianlh/pokedex-api/repository"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SavedSearchUseCaseInterface is
+type SavedSearchUseCaseInterface interface {
+	CreateSavedSearch(ctx context.Context, req model.SavedSearchReq) (res model.SavedSearchRes, resCode int, resMessage string, err error)
+	GetListSavedSearch(ctx context.Context) (res []model.SavedSearchRes, resCode int, resMessage string, err error)
+	GetSavedSearchById(ctx context.Context, reqId string) (res model.SavedSearchRes, resCode int, resMessage string, err error)
+	UpdateSavedSearch(ctx context.Context, reqId string, req model.SavedSearchReq) (res model.SavedSearchRes, resCode int, resMessage string, err error)
+	DeleteSavedSearch(ctx context.Context, reqId string) (resCode int, resMessage string, err error)
+	GetSavedSearchResult(ctx context.Context, reqId string) (resSavedSearch model.SavedSearchRes, res []model.GetListMonsterRes, resCode int, resMessage string, err error)
+}
+
+type savedSearchUseCase struct {
+	ctxTimeout      time.Duration
+	monsterUseCase  MonsterUseCaseInterface
+	savedSearchRepo repository.SavedSearchRepositoryInterface
+	mCategoryRepo   repository.MCategoryRepositoryInterface
+	mTypeRepo       repository.MTypeRepositoryInterface
+}
+
+func NewSavedSearchUseCase(ctxTimeout time.Duration, monsterUseCase MonsterUseCaseInterface, savedSearchRepo repository.SavedSearchRepositoryInterface, mCategoryRepo repository.MCategoryRepositoryInterface, mTypeRepo repository.MTypeRepositoryInterface) SavedSearchUseCaseInterface {
+	return &savedSearchUseCase{
+		ctxTimeout:      ctxTimeout,
+		monsterUseCase:  monsterUseCase,
+		savedSearchRepo: savedSearchRepo,
+		mCategoryRepo:   mCategoryRepo,
+		mTypeRepo:       mTypeRepo,
+	}
+}
+
+// CreateSavedSearch is use case to save monster query of authenticated user by name
+func (uSavedSearch *savedSearchUseCase) CreateSavedSearch(ctx context.Context, req model.SavedSearchReq) (res model.SavedSearchRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uSavedSearch.ctxTimeout)
+	defer cancel()
+
+	userId := actorId(ctx)
+	if userId == nil {
+		return res, http.StatusUnauthorized, "unauthorized", errors.New("saved search requires authenticated user")
+	}
+	req.Query, err = savedSearchQueryValidation(req.Query)
+	if err != nil {
+		return res, http.StatusBadRequest, "saved search query not valid", err
+	}
+	query, err := json.Marshal(req.Query)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to create saved search", err
+	}
+
+	// create saved search
+	savedSearchId, err := uSavedSearch.savedSearchRepo.CreateSavedSearch(ctx, model.SavedSearch{
+		UserId: *userId,
+		Name:   strings.TrimSpace(req.Name),
+		Query:  string(query),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return res, http.StatusConflict, "saved search name already used", err
+		}
+		return res, http.StatusInternalServerError, "failed to create saved search", err
+	}
+
+	// find saved search by id
+	return uSavedSearch.getSavedSearchById(ctx, *userId, savedSearchId, http.StatusCreated, "create saved search successfully")
+}
+
+// GetListSavedSearch is use case to get list saved search of authenticated user, every saved search is checked for staleness
+func (uSavedSearch *savedSearchUseCase) GetListSavedSearch(ctx context.Context) (res []model.SavedSearchRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uSavedSearch.ctxTimeout)
+	defer cancel()
+
+	userId := actorId(ctx)
+	if userId == nil {
+		return nil, http.StatusUnauthorized, "unauthorized", errors.New("saved search requires authenticated user")
+	}
+
+	// find list saved search
+	resSavedSearch, err := uSavedSearch.savedSearchRepo.GetListSavedSearch(ctx, *userId)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed to get list saved search", err
+	}
+
+	// find active monster type and monster category
+	activeTypeIds, activeCategoryIds, err := uSavedSearch.getActiveReference(ctx)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed to get list saved search", err
+	}
+
+	// mapping response data
+	res = []model.SavedSearchRes{}
+	for i := 0; i < len(resSavedSearch); i++ {
+		savedSearch, err := savedSearchRes(resSavedSearch[i], activeTypeIds, activeCategoryIds)
+		if err != nil {
+			return nil, http.StatusInternalServerError, "failed to get list saved search", err
+		}
+		res = append(res, savedSearch)
+	}
+
+	return res, http.StatusOK, "get all saved search successfully", nil
+}
+
+// GetSavedSearchById is use case to get saved search of authenticated user by id
+func (uSavedSearch *savedSearchUseCase) GetSavedSearchById(ctx context.Context, reqId string) (res model.SavedSearchRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uSavedSearch.ctxTimeout)
+	defer cancel()
+
+	userId := actorId(ctx)
+	if userId == nil {
+		return res, http.StatusUnauthorized, "unauthorized", errors.New("saved search requires authenticated user")
+	}
+
+	// find saved search by id
+	return uSavedSearch.getSavedSearchById(ctx, *userId, reqId, http.StatusOK, "get saved search successfully")
+}
+
+// UpdateSavedSearch is use case to replace name and monster query of saved search of authenticated user
+func (uSavedSearch *savedSearchUseCase) UpdateSavedSearch(ctx context.Context, reqId string, req model.SavedSearchReq) (res model.SavedSearchRes, resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uSavedSearch.ctxTimeout)
+	defer cancel()
+
+	userId := actorId(ctx)
+	if userId == nil {
+		return res, http.StatusUnauthorized, "unauthorized", errors.New("saved search requires authenticated user")
+	}
+	req.Query, err = savedSearchQueryValidation(req.Query)
+	if err != nil {
+		return res, http.StatusBadRequest, "saved search query not valid", err
+	}
+	query, err := json.Marshal(req.Query)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to update saved search", err
+	}
+
+	// update saved search
+	err = uSavedSearch.savedSearchRepo.UpdateSavedSearch(ctx, *userId, reqId, map[string]interface{}{
+		"name":       strings.TrimSpace(req.Name),
+		"query":      string(query),
+		"updated_at": time.Now(),
+	})
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return res, http.StatusConflict, "saved search name already used", err
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, http.StatusNotFound, "saved search not found", err
+		}
+		return res, http.StatusInternalServerError, "failed to update saved search", err
+	}
+
+	// find saved search by id
+	return uSavedSearch.getSavedSearchById(ctx, *userId, reqId, http.StatusOK, "update saved search successfully")
+}
+
+// DeleteSavedSearch is use case to delete saved search of authenticated user
+func (uSavedSearch *savedSearchUseCase) DeleteSavedSearch(ctx context.Context, reqId string) (resCode int, resMessage string, err error) {
+	ctx, cancel := context.WithTimeout(ctx, uSavedSearch.ctxTimeout)
+	defer cancel()
+
+	userId := actorId(ctx)
+	if userId == nil {
+		return http.StatusUnauthorized, "unauthorized", errors.New("saved search requires authenticated user")
+	}
+
+	// delete saved search
+	err = uSavedSearch.savedSearchRepo.DeleteSavedSearch(ctx, *userId, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return http.StatusNotFound, "saved search not found", err
+		}
+		return http.StatusInternalServerError, "failed to delete saved search", err
+	}
+
+	return http.StatusOK, "delete saved search successfully", nil
+}
+
+// GetSavedSearchResult is use case to run saved search of authenticated user as list monster, stale saved search is not run
+// since filter of deleted monster type or monster category no longer means what it meant when it was saved
+func (uSavedSearch *savedSearchUseCase) GetSavedSearchResult(ctx context.Context, reqId string) (resSavedSearch model.SavedSearchRes, res []model.GetListMonsterRes, resCode int, resMessage string, err error) {
+	// find saved search by id
+	resSavedSearch, resCode, resMessage, err = uSavedSearch.GetSavedSearchById(ctx, reqId)
+	if err != nil {
+		return resSavedSearch, nil, resCode, resMessage, err
+	}
+	if resSavedSearch.IsStale {
+		return resSavedSearch, nil, http.StatusConflict, "saved search is stale", savedSearchStaleError(resSavedSearch.Stale)
+	}
+
+	// find list monster
+	res, resCode, resMessage, err = uSavedSearch.monsterUseCase.GetListMonster(ctx, resSavedSearch.Query)
+	if err != nil {
+		return resSavedSearch, nil, resCode, resMessage, err
+	}
+	if res == nil {
+		res = []model.GetListMonsterRes{}
+	}
+
+	return resSavedSearch, res, http.StatusOK, "get saved search result successfully", nil
+}
+
+// getSavedSearchById is function to get saved search by id with its staleness
+func (uSavedSearch *savedSearchUseCase) getSavedSearchById(ctx context.Context, userId, reqId string, successCode int, successMessage string) (res model.SavedSearchRes, resCode int, resMessage string, err error) {
+	// find saved search by id
+	resSavedSearch, err := uSavedSearch.savedSearchRepo.GetSavedSearchById(ctx, userId, reqId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return res, http.StatusNotFound, "saved search not found", err
+		}
+		return res, http.StatusInternalServerError, "failed to get saved search", err
+	}
+
+	// find active monster type and monster category
+	activeTypeIds, activeCategoryIds, err := uSavedSearch.getActiveReference(ctx)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get saved search", err
+	}
+
+	// mapping response data
+	res, err = savedSearchRes(resSavedSearch, activeTypeIds, activeCategoryIds)
+	if err != nil {
+		return res, http.StatusInternalServerError, "failed to get saved search", err
+	}
+
+	return res, successCode, successMessage, nil
+}
+
+// getActiveReference is function to get id of every monster type and monster category which is not deleted
+func (uSavedSearch *savedSearchUseCase) getActiveReference(ctx context.Context) (activeTypeIds, activeCategoryIds map[string]bool, err error) {
+	resType, err := uSavedSearch.mTypeRepo.GetAllMonsterType(ctx, []string{`id`})
+	if err != nil {
+		return nil, nil, err
+	}
+	resCategory, err := uSavedSearch.mCategoryRepo.GetAllMonsterCategory(ctx, []string{`id`})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	activeTypeIds = map[string]bool{}
+	for i := 0; i < len(resType); i++ {
+		activeTypeIds[resType[i].ID] = true
+	}
+	activeCategoryIds = map[string]bool{}
+	for i := 0; i < len(resCategory); i++ {
+		activeCategoryIds[resCategory[i].ID] = true
+	}
+
+	return activeTypeIds, activeCategoryIds, nil
+}
+
+// savedSearchRes is function to map saved search into response, monster type and monster category which is not active
+// marks the saved search as stale
+func savedSearchRes(savedSearch model.SavedSearch, activeTypeIds, activeCategoryIds map[string]bool) (res model.SavedSearchRes, err error) {
+	res = model.SavedSearchRes{
+		ID:        savedSearch.ID,
+		Name:      savedSearch.Name,
+		CreatedAt: savedSearch.CreatedAt,
+		UpdatedAt: savedSearch.UpdatedAt,
+	}
+	err = json.Unmarshal([]byte(savedSearch.Query), &res.Query)
+	if err != nil {
+		return res, err
+	}
+
+	stale := model.SavedSearchStaleRes{}
+	for i := 0; i < len(res.Query.MonsterTypeId); i++ {
+		if !activeTypeIds[res.Query.MonsterTypeId[i]] {
+			stale.MonsterTypeId = append(stale.MonsterTypeId, res.Query.MonsterTypeId[i])
+		}
+	}
+	if res.Query.MonsterCategoryId != "" && !activeCategoryIds[res.Query.MonsterCategoryId] {
+		stale.MonsterCategoryId = res.Query.MonsterCategoryId
+	}
+	if len(stale.MonsterTypeId) > 0 || stale.MonsterCategoryId != "" {
+		res.IsStale = true
+		res.Stale = &stale
+	}
+
+	return res, nil
+}
+
+// savedSearchStaleError is function to describe deleted reference of stale saved search
+func savedSearchStaleError(stale *model.SavedSearchStaleRes) error {
+	var errString []string
+	if len(stale.MonsterTypeId) > 0 {
+		errString = append(errString, fmt.Sprintf("monster type %s has been deleted", strings.Join(stale.MonsterTypeId, ", ")))
+	}
+	if stale.MonsterCategoryId != "" {
+		errString = append(errString, fmt.Sprintf("monster category %s has been deleted", stale.MonsterCategoryId))
+	}
+
+	return errors.New(strings.Join(errString, ", "))
+}
+
+// savedSearchQueryValidation is function to validate monster query of saved search the same way as list monster request,
+// so saved search can always be run, uuid is saved in canonical form so it can be compared when checking staleness
+func savedSearchQueryValidation(queryReq model.MonsterQueryReq) (res model.MonsterQueryReq, err error) {
+	var errString []string
+	queryReq.Q, err = form.ParseText("q", strings.TrimSpace(queryReq.Q), constants.SearchMaxQueryLength)
+	if err != nil {
+		errString = append(errString, err.Error())
+	}
+	queryReq.Name, err = form.ParseText("name", queryReq.Name, constants.MonsterNameMaxLength)
+	if err != nil {
+		errString = append(errString, err.Error())
+	}
+	if queryReq.Filter != "" {
+		_, _, err = filter.Compile(queryReq.Filter, MonsterFilterFields)
+		if err != nil {
+			errString = append(errString, "filter "+err.Error())
+		}
+	}
+	if len(queryReq.Sort) > constants.SortMaxKeys {
+		errString = append(errString, fmt.Sprintf("sort must have at most %d keys", constants.SortMaxKeys))
+	}
+	_, err = monsterOrder(queryReq, clause.Expr{})
+	if err != nil {
+		errString = append(errString, err.Error())
+	}
+	for i := 0; i < len(queryReq.MonsterTypeId); i++ {
+		queryReq.MonsterTypeId[i], err = form.ParseUUID(fmt.Sprintf("monster_type_id[%d]", i), queryReq.MonsterTypeId[i])
+		if err != nil {
+			errString = append(errString, err.Error())
+		}
+	}
+	if queryReq.MonsterTypeMatch != "" && queryReq.MonsterTypeMatch != constants.MonsterTypeMatchAny && queryReq.MonsterTypeMatch != constants.MonsterTypeMatchAll {
+		errString = append(errString, fmt.Sprintf("monster_type_match must be %s or %s", constants.MonsterTypeMatchAny, constants.MonsterTypeMatchAll))
+	}
+	if queryReq.MonsterCategoryId != "" {
+		queryReq.MonsterCategoryId, err = form.ParseUUID("monster_category_id", queryReq.MonsterCategoryId)
+		if err != nil {
+			errString = append(errString, err.Error())
+		}
+	}
+	_, _, _, err = monsterListSelect(queryReq)
+	if err != nil {
+		errString = append(errString, err.Error())
+	}
+	if len(errString) > 0 {
+		return res, errors.New(strings.Join(errString, ", "))
+	}
+
+	return monsterQueryNormalization(queryReq), nil
+}
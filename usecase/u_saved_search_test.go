@@ -0,0 +1,176 @@
+package usecase
+
+import (
+	"encoding/json"
+	"github.com/frianlh/pokedex-api/libs/form"
+	"github.com/frianlh/pokedex-api/model"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestSavedSearchRes(t *testing.T) {
+	activeTypeIds := map[string]bool{"3906338a-1393-4ccc-84ea-fa6a75034e09": true}
+	activeCategoryIds := map[string]bool{"6eaac59e-33ef-4f99-8244-512deb0dc151": true}
+
+	// argument
+	type args struct {
+		query model.MonsterQueryReq
+	}
+
+	// test case
+	tests := []struct {
+		name      string
+		args      args
+		wantStale *model.SavedSearchStaleRes
+	}{
+		// success scenario: test with active monster type and monster category
+		{
+			name: "Success_With_Active_Reference",
+			args: args{
+				query: model.MonsterQueryReq{
+					MonsterTypeId:     []string{"3906338a-1393-4ccc-84ea-fa6a75034e09"},
+					MonsterCategoryId: "6eaac59e-33ef-4f99-8244-512deb0dc151",
+				},
+			},
+			wantStale: nil,
+		},
+		// success scenario: test without monster type and monster category
+		{
+			name: "Success_Without_Reference",
+			args: args{
+				query: model.MonsterQueryReq{Name: "Farfetch'd"},
+			},
+			wantStale: nil,
+		},
+		// success scenario: test with deleted monster type and monster category
+		{
+			name: "Success_With_Deleted_Reference",
+			args: args{
+				query: model.MonsterQueryReq{
+					MonsterTypeId:     []string{"3906338a-1393-4ccc-84ea-fa6a75034e09", "8653ceaf-36ea-4c30-b7af-8a5c6147f3a2"},
+					MonsterCategoryId: "9dfef172-34d0-454a-b92e-c8ceba59f487",
+				},
+			},
+			wantStale: &model.SavedSearchStaleRes{
+				MonsterTypeId:     []string{"8653ceaf-36ea-4c30-b7af-8a5c6147f3a2"},
+				MonsterCategoryId: "9dfef172-34d0-454a-b92e-c8ceba59f487",
+			},
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := json.Marshal(tt.args.query)
+			assert.NoError(t, err)
+			gotRes, err := savedSearchRes(model.SavedSearch{Name: "daily", Query: string(query)}, activeTypeIds, activeCategoryIds)
+			assert.NoError(t, err)
+			assert.Equal(t, gotRes.Query, tt.args.query)
+			assert.Equal(t, gotRes.IsStale, tt.wantStale != nil)
+			assert.Equal(t, gotRes.Stale, tt.wantStale)
+		})
+	}
+}
+
+func TestSavedSearchQueryValidation(t *testing.T) {
+	hpMin := float64(50)
+	isCaught := true
+
+	// argument
+	type args struct {
+		query model.MonsterQueryReq
+	}
+
+	// test case
+	tests := []struct {
+		name    string
+		args    args
+		wantRes model.MonsterQueryReq
+		wantErr bool
+	}{
+		// success scenario: test with every filter
+		{
+			name: "Success_With_Every_Filter",
+			args: args{
+				query: model.MonsterQueryReq{
+					Sort:              []form.Sort{{Field: "relevance", Desc: true}, {Field: "name"}},
+					Name:              "Mr. Mime",
+					Q:                 " psychic ",
+					Filter:            `hp > 50 AND NOT caught`,
+					MonsterTypeId:     []string{"8653CEAF-36EA-4C30-B7AF-8A5C6147F3A2", "8653ceaf-36ea-4c30-b7af-8a5c6147f3a2"},
+					MonsterCategoryId: "6EAAC59E-33EF-4F99-8244-512DEB0DC151",
+					IsCaught:          &isCaught,
+					HP:                model.RangeReq{Min: &hpMin},
+				},
+			},
+			wantRes: model.MonsterQueryReq{
+				Sort:              []form.Sort{{Field: "relevance", Desc: true}, {Field: "name"}},
+				Name:              "Mr. Mime",
+				Q:                 "psychic",
+				Filter:            `hp > 50 AND NOT caught`,
+				MonsterTypeId:     []string{"8653ceaf-36ea-4c30-b7af-8a5c6147f3a2"},
+				MonsterTypeMatch:  "any",
+				MonsterCategoryId: "6eaac59e-33ef-4f99-8244-512deb0dc151",
+				IsCaught:          &isCaught,
+				HP:                model.RangeReq{Min: &hpMin},
+			},
+		},
+		// failed scenario: test with relevance sort without search query
+		{
+			name: "Failed_With_Relevance_Sort_Without_Q",
+			args: args{
+				query: model.MonsterQueryReq{Sort: []form.Sort{{Field: "relevance"}}},
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with unknown sort field
+		{
+			name: "Failed_With_Unknown_Sort_Field",
+			args: args{
+				query: model.MonsterQueryReq{Sort: []form.Sort{{Field: "password"}}},
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with invalid filter expression
+		{
+			name: "Failed_With_Invalid_Filter",
+			args: args{
+				query: model.MonsterQueryReq{Filter: "hp >"},
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with invalid monster type id
+		{
+			name: "Failed_With_Invalid_Monster_Type_Id",
+			args: args{
+				query: model.MonsterQueryReq{MonsterTypeId: []string{"grass"}},
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with evolutions include
+		{
+			name: "Failed_With_Evolutions_Include",
+			args: args{
+				query: model.MonsterQueryReq{Include: []string{"evolutions"}},
+			},
+			wantErr: true,
+		},
+		// failed scenario: test with invalid monster type match
+		{
+			name: "Failed_With_Invalid_Monster_Type_Match",
+			args: args{
+				query: model.MonsterQueryReq{MonsterTypeMatch: "some"},
+			},
+			wantErr: true,
+		},
+	}
+
+	// test
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotRes, err := savedSearchQueryValidation(tt.args.query)
+			assert.Equal(t, err != nil, tt.wantErr)
+			assert.Equal(t, gotRes, tt.wantRes)
+		})
+	}
+}